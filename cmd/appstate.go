@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/icon-project/centralized-relay/relayer"
 	"github.com/icon-project/centralized-relay/relayer/kms"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -36,34 +38,81 @@ func (a *appState) loadConfigFile(ctx context.Context) error {
 		return nil
 	}
 
+	newCfg, err := a.readConfigFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	// save runtime configuration in app state
+	a.config = newCfg
+
+	return nil
+}
+
+// readConfigFile reads and validates the runtime configuration of the config file
+func (a *appState) readConfigFile(ctx context.Context) (*Config, error) {
 	// read the config file bytes
 	file, err := os.ReadFile(a.configPath)
 	if err != nil {
-		return fmt.Errorf("error reading file: %w", err)
+		return nil, fmt.Errorf("error reading file: %w", err)
 	}
 
 	// unmarshall them into the wrapper struct
 	cfgWrapper := &ConfigInputWrapper{}
 	err = yaml.Unmarshal(file, cfgWrapper)
 	if err != nil {
-		return fmt.Errorf("error unmarshalling config: %w", err)
+		return nil, fmt.Errorf("error unmarshalling config: %w", err)
 	}
 
 	// retrieve the runtime configuration from the disk configuration.
 	newCfg, err := cfgWrapper.RuntimeConfig(ctx, a)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// validate runtime configuration
 	if err := newCfg.validateConfig(); err != nil {
-		return fmt.Errorf("error parsing chain config: %w", err)
+		return nil, fmt.Errorf("error parsing chain config: %w", err)
 	}
+	return newCfg, nil
+}
 
-	// save runtime configuration in app state
-	a.config = newCfg
+// loadChains is the chain loader of the relayer config reload
+func (a *appState) loadChains(ctx context.Context) (map[string]*relayer.Chain, error) {
+	cfg, err := a.readConfigFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Chains.GetAll(), nil
+}
 
-	return nil
+// watchConfigFile reloads the chains of the relayer whenever the config file is modified,
+// the file is polled every interval until ctx is done
+func (a *appState) watchConfigFile(ctx context.Context, rly *relayer.Relayer, interval time.Duration) {
+	var lastMod time.Time
+	if info, err := os.Stat(a.configPath); err == nil {
+		lastMod = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(a.configPath)
+			if err != nil || !info.ModTime().After(lastMod) {
+				continue
+			}
+			lastMod = info.ModTime()
+			a.log.Info("config file changed, reloading chains", zap.String("path", a.configPath))
+			if _, err := rly.Reload(ctx); err != nil {
+				a.log.Error("failed to reload chains", zap.Error(err))
+			}
+		}
+	}
 }
 
 func (a *appState) performConfigLockingOperation(ctx context.Context, operation func() error) error {
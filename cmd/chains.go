@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 
 	"github.com/icon-project/centralized-relay/relayer"
+	"github.com/icon-project/centralized-relay/relayer/socket"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -27,6 +30,8 @@ func chainsCmd(a *appState) *cobra.Command {
 		chainsListCmd(a),
 		chainsAddCmd(a),
 		chainsDeleteCmd(a),
+		chainsStatusCmd(a),
+		chainsBalanceCmd(a),
 	)
 
 	return cmd
@@ -192,6 +197,83 @@ $ %s ch d ibc-0`, appName, appName)),
 	return cmd
 }
 
+func chainsStatusCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "status [chain_name]",
+		Aliases: []string{"s"},
+		Short:   "Returns the listener health of the chains of the running relayer",
+		Args:    withUsage(cobra.MaximumNArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s chains status
+$ %s ch s 0x2.icon`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var chain string
+			if len(args) > 0 {
+				chain = args[0]
+			}
+			client, err := socket.NewClient()
+			if err != nil {
+				return fmt.Errorf("relayer is not running: %w", err)
+			}
+			defer client.Close()
+
+			result, err := client.ChainStatus(chain)
+			if err != nil {
+				return err
+			}
+			for _, status := range result.Chains {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-10s restarts(%d) since(%s) %s\n",
+					status.Nid, status.State, status.Restarts, status.Since.Format(time.DateTime), status.LastError)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func chainsBalanceCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "balance [chain_name]",
+		Aliases: []string{"b"},
+		Short:   "Returns the relayer wallet balance of the chains of the running relayer",
+		Args:    withUsage(cobra.MaximumNArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s chains balance
+$ %s ch b 0x2.icon`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var chain string
+			if len(args) > 0 {
+				chain = args[0]
+			}
+			client, err := socket.NewClient()
+			if err != nil {
+				return fmt.Errorf("relayer is not running: %w", err)
+			}
+			defer client.Close()
+
+			result, err := client.WalletStatus(chain)
+			if err != nil {
+				return err
+			}
+			printLabels("Chain", "Wallet", "Balance", "Threshold", "Low", "Paused", "Queried")
+			for _, wallet := range result.Wallets {
+				balance, threshold, queried := "unknown", "none", "never"
+				if wallet.Balance != nil {
+					balance = wallet.Balance.String()
+					queried = wallet.QueriedAt.Format(time.DateTime)
+				}
+				if wallet.Threshold != nil {
+					threshold = wallet.Threshold.String()
+				}
+				printValues(wallet.Nid, wallet.Address, balance, threshold,
+					strconv.FormatBool(wallet.Low()), strconv.FormatBool(wallet.Paused()), queried)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
 func (c *Config) DeleteChain(chain string) {
 	delete(c.Chains, chain)
 }
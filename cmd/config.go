@@ -16,6 +16,7 @@ import (
 	"github.com/icon-project/centralized-relay/relayer/chains/icon"
 	"github.com/icon-project/centralized-relay/relayer/kms"
 	"github.com/icon-project/centralized-relay/relayer/provider"
+	"github.com/icon-project/centralized-relay/relayer/socket"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -31,6 +32,7 @@ func configCmd(a *appState) *cobra.Command {
 	cmd.AddCommand(
 		configShowCmd(a),
 		configInitCmd(a),
+		configReloadCmd(a),
 	)
 	return cmd
 }
@@ -91,6 +93,34 @@ $ %s cfg list`, appName, a.homePath, appName)),
 	return yamlFlag(a.viper, jsonFlag(a.viper, cmd))
 }
 
+// Command for reloading the chains of the running relayer from the config file
+func configReloadCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "reload",
+		Aliases: []string{"r"},
+		Short:   "Applies the chain changes of the config file to the running relayer",
+		Args:    withUsage(cobra.NoArgs),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s config reload
+$ %s cfg r`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := socket.NewClient()
+			if err != nil {
+				return fmt.Errorf("relayer is not running: %w", err)
+			}
+			defer client.Close()
+
+			result, err := client.ReloadConfig()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "added: %v\nremoved: %v\nupdated: %v\n", result.Added, result.Removed, result.Updated)
+			return nil
+		},
+	}
+	return cmd
+}
+
 // Command for initializing an empty config at the --home location
 func configInitCmd(a *appState) *cobra.Command {
 	cmd := &cobra.Command{
@@ -127,8 +157,10 @@ $ %s cfg i`, appName, a.homePath, appName)),
 
 // GlobalConfig describes any global relayer settings
 type GlobalConfig struct {
-	Timeout  string `yaml:"timeout" json:"timeout"`
-	KMSKeyID string `yaml:"kms-key-id" json:"kms-key-id"`
+	Timeout        string    `yaml:"timeout" json:"timeout"`
+	KMSKeyID       string    `yaml:"kms-key-id" json:"kms-key-id"`
+	DB             *DBConfig `yaml:"db,omitempty" json:"db,omitempty"`
+	relayer.Config `yaml:",inline"`
 }
 
 // newDefaultGlobalConfig returns a global config with defaults set
@@ -155,7 +187,13 @@ func (c *Config) Save(cfgPath string) error {
 // validateConfig is used to validate the GlobalConfig values
 func (c *Config) validateConfig() error {
 	// validating config
-	return nil
+	if c.Global == nil {
+		return nil
+	}
+	if err := c.Global.DB.Validate(); err != nil {
+		return err
+	}
+	return c.Global.Config.Validate()
 }
 
 // ConfigOutputWrapper is an intermediary type for writing the config to disk and stdout
@@ -3,8 +3,11 @@ package cmd
 import (
 	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/icon-project/centralized-relay/relayer/store"
 	"github.com/spf13/cobra"
 )
 
@@ -42,7 +45,7 @@ func contractCMD(a *appState) *cobra.Command {
 		},
 	}
 
-	feeCmd.AddCommand(state.getFee(), state.setFee(), state.claimFee())
+	feeCmd.AddCommand(state.getFee(), state.setFee(), state.claimFee(), state.feeCosts(), state.feeAudit(), state.feeClaims())
 
 	deployCmd := &cobra.Command{
 		Use:   "deploy",
@@ -139,3 +142,100 @@ func (c *contractState) claimFee() *cobra.Command {
 	}
 	return claimFeeCmd
 }
+
+// feeCosts lists the last profitability checks of the routes
+func (c *contractState) feeCosts() *cobra.Command {
+	feeCostsCmd := &cobra.Command{
+		Use:     "costs",
+		Short:   "Compare the route fees with the relay costs",
+		Aliases: []string{"cs"},
+		Example: strings.TrimSpace(fmt.Sprintf(`$ %s contract fee costs --chain [chain-nid]`, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.getSocket(c.app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			res, err := client.RouteCosts(c.chain)
+			if err != nil {
+				return err
+			}
+			printLabels("Src", "Dst", "Fee", "Cost", "Fee Value", "Cost Value", "Suggested Fee", "Profitable", "Checked At")
+			for _, rc := range res.Routes {
+				printValues(rc.Src, rc.Dst, rc.Fee, rc.Cost,
+					strconv.FormatFloat(rc.FeeValue, 'g', 6, 64),
+					strconv.FormatFloat(rc.CostValue, 'g', 6, 64),
+					rc.SuggestedFee, strconv.FormatBool(rc.Profitable), rc.CheckedAt.Format(time.RFC3339),
+				)
+			}
+			return nil
+		},
+	}
+	feeCostsCmd.Flags().StringVar(&c.chain, "chain", "", "Source chain NID [optional: all chains]")
+	return feeCostsCmd
+}
+
+// feeAudit lists the fee changes of the fee adjuster
+func (c *contractState) feeAudit() *cobra.Command {
+	feeAuditCmd := &cobra.Command{
+		Use:     "audit",
+		Short:   "List the fee changes made by the fee adjuster",
+		Aliases: []string{"au"},
+		Example: strings.TrimSpace(fmt.Sprintf(`$ %s contract fee audit --chain [chain-nid]`, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.getSocket(c.app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			pg := store.NewPagination().WithPage(c.page, c.limit)
+			res, err := client.FeeAudit(c.chain, pg)
+			if err != nil {
+				return err
+			}
+			printLabels("Src", "Dst", "Msg Fee", "New Msg Fee", "Res Fee", "New Res Fee", "Dry Run", "At", "Error")
+			for _, change := range res.Changes {
+				printValues(change.Src, change.Dst, change.OldMsgFee, change.NewMsgFee, change.OldResFee, change.NewResFee,
+					strconv.FormatBool(change.DryRun), change.At.Format(time.RFC3339), change.Error,
+				)
+			}
+			fmt.Printf("\nTotal: %d\n", res.Total)
+			return nil
+		},
+	}
+	feeAuditCmd.Flags().StringVar(&c.chain, "chain", "", "Source chain NID [optional: all chains]")
+	feeAuditCmd.Flags().UintVarP(&c.limit, "limit", "l", 10, "limit number of results")
+	feeAuditCmd.Flags().UintVarP(&c.page, "page", "p", 1, "page number")
+	return feeAuditCmd
+}
+
+// feeClaims lists the fee claims of the fee claimer
+func (c *contractState) feeClaims() *cobra.Command {
+	feeClaimsCmd := &cobra.Command{
+		Use:     "claims",
+		Short:   "List the fee claims made by the relayer",
+		Example: strings.TrimSpace(fmt.Sprintf(`$ %s contract fee claims --chain [chain-nid]`, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.getSocket(c.app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			pg := store.NewPagination().WithPage(c.page, c.limit)
+			res, err := client.FeeClaims(c.chain, pg)
+			if err != nil {
+				return err
+			}
+			printLabels("Chain", "Amount", "Treasury", "At", "Error", "Sweep Error")
+			for _, claim := range res.Claims {
+				printValues(claim.Nid, claim.Amount.String(), claim.Treasury, claim.At.Format(time.RFC3339), claim.Error, claim.SweepError)
+			}
+			fmt.Printf("\nTotal: %d\n", res.Total)
+			return nil
+		},
+	}
+	feeClaimsCmd.Flags().StringVar(&c.chain, "chain", "", "Chain NID [optional: all chains]")
+	feeClaimsCmd.Flags().UintVarP(&c.limit, "limit", "l", 10, "limit number of results")
+	feeClaimsCmd.Flags().UintVarP(&c.page, "page", "p", 1, "page number")
+	return feeClaimsCmd
+}
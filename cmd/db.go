@@ -1,16 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/icon-project/centralized-relay/relayer"
-	"github.com/icon-project/centralized-relay/relayer/lvldb"
 	"github.com/icon-project/centralized-relay/relayer/socket"
 	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +28,19 @@ type dbState struct {
 	page   uint
 	limit  uint
 	server *socket.Server
+
+	// message list filters
+	dst    string
+	event  string
+	failed bool
+	since  string
+	cursor []byte
+
+	// history filters and export
+	until  string
+	all    bool
+	format string
+	output string
 }
 
 func newDBState() *dbState {
@@ -71,7 +91,19 @@ func dbCmd(a *appState) *cobra.Command {
 	}
 	blockCmd.AddCommand(db.blockInfo(a))
 
-	dbCMD.AddCommand(messagesCmd, blockCmd, pruneCmd)
+	dlqCmd := &cobra.Command{
+		Use:   "dlq",
+		Short: "Manage the messages that exhausted their retries",
+	}
+	dlqCmd.AddCommand(db.dlqList(a), db.dlqShow(a), db.dlqRequeue(a), db.dlqPurge(a))
+
+	rejectedCmd := &cobra.Command{
+		Use:   "rejected",
+		Short: "Get the messages refused by the filter rules",
+	}
+	rejectedCmd.AddCommand(db.rejectedList(a))
+
+	dbCMD.AddCommand(messagesCmd, blockCmd, dlqCmd, rejectedCmd, pruneCmd, db.history(a), db.migrate(a))
 	return dbCMD
 }
 
@@ -89,17 +121,24 @@ func (d *dbState) messagesList(app *appState) *cobra.Command {
 				return err
 			}
 			defer client.Close()
-			pg := store.NewPagination().WithPage(d.page, d.limit)
-			messages, err := client.GetMessageList(d.chain, pg)
+			filter, err := d.messageFilter()
+			if err != nil {
+				return err
+			}
+			pg := store.NewPagination().WithPage(d.page, d.limit).WithCursor(d.cursor)
+			messages, err := client.GetMessageList(filter, pg)
 			if err != nil {
 				return err
 			}
 
-			printLabels("Sn", "Src", "Dst", "Height", "Event", "Retry")
+			printLabels("Sn", "Src", "Dst", "Height", "Event", "Retry", "Status")
 			// Print messages
 			for _, msg := range messages.Messages {
-				fmt.Printf("%-10d %-10s %-10s %-10d %-10s %-10d \n",
-					msg.Sn, msg.Src, msg.Dst, msg.MessageHeight, msg.EventType, msg.Retry)
+				fmt.Printf("%-10d %-10s %-10s %-10d %-10s %-10d %-10s \n",
+					msg.Sn, msg.Src, msg.Dst, msg.MessageHeight, msg.EventType, msg.Retry, msg.GetStatus())
+			}
+			if messages.Next != nil {
+				fmt.Printf("\nNext page: --cursor %s\n", base64.StdEncoding.EncodeToString(messages.Next))
 			}
 
 			return nil
@@ -122,7 +161,7 @@ func (d *dbState) messagesRelay(app *appState) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			result, err := client.RelayMessage(d.chain, d.height, new(big.Int).SetUint64(d.sn))
+			result, err := client.RelayMessage(d.chain, d.event, d.height, new(big.Int).SetUint64(d.sn))
 			if err != nil {
 				return err
 			}
@@ -134,6 +173,7 @@ func (d *dbState) messagesRelay(app *appState) *cobra.Command {
 	d.messageMsgIDFlag(rly, true)
 	d.messageChainFlag(rly, true)
 	d.messageHeightFlag(rly)
+	d.messageEventFlag(rly)
 	return rly
 }
 
@@ -174,6 +214,12 @@ func (d *dbState) messageMsgIDFlag(cmd *cobra.Command, markRequired bool) {
 	}
 }
 
+// messageEventFlag selects the event type of the message of --sn, it is required when the sn
+// is stored under several event types
+func (d *dbState) messageEventFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&d.event, "event", "", "event type of the message of --sn, required when several event types have the sn")
+}
+
 func (d *dbState) messageHeightFlag(cmd *cobra.Command) {
 	cmd.Flags().Uint64Var(&d.height, "height", 0, "block height")
 }
@@ -194,6 +240,13 @@ func (d *dbState) dbMessageFlagsListFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&d.chain, "chain", "c", "", "filter by chain")
 	// offset results
 	cmd.Flags().UintVarP(&d.page, "page", "p", 1, "page number")
+	// continue after the previous page
+	cmd.Flags().BytesBase64Var(&d.cursor, "cursor", nil, "cursor of the page printed by the previous list, the page number is then ignored")
+	// filters
+	cmd.Flags().StringVar(&d.dst, "dst", "", "filter by destination chain")
+	cmd.Flags().StringVar(&d.event, "event", "", "filter by event type")
+	cmd.Flags().BoolVar(&d.failed, "failed", false, "only the messages whose delivery failed at least once")
+	cmd.Flags().StringVar(&d.since, "since", "", "only the messages detected since a time (RFC3339) or a duration ago (1h30m)")
 
 	// make chain arg required
 	if err := cmd.MarkFlagRequired("chain"); err != nil {
@@ -201,6 +254,37 @@ func (d *dbState) dbMessageFlagsListFlags(cmd *cobra.Command) {
 	}
 }
 
+// messageFilter returns the message list filter of the flags
+func (d *dbState) messageFilter() (*store.MessageFilter, error) {
+	filter := &store.MessageFilter{
+		Src:       d.chain,
+		Dst:       d.dst,
+		EventType: d.event,
+		Failed:    d.failed,
+	}
+	since, err := parseTimeFlag("since", d.since)
+	if err != nil {
+		return nil, err
+	}
+	filter.Since = since
+	return filter, nil
+}
+
+// parseTimeFlag parses a time flag given as an RFC3339 time or a duration ago, the zero time when it is empty
+func parseTimeFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ago, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-ago), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q, expected an RFC3339 time or a duration", name, value)
+	}
+	return t, nil
+}
+
 func (d *dbState) blockInfo(app *appState) *cobra.Command {
 	block := &cobra.Command{
 		Use:     "view",
@@ -256,9 +340,358 @@ func (d *dbState) revertMessage(app *appState) *cobra.Command {
 	return revert
 }
 
+func (d *dbState) dlqList(app *appState) *cobra.Command {
+	list := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List messages in the dead letter queue",
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return d.closeSocket()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := d.getSocket(app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			pg := store.NewPagination().WithPage(d.page, d.limit)
+			result, err := client.DLQList(d.chain, pg)
+			if err != nil {
+				return err
+			}
+
+			printLabels("Sn", "Src", "Dst", "Event", "Retry", "Failed At", "Last Error")
+			for _, msg := range result.Messages {
+				fmt.Printf("%-10d %-10s %-10s %-10s %-10d %-20s %s\n",
+					msg.Sn, msg.Src, msg.Dst, msg.EventType, msg.Retry, msg.FailedAt.Format(time.DateTime), msg.LastError)
+			}
+			fmt.Printf("\nTotal: %d\n", result.Total)
+			return nil
+		},
+	}
+	list.Flags().UintVarP(&d.limit, "limit", "l", 10, "limit number of results")
+	list.Flags().UintVarP(&d.page, "page", "p", 1, "page number")
+	d.messageChainFlag(list, false)
+	return list
+}
+
+func (d *dbState) rejectedList(app *appState) *cobra.Command {
+	list := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the messages refused by the filter rules or the profitability check",
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return d.closeSocket()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := d.getSocket(app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			pg := store.NewPagination().WithPage(d.page, d.limit)
+			result, err := client.RejectedList(d.chain, pg)
+			if err != nil {
+				return err
+			}
+
+			printLabels("Sn", "Src", "Dst", "Event", "Rejected At", "Reason")
+			for _, msg := range result.Messages {
+				fmt.Printf("%-10d %-10s %-10s %-10s %-20s %s\n",
+					msg.Sn, msg.Src, msg.Dst, msg.EventType, msg.RejectedAt.Format(time.DateTime), msg.Reason)
+			}
+			fmt.Printf("\nTotal: %d\n", result.Total)
+			return nil
+		},
+	}
+	list.Flags().UintVarP(&d.limit, "limit", "l", 10, "limit number of results")
+	list.Flags().UintVarP(&d.page, "page", "p", 1, "page number")
+	d.messageChainFlag(list, false)
+	return list
+}
+
+func (d *dbState) dlqShow(app *appState) *cobra.Command {
+	show := &cobra.Command{
+		Use:   "show",
+		Short: "Show a message in the dead letter queue with its attempt history",
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return d.closeSocket()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := d.getSocket(app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			result, err := client.DLQShow(d.chain, d.event, new(big.Int).SetUint64(d.sn))
+			if err != nil {
+				return err
+			}
+			printLabels("Sn", "Src", "Dst", "Height", "Event", "Retry")
+			printValues(result.Sn.Uint64(), result.Src, result.Dst, result.MessageHeight, result.EventType, uint64(result.Retry))
+			fmt.Printf("\nFailed at:  %s\n", result.FailedAt.Format(time.DateTime))
+			fmt.Printf("Last error: %s\n\n", result.LastError)
+
+			printLabels("Attempt", "Time", "Tx Hash", "Error")
+			for _, attempt := range result.Attempts {
+				fmt.Printf("%-10d %-20s %-10s %s\n", attempt.Retry, attempt.Time.Format(time.DateTime), attempt.TxHash, attempt.Error)
+			}
+			return nil
+		},
+	}
+	d.messageMsgIDFlag(show, true)
+	d.messageChainFlag(show, true)
+	d.messageEventFlag(show)
+	return show
+}
+
+func (d *dbState) dlqRequeue(app *appState) *cobra.Command {
+	requeue := &cobra.Command{
+		Use:   "requeue",
+		Short: "Move a message from the dead letter queue back to the relay",
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return d.closeSocket()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := d.getSocket(app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			result, err := client.DLQRequeue(d.chain, d.event, new(big.Int).SetUint64(d.sn))
+			if err != nil {
+				return err
+			}
+			printLabels("Sn", "Src", "Dst", "Height", "Event", "Status")
+			printValues(result.Sn.Uint64(), result.Src, result.Dst, result.MessageHeight, result.EventType, string(result.GetStatus()))
+			return nil
+		},
+	}
+	d.messageMsgIDFlag(requeue, true)
+	d.messageChainFlag(requeue, true)
+	d.messageEventFlag(requeue)
+	return requeue
+}
+
+func (d *dbState) dlqPurge(app *appState) *cobra.Command {
+	purge := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete messages from the dead letter queue",
+		Long:  "Delete a single message when --sn is given, otherwise every message of the chain or of all the chains.",
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return d.closeSocket()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var sn *big.Int
+			if cmd.Flags().Changed("sn") {
+				if d.chain == "" {
+					return fmt.Errorf("--chain is required with --sn")
+				}
+				sn = new(big.Int).SetUint64(d.sn)
+			} else if d.event != "" {
+				return fmt.Errorf("--event is only used with --sn")
+			}
+			client, err := d.getSocket(app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			result, err := client.DLQPurge(d.chain, d.event, sn)
+			if err != nil {
+				return err
+			}
+			printLabels("Purged")
+			printValues(result.Count)
+			return nil
+		},
+	}
+	d.messageMsgIDFlag(purge, false)
+	d.messageChainFlag(purge, false)
+	d.messageEventFlag(purge)
+	return purge
+}
+
+// history queries the records of the delivered messages, it prints a page of them
+// or exports them as csv or json
+func (d *dbState) history(app *appState) *cobra.Command {
+	history := &cobra.Command{
+		Use:     "history",
+		Aliases: []string{"hist"},
+		Short:   "Query the relay history of the delivered messages",
+		Long: "Query the relay history of the delivered messages, in the order of delivery or of sn when --chain and --sn are given. " +
+			"The records are printed as a table, or exported as csv or json with --format, every matching record with --all.",
+		Example: strings.TrimSpace(fmt.Sprintf(`$ %s db history --chain 0x2.icon --sn 120
+$ %s db history --since 720h --format csv --all --output history.csv`, appName, appName)),
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return d.closeSocket()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch d.format {
+			case "table", "csv", "json":
+			default:
+				return fmt.Errorf("invalid format %q, expected table, csv or json", d.format)
+			}
+			filter := &store.HistoryFilter{Src: d.chain, Dst: d.dst, EventType: d.event}
+			if cmd.Flags().Changed("sn") {
+				filter.Sn = new(big.Int).SetUint64(d.sn)
+			}
+			var err error
+			if filter.Since, err = parseTimeFlag("since", d.since); err != nil {
+				return err
+			}
+			if filter.Until, err = parseTimeFlag("until", d.until); err != nil {
+				return err
+			}
+
+			client, err := d.getSocket(app)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			pg := store.NewPagination().WithPage(d.page, d.limit).WithCursor(d.cursor)
+			if d.all {
+				pg = store.NewPagination().WithLimit(store.DefaultPageSize)
+			}
+			var records []*types.RelayRecord
+			var total int
+			var next []byte
+			for {
+				result, err := client.History(filter, pg)
+				if err != nil {
+					return err
+				}
+				records, total, next = append(records, result.Records...), result.Total, result.Next
+				if !d.all || next == nil {
+					break
+				}
+				pg = store.NewPagination().WithLimit(store.DefaultPageSize).WithCursor(next)
+			}
+
+			out := io.Writer(os.Stdout)
+			if d.output != "" {
+				f, err := os.Create(d.output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			switch d.format {
+			case "csv":
+				return writeHistoryCSV(out, records)
+			case "json":
+				data, err := jsoniter.MarshalIndent(records, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(out, string(data))
+				return err
+			}
+
+			fmt.Fprintf(out, "%-10s %-10s %-10s %-14s %-6s %-12s %-20s %s\n", "Sn", "Src", "Dst", "Event", "Retry", "Latency", "Delivered At", "Dst Tx Hash")
+			for _, r := range records {
+				fmt.Fprintf(out, "%-10d %-10s %-10s %-14s %-6d %-12s %-20s %s\n",
+					r.Sn, r.Src, r.Dst, r.EventType, r.Retry, r.Latency.Round(time.Millisecond), r.DeliveredAt.Format(time.DateTime), r.DstTxHash)
+			}
+			fmt.Fprintf(out, "\nTotal: %d\n", total)
+			if next != nil {
+				fmt.Fprintf(out, "Next page: --cursor %s\n", base64.StdEncoding.EncodeToString(next))
+			}
+			return nil
+		},
+	}
+	history.Flags().StringVarP(&d.chain, "chain", "c", "", "filter by source chain")
+	history.Flags().StringVar(&d.dst, "dst", "", "filter by destination chain")
+	history.Flags().StringVar(&d.event, "event", "", "filter by event type")
+	history.Flags().Uint64Var(&d.sn, "sn", 0, "filter by message sn, looked up directly with --chain")
+	history.Flags().StringVar(&d.since, "since", "", "only the messages delivered since a time (RFC3339) or a duration ago (1h30m)")
+	history.Flags().StringVar(&d.until, "until", "", "only the messages delivered before a time (RFC3339) or a duration ago (1h30m)")
+	history.Flags().UintVarP(&d.limit, "limit", "l", 10, "limit number of results")
+	history.Flags().UintVarP(&d.page, "page", "p", 1, "page number")
+	history.Flags().BytesBase64Var(&d.cursor, "cursor", nil, "cursor of the page printed by the previous query, the page number is then ignored")
+	history.Flags().BoolVar(&d.all, "all", false, "every matching record, the pagination flags are ignored")
+	history.Flags().StringVar(&d.format, "format", "table", "output format: table, csv or json")
+	history.Flags().StringVarP(&d.output, "output", "o", "", "file to write to instead of the standard output")
+	return history
+}
+
+// historyCSVHeader is the header of the csv export of the relay history
+var historyCSVHeader = []string{
+	"src", "dst", "sn", "event_type", "src_tx_hash", "src_height", "dst_tx_hash", "dst_height",
+	"gas_used", "fee", "retry", "latency_ms", "detected_at", "delivered_at",
+}
+
+// writeHistoryCSV writes the records as csv, the times in RFC3339
+func writeHistoryCSV(out io.Writer, records []*types.RelayRecord) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(historyCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		var detectedAt string
+		if !r.DetectedAt.IsZero() {
+			detectedAt = r.DetectedAt.UTC().Format(time.RFC3339Nano)
+		}
+		if err := w.Write([]string{
+			r.Src,
+			r.Dst,
+			r.Sn.String(),
+			r.EventType,
+			r.SrcTxHash,
+			strconv.FormatUint(r.SrcHeight, 10),
+			r.DstTxHash,
+			strconv.FormatUint(r.DstHeight, 10),
+			strconv.FormatUint(r.GasUsed, 10),
+			strconv.FormatUint(r.Fee, 10),
+			strconv.FormatUint(uint64(r.Retry), 10),
+			strconv.FormatInt(r.Latency.Milliseconds(), 10),
+			detectedAt,
+			r.DeliveredAt.UTC().Format(time.RFC3339Nano),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// migrate upgrades the keys of the database in place, it opens the database itself
+// as the relayer refuses to start on an outdated schema
+func (d *dbState) migrate(app *appState) *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the database to the key schema of this relayer",
+		Long:  "Upgrade the database to the key schema of this relayer, the relayer must be stopped while it runs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if client, err := socket.NewClient(); err == nil {
+				client.Close()
+				return fmt.Errorf("the relayer is running, stop it before migrating the database")
+			}
+			db, err := app.openDB(cmd.Context(), false)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			from, err := store.GetSchemaVersion(db)
+			if err != nil {
+				return err
+			}
+			count, err := relayer.MigrateStore(db)
+			if err != nil {
+				return fmt.Errorf("migrated %d records before failing: %w", count, err)
+			}
+			printLabels("From", "To", "Records")
+			printValues(from, store.SchemaVersion, count)
+			return nil
+		},
+	}
+}
+
 // getRelayer returns the relayer instance
 func (d *dbState) getRelayer(app *appState) (*relayer.Relayer, error) {
-	db, err := lvldb.NewLvlDB(app.dbPath)
+	db, err := app.openDB(context.Background(), false)
 	if err != nil {
 		return nil, err
 	}
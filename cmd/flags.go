@@ -17,6 +17,8 @@ const (
 	flagOverwriteConfig = "overwrite"
 	flagFlushInterval   = "flush-interval"
 	flagFresh           = "fresh"
+	flagShutdownTimeout = "shutdown-timeout"
+	flagWatchInterval   = "watch-interval"
 	flagFile            = "file"
 	flagConfig          = "config"
 )
@@ -29,6 +31,22 @@ func flushIntervalFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func shutdownTimeoutFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagShutdownTimeout, relayer.DefaultShutdownTimeout, "how long to wait for in-flight transactions on shutdown")
+	if err := v.BindPFlag(flagShutdownTimeout, cmd.Flags().Lookup(flagShutdownTimeout)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func watchIntervalFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagWatchInterval, relayer.DefaultConfigWatchInterval, "how frequently the config file is checked for chain changes, 0 to disable")
+	if err := v.BindPFlag(flagWatchInterval, cmd.Flags().Lookup(flagWatchInterval)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func freshFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().Bool(flagFresh, false, "whether to clear db and start fresh")
 	if err := v.BindPFlag(flagFresh, cmd.Flags().Lookup(flagFresh)); err != nil {
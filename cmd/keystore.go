@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/icon-project/centralized-relay/relayer/provider"
 	"github.com/icon-project/centralized-relay/relayer/types"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +20,8 @@ type keystoreState struct {
 	confirmPassword string
 	address         string
 	path            string
+	pool            bool
+	remove          bool
 }
 
 func newKeyStoreState() (*keystoreState, error) {
@@ -115,10 +119,16 @@ func (k *keystoreState) list(a *appState) *cobra.Command {
 					wallets[wallet] = balance
 				}
 			}
+			var pool []string
+			if cf, ok := chain.ChainProvider.Config().(provider.WalletPoolConfig); ok {
+				pool = cf.GetWallets()
+			}
 			printLabels("Wallet", "Balance")
 			for wallet, balance := range wallets {
 				if wallet == chain.ChainProvider.Config().GetWallet() {
 					wallet = "* -> " + wallet
+				} else if slices.Contains(pool, wallet) {
+					wallet = "+ -> " + wallet
 				}
 				printValues(wallet, balance.Calculate())
 			}
@@ -165,11 +175,19 @@ func (k *keystoreState) use(a *appState) *cobra.Command {
 	use := &cobra.Command{
 		Use:   "use",
 		Short: "use keystore",
+		Long:  "Use the keystore as the relayer wallet of the chain, or add it to the wallet pool signing along it with --pool",
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keystore use --chain 0xa869.fuji --address 0x...
+$ %s keystore use --chain 0xa869.fuji --address 0x... --pool
+$ %s keystore use --chain 0xa869.fuji --address 0x... --remove`, appName, appName, appName)),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			chain, err := a.config.Chains.Get(k.chain)
 			if err != nil {
 				return err
 			}
+			if k.remove {
+				return k.removeFromPool(a, chain.ChainProvider.Config())
+			}
 			kestorePath := filepath.Join(a.homePath, "keystore", k.chain, k.address)
 			if _, err := os.Stat(kestorePath); os.IsNotExist(err) {
 				return fmt.Errorf("keystore not found")
@@ -178,13 +196,16 @@ func (k *keystoreState) use(a *appState) *cobra.Command {
 				return fmt.Errorf("password not found")
 			}
 			cf := chain.ChainProvider.Config()
+			if k.pool {
+				return k.addToPool(a, cf)
+			}
 			// check if it is the same wallet
 			if cf.GetWallet() == k.address {
 				fmt.Fprintf(os.Stdout, "Wallet already configured: %s\n", k.address)
 				return nil
 			}
 			cf.SetWallet(k.address)
-			if err := a.config.Save(a.homePath); err != nil {
+			if err := a.config.Save(a.configPath); err != nil {
 				return err
 			}
 			if err := chain.ChainProvider.SetAdmin(cmd.Context(), k.address); err != nil {
@@ -196,9 +217,60 @@ func (k *keystoreState) use(a *appState) *cobra.Command {
 	}
 	k.chainFlag(use)
 	k.addressFlag(use)
+	use.Flags().BoolVar(&k.pool, "pool", false, "add the wallet to the wallet pool of the chain")
+	use.Flags().BoolVar(&k.remove, "remove", false, "remove the wallet from the wallet pool of the chain")
+	use.MarkFlagsMutuallyExclusive("pool", "remove")
 	return use
 }
 
+// walletPool returns the wallet pool config of the chain
+func walletPool(cf provider.Config) (provider.WalletPoolConfig, error) {
+	pool, ok := cf.(provider.WalletPoolConfig)
+	if !ok {
+		return nil, fmt.Errorf("wallet pool is not supported on this chain")
+	}
+	return pool, nil
+}
+
+// addToPool adds the wallet to the wallet pool of the chain
+func (k *keystoreState) addToPool(a *appState, cf provider.Config) error {
+	pool, err := walletPool(cf)
+	if err != nil {
+		return err
+	}
+	wallets := pool.GetWallets()
+	if cf.GetWallet() == k.address || slices.Contains(wallets, k.address) {
+		fmt.Fprintf(os.Stdout, "Wallet already in use: %s\n", k.address)
+		return nil
+	}
+	pool.SetWallets(append(slices.Clone(wallets), k.address))
+	if err := a.config.Save(a.configPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "Wallet added to the pool: %s\n", k.address)
+	return nil
+}
+
+// removeFromPool removes the wallet from the wallet pool of the chain
+func (k *keystoreState) removeFromPool(a *appState, cf provider.Config) error {
+	pool, err := walletPool(cf)
+	if err != nil {
+		return err
+	}
+	wallets := pool.GetWallets()
+	if !slices.Contains(wallets, k.address) {
+		return fmt.Errorf("wallet not in the pool: %s", k.address)
+	}
+	pool.SetWallets(slices.DeleteFunc(slices.Clone(wallets), func(addr string) bool {
+		return addr == k.address
+	}))
+	if err := a.config.Save(a.configPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "Wallet removed from the pool: %s\n", k.address)
+	return nil
+}
+
 // change password
 func (k *keystoreState) changePassword(a *appState) *cobra.Command {
 	changePassword := &cobra.Command{
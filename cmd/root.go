@@ -9,6 +9,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime/debug"
+	"syscall"
 	"time"
 
 	zaplogfmt "github.com/jsternberg/zap-logfmt"
@@ -50,7 +51,7 @@ func Execute() {
 	defer cancel()
 
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt) // Using signal.Notify, instead of signal.NotifyContext, in order to see details of signal.
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM) // Using signal.Notify, instead of signal.NotifyContext, in order to see details of signal.
 	go func() {
 		// Wait for interrupt signal.
 		sig := <-sigCh
@@ -7,8 +7,8 @@ import (
 	"strings"
 
 	"github.com/icon-project/centralized-relay/relayer"
-	"github.com/icon-project/centralized-relay/relayer/lvldb"
 	"github.com/icon-project/centralized-relay/relayer/socket"
+	"github.com/icon-project/centralized-relay/relayer/store"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
@@ -37,14 +37,33 @@ func startCmd(a *appState) *cobra.Command {
 				return err
 			}
 
-			db, err := lvldb.NewLvlDB(a.dbPath)
+			shutdownTimeout, err := cmd.Flags().GetDuration(flagShutdownTimeout)
 			if err != nil {
 				return err
 			}
+
+			watchInterval, err := cmd.Flags().GetDuration(flagWatchInterval)
+			if err != nil {
+				return err
+			}
+
+			db, err := a.openDB(cmd.Context(), true)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			// a shared database is written only while its lock is held
+			var lockLost <-chan struct{}
+			if locker, ok := db.(store.Locker); ok {
+				lockLost = locker.Lost()
+			}
+
 			rly, err := relayer.NewRelayer(a.log, db, chains, fresh)
 			if err != nil {
 				return fmt.Errorf("error creating new relayer %v", err)
 			}
+			rly.SetConfig(&a.config.Global.Config)
+			rly.SetChainLoader(a.loadChains)
 
 			rlyErrCh, err := rly.Start(cmd.Context(), flushInterval, fresh)
 			if err != nil {
@@ -55,13 +74,25 @@ func startCmd(a *appState) *cobra.Command {
 				return err
 			}
 			go listener.Listen()
-			defer listener.Close()
 
-			// Block until the error channel sends a message.
-			// The context being canceled will cause the relayer to stop,
-			// so we don't want to separately monitor the ctx.Done channel,
-			// because we would risk returning before the relayer cleans up.
-			if err := <-rlyErrCh; err != nil && !errors.Is(err, context.Canceled) {
+			if watchInterval > 0 {
+				go a.watchConfigFile(cmd.Context(), rly, watchInterval)
+			}
+
+			// Block until the relayer fails or the command is interrupted
+			select {
+			case err = <-rlyErrCh:
+			case <-lockLost:
+				err = store.ErrLockLost
+			case <-cmd.Context().Done():
+			}
+
+			// stop accepting socket requests, then drain the in-flight transactions
+			// before the deferred db close
+			listener.Close()
+			rly.Shutdown(shutdownTimeout)
+
+			if err != nil && !errors.Is(err, context.Canceled) {
 				a.log.Warn("Relayer start error", zap.Error(err))
 				return err
 			}
@@ -70,5 +101,7 @@ func startCmd(a *appState) *cobra.Command {
 	}
 	cmd = flushIntervalFlag(a.viper, cmd)
 	cmd = freshFlag(a.viper, cmd)
+	cmd = shutdownTimeoutFlag(a.viper, cmd)
+	cmd = watchIntervalFlag(a.viper, cmd)
 	return cmd
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/icon-project/centralized-relay/relayer/boltdb"
+	"github.com/icon-project/centralized-relay/relayer/lvldb"
+	"github.com/icon-project/centralized-relay/relayer/pebbledb"
+	"github.com/icon-project/centralized-relay/relayer/sqldb"
+	"github.com/icon-project/centralized-relay/relayer/store"
+)
+
+const (
+	BackendLevelDB  = "leveldb"
+	BackendPebble   = "pebble"
+	BackendBolt     = "bolt"
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// DBConfig selects the database backend of the relayer
+type DBConfig struct {
+	// Backend is one of leveldb, pebble, bolt, sqlite or postgres, leveldb when unset
+	Backend string `yaml:"backend,omitempty" json:"backend,omitempty"`
+	// Path is the directory or file of the embedded backends,
+	// the --db path for leveldb and the --db path suffixed with the backend for the others when unset
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// DSN is the connection string of the postgres backend
+	DSN string `yaml:"dsn,omitempty" json:"dsn,omitempty"`
+}
+
+// Validate checks the database values
+func (c *DBConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.backend() {
+	case BackendLevelDB, BackendPebble, BackendBolt, BackendSQLite:
+	case BackendPostgres:
+		if c.DSN == "" {
+			return fmt.Errorf("db dsn is required by the postgres backend")
+		}
+	default:
+		return fmt.Errorf("unsupported db backend: %s", c.Backend)
+	}
+	return nil
+}
+
+func (c *DBConfig) backend() string {
+	if c == nil || c.Backend == "" {
+		return BackendLevelDB
+	}
+	return c.Backend
+}
+
+// path returns the location of an embedded backend
+func (c *DBConfig) path(dbPath string) string {
+	switch {
+	case c != nil && c.Path != "":
+		return c.Path
+	case c.backend() == BackendLevelDB:
+		return dbPath
+	default:
+		return dbPath + "." + c.backend()
+	}
+}
+
+// openDB opens the database of the backend set in the global config and takes the lock of a database
+// shared by several relayers, wait blocks until the relayer holding it stops or ctx is done,
+// otherwise store.ErrLocked is returned
+func (a *appState) openDB(ctx context.Context, wait bool) (store.Store, error) {
+	db, err := a.newDB()
+	if err != nil {
+		return nil, err
+	}
+	locker, ok := db.(store.Locker)
+	if !ok {
+		return db, nil
+	}
+	err = locker.TryLock()
+	if errors.Is(err, store.ErrLocked) && wait {
+		a.log.Info("Database in use by another relayer, waiting for its lock")
+		err = locker.Lock(ctx)
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func (a *appState) newDB() (store.Store, error) {
+	var cfg *DBConfig
+	if a.config != nil && a.config.Global != nil {
+		cfg = a.config.Global.DB
+	}
+	switch cfg.backend() {
+	case BackendPebble:
+		return pebbledb.NewPebbleDB(cfg.path(a.dbPath))
+	case BackendBolt:
+		return boltdb.NewBoltDB(cfg.path(a.dbPath))
+	case BackendSQLite:
+		return sqldb.NewSQLDB(sqldb.DriverSQLite, "file:"+cfg.path(a.dbPath))
+	case BackendPostgres:
+		return sqldb.NewSQLDB(sqldb.DriverPostgres, cfg.DSN)
+	default:
+		return lvldb.NewLvlDB(cfg.path(a.dbPath))
+	}
+}
@@ -0,0 +1,292 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/provider"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+)
+
+// DefaultBalanceInterval is how frequently the relayer wallet balances are queried
+var DefaultBalanceInterval = time.Minute
+
+// BalanceConfig monitors the relayer wallets of every chain, the pool wallets included. A warning is
+// logged while a balance is under its threshold and the routing to a chain is paused while one of
+// its wallets cannot cover the estimated cost of a delivery, it resumes once the monitor sees the
+// wallet topped up.
+type BalanceConfig struct {
+	// Interval between two queries of the balances
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Thresholds are the balances under which a warning is logged, per nid, in the smallest denomination of the chain
+	Thresholds map[string]*big.Int `yaml:"thresholds,omitempty" json:"thresholds,omitempty"`
+}
+
+// Validate checks the balance monitor values
+func (c *BalanceConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("balance interval cannot be negative: %s", c.Interval)
+	}
+	return nil
+}
+
+func (c *BalanceConfig) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultBalanceInterval
+	}
+	return c.Interval
+}
+
+// WalletStatus is the last known balance of a relayer wallet of a chain
+type WalletStatus struct {
+	Nid     string
+	Address string
+	Balance *types.Coin
+	// Threshold is the balance under which the wallet is low, nil when none is set
+	Threshold *big.Int
+	// Needed is the estimated cost a wallet of the chain could not cover, the routing to the
+	// chain is paused while it is set
+	Needed    uint64
+	QueriedAt time.Time
+}
+
+// Low reports whether the balance is under the threshold
+func (s WalletStatus) Low() bool {
+	return s.Balance != nil && s.Threshold != nil && s.Balance.Amount.Cmp(s.Threshold) < 0
+}
+
+// Paused reports whether the routing to the chain is paused for low funds
+func (s WalletStatus) Paused() bool {
+	return s.Needed > 0
+}
+
+// chainWallet guards the wallet state of a chain runtime
+type chainWallet struct {
+	mu sync.RWMutex
+	// balances are the last queried balances by wallet address
+	balances  map[string]*types.Coin
+	needed    uint64
+	queriedAt time.Time
+}
+
+// walletAddresses returns the configured wallet of the chain followed by its pool wallets
+func (r *ChainRuntime) walletAddresses() []string {
+	addrs := []string{r.Provider.Config().GetWallet()}
+	if pool, ok := r.Provider.Config().(provider.WalletPoolConfig); ok {
+		for _, addr := range pool.GetWallets() {
+			if !slices.Contains(addrs, addr) {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	return addrs
+}
+
+// Wallets returns the last known balances of the relayer wallets of the chain
+func (r *ChainRuntime) Wallets() []WalletStatus {
+	r.wallet.mu.RLock()
+	defer r.wallet.mu.RUnlock()
+	addrs := r.walletAddresses()
+	statuses := make([]WalletStatus, 0, len(addrs))
+	for _, addr := range addrs {
+		statuses = append(statuses, WalletStatus{
+			Nid:       r.Provider.NID(),
+			Address:   addr,
+			Balance:   r.wallet.balances[addr],
+			Needed:    r.wallet.needed,
+			QueriedAt: r.wallet.queriedAt,
+		})
+	}
+	return statuses
+}
+
+// lowestBalance returns the lowest known balance of the wallets of the chain, any of them may
+// be picked for a delivery, nil before the balances are queried
+func (r *ChainRuntime) lowestBalance() *types.Coin {
+	r.wallet.mu.RLock()
+	defer r.wallet.mu.RUnlock()
+	return lowest(r.wallet.balances)
+}
+
+// paused reports whether the routing to the chain is paused for low funds
+func (r *ChainRuntime) paused() bool {
+	r.wallet.mu.RLock()
+	defer r.wallet.mu.RUnlock()
+	return r.wallet.needed > 0
+}
+
+func lowest(balances map[string]*types.Coin) *types.Coin {
+	var low *types.Coin
+	for _, balance := range balances {
+		if low == nil || balance.Amount.Cmp(low.Amount) < 0 {
+			low = balance
+		}
+	}
+	return low
+}
+
+// setBalances records the queried balances and reports whether they lift the pause of the routing
+func (r *ChainRuntime) setBalances(balances map[string]*types.Coin) bool {
+	r.wallet.mu.Lock()
+	defer r.wallet.mu.Unlock()
+	r.wallet.balances = balances
+	r.wallet.queriedAt = time.Now()
+	if r.wallet.needed > 0 && lowest(balances).Amount.Cmp(new(big.Int).SetUint64(r.wallet.needed)) >= 0 {
+		r.wallet.needed = 0
+		return true
+	}
+	return false
+}
+
+// pauseRouting records the cost the wallet cannot cover and reports whether the routing was running
+func (r *ChainRuntime) pauseRouting(needed uint64) bool {
+	r.wallet.mu.Lock()
+	defer r.wallet.mu.Unlock()
+	running := r.wallet.needed == 0
+	r.wallet.needed = needed
+	return running
+}
+
+// StartBalanceMonitor queries the relayer wallet balances every interval until ctx is done,
+// it returns right away when the balance monitor is not configured
+func (r *Relayer) StartBalanceMonitor(ctx context.Context) {
+	if r.cfg.Balance == nil {
+		return
+	}
+	ticker := time.NewTicker(r.cfg.Balance.interval())
+	defer ticker.Stop()
+
+	for {
+		for _, chain := range r.chainRuntimes() {
+			if err := r.checkBalance(ctx, chain); err != nil {
+				chain.log.Warn("failed to query wallet balance", zap.Error(err))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkBalance queries the balances of the relayer wallets of the chain, warns about the low ones
+// and resumes the routing to the chain when they all cover the cost it was paused for
+func (r *Relayer) checkBalance(ctx context.Context, chain *ChainRuntime) error {
+	nId := chain.Provider.NID()
+	threshold := r.cfg.Balance.Thresholds[nId]
+	balances := make(map[string]*types.Coin)
+	for _, addr := range chain.walletAddresses() {
+		balance, err := chain.Provider.QueryBalance(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("wallet %s: %w", addr, err)
+		}
+		if balance == nil {
+			return nil
+		}
+		balances[addr] = balance
+		walletBalance.WithLabelValues(nId, addr, balance.Denom).Set(balance.Float64())
+
+		if threshold != nil && balance.Amount.Cmp(threshold) < 0 {
+			chain.log.Warn("relayer wallet balance is low",
+				zap.String("wallet", addr),
+				zap.String("balance", balance.String()),
+				zap.Stringer("threshold", threshold),
+			)
+		}
+	}
+	if chain.setBalances(balances) {
+		chain.log.Info("relayer wallets topped up, resuming routing", zap.String("balance", lowest(balances).String()))
+		routingPaused.WithLabelValues(nId).Set(0)
+		r.resumeRouting(nId)
+	}
+	return nil
+}
+
+// canAfford reports whether every relayer wallet of dst covers the estimated cost of the message.
+// When it does not the routing to dst is paused and the message is held, without counting a
+// retry, until the balance monitor sees the wallet topped up.
+func (r *Relayer) canAfford(ctx context.Context, src, dst *ChainRuntime, m *types.RouteMessage) bool {
+	if r.cfg.Balance == nil {
+		return true
+	}
+	balance := dst.lowestBalance()
+	if balance == nil {
+		return true
+	}
+	if !dst.paused() {
+		cost, err := dst.Provider.EstimateCost(ctx, m.Message)
+		if err != nil {
+			dst.log.Debug("failed to estimate delivery cost", zap.String("src", m.Src), zap.Uint64("sn", m.Sn.Uint64()), zap.Error(err))
+			return true
+		}
+		if new(big.Int).SetUint64(cost).Cmp(balance.Amount) <= 0 {
+			return true
+		}
+		if dst.pauseRouting(cost) {
+			dst.log.Warn("relayer wallet cannot cover the delivery cost, pausing routing",
+				zap.String("balance", balance.String()),
+				zap.Uint64("cost", cost),
+			)
+			routingPaused.WithLabelValues(dst.Provider.NID()).Set(1)
+		}
+	}
+
+	m.SetNextTry(r.cfg.Balance.interval())
+	if err := r.transition(m, types.MessageStatusDetected); err == nil {
+		r.schedule(src, m)
+	}
+	return false
+}
+
+// resumeRouting schedules right away the messages held for the chain
+func (r *Relayer) resumeRouting(nId string) {
+	for _, src := range r.chainRuntimes() {
+		src.MessageCache.Range(func(m *types.RouteMessage) bool {
+			if m.Dst == nId && m.GetStatus() == types.MessageStatusDetected {
+				m.ClearNextTry()
+				r.schedule(src, m)
+			}
+			return true
+		})
+	}
+}
+
+// GetWalletStatus returns the wallet balances of the chain or of all the chains when nId is empty
+func (r *Relayer) GetWalletStatus(nId string) ([]WalletStatus, error) {
+	var chains []*ChainRuntime
+	if nId != "" {
+		chain, err := r.FindChainRuntime(nId)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, chain)
+	} else {
+		for _, chain := range r.chainRuntimes() {
+			chains = append(chains, chain)
+		}
+	}
+	statuses := make([]WalletStatus, 0, len(chains))
+	for _, chain := range chains {
+		for _, status := range chain.Wallets() {
+			if r.cfg.Balance != nil {
+				status.Threshold = r.cfg.Balance.Thresholds[status.Nid]
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	// the wallets of a chain keep their order, the configured one first
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Nid < statuses[j].Nid
+	})
+	return statuses, nil
+}
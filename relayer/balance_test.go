@@ -0,0 +1,39 @@
+package relayer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBalanceConfig(t *testing.T) {
+	data := `
+balance:
+  interval: 1m
+  thresholds:
+    0x1.icon: 100000000000000000000
+    0xa869.fuji: 1000000000000000000
+`
+	cfg := new(Config)
+	require.NoError(t, yaml.Unmarshal([]byte(data), cfg))
+	require.NoError(t, cfg.Validate())
+
+	// the thresholds of the 18 decimal chains are beyond the range of an uint64
+	threshold := cfg.Balance.Thresholds["0x1.icon"]
+	require.NotNil(t, threshold)
+	assert.Equal(t, "100000000000000000000", threshold.String())
+
+	balance := types.NewCoin("ICX", new(big.Int).Mul(big.NewInt(50), big.NewInt(1e18)))
+	status := WalletStatus{Balance: balance, Threshold: threshold}
+	assert.True(t, status.Low())
+	assert.Equal(t, 5e19, balance.Float64())
+	assert.Equal(t, "50000000000000000000icx", balance.String())
+
+	// no threshold, never low
+	status.Threshold = cfg.Balance.Thresholds["0x2.icon"]
+	assert.False(t, status.Low())
+}
@@ -0,0 +1,107 @@
+package relayer
+
+import (
+	"context"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/provider"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+)
+
+// batching returns the batching of the destination, nil when its messages are routed one by one
+func (r *Relayer) batching(dst *ChainRuntime) *BatchConfig {
+	cfg := r.cfg.Scheduler.batch(dst.Provider.NID())
+	if cfg == nil {
+		return nil
+	}
+	if router, ok := dst.Provider.(provider.BatchRouter); !ok || !router.SupportsBatch() {
+		return nil
+	}
+	return cfg
+}
+
+// collectBatch gathers the deliverable messages popped from the queue within the batch window,
+// starting with item, and routes them together. A message whose delivery failed before is routed
+// on its own so that a failing message cannot fail the batch again.
+func (r *Relayer) collectBatch(ctx context.Context, dst *ChainRuntime, q *workQueue, item *workItem, cfg *BatchConfig) {
+	var (
+		batch    []*workItem
+		deadline = time.Now().Add(cfg.window())
+	)
+	for {
+		switch {
+		case !r.deliverable(r.routeCtx, item.src, dst, item.message):
+			q.done(item)
+		case item.message.GetRetry() > 0:
+			r.RouteMessage(r.routeCtx, item.message, dst, item.src)
+			q.done(item)
+		default:
+			batch = append(batch, item)
+		}
+		if len(batch) >= cfg.Size {
+			break
+		}
+		next, ok := q.popUntil(ctx, deadline)
+		if !ok {
+			break
+		}
+		item = next
+	}
+
+	r.routeBatch(r.routeCtx, dst, batch)
+	for _, item := range batch {
+		q.done(item)
+	}
+}
+
+// routeBatch delivers the messages to dst in one transaction, the responses are dispatched to the
+// callback of every message. A message reported failed fails on its own, a failed batch is a failed
+// delivery of the messages not reported failed.
+func (r *Relayer) routeBatch(ctx context.Context, dst *ChainRuntime, items []*workItem) {
+	switch len(items) {
+	case 0:
+		return
+	case 1:
+		r.RouteMessage(ctx, items[0].message, dst, items[0].src)
+		return
+	}
+
+	var (
+		routed   = make(map[string]*workItem, len(items))
+		messages = make([]*types.Message, 0, len(items))
+	)
+	for _, item := range items {
+		m := item.message
+		m.IncrementRetry()
+		m.SetNextTry(r.retryPolicy(m).Delay(m.GetRetry()))
+		if err := r.transition(m, types.MessageStatusSubmitted); err != nil {
+			continue
+		}
+		routed[workKey(m.MessageKey())] = item
+		messages = append(messages, m.Message)
+	}
+	callback := func(key *types.MessageKey, response *types.TxResponse, err error) {
+		item, ok := routed[workKey(key)]
+		if !ok {
+			return
+		}
+		if err != nil {
+			delete(routed, workKey(key))
+			r.HandleMessageFailed(item.message, dst, item.src, err)
+			return
+		}
+		if response.Code == types.Success {
+			delete(routed, workKey(key))
+		}
+		r.callback(ctx, item.src, dst, key)(key, response, err)
+	}
+
+	dst.log.Info("routing batch", zap.Int("size", len(messages)))
+	if err := dst.Provider.(provider.BatchRouter).RouteBatch(ctx, messages, callback); err != nil {
+		dst.log.Error("batch routing failed", zap.Int("size", len(messages)), zap.Error(err))
+		for _, item := range routed {
+			r.HandleMessageFailed(item.message, dst, item.src, err)
+		}
+	}
+}
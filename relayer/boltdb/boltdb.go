@@ -0,0 +1,121 @@
+package boltdb
+
+import (
+	"bytes"
+
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var _ store.Store = (*BoltDB)(nil)
+
+// bucket holds all the keys of the relayer
+var bucket = []byte("relayer")
+
+type BoltDB struct {
+	db *bolt.DB
+}
+
+func NewBoltDB(path string) (*BoltDB, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "bolt.Open fail")
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltDB{db: db}, nil
+}
+
+func (db *BoltDB) GetByKey(key []byte) ([]byte, error) {
+	var value []byte
+	err := db.db.View(func(tx *bolt.Tx) error {
+		// the value is only valid during the transaction
+		value = bytes.Clone(tx.Bucket(bucket).Get(key))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, store.ErrNotFound
+	}
+	return value, nil
+}
+
+func (db *BoltDB) SetByKey(key []byte, value []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, value)
+	})
+}
+
+func (db *BoltDB) DeleteByKey(key []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete(key)
+	})
+}
+
+// NewIterator reads the keys one page at a time, a read transaction held open could
+// block the writes growing the database file
+func (db *BoltDB) NewIterator(prefix []byte) store.Iterator {
+	return store.NewPageIterator(prefix, db.readPage)
+}
+
+func (db *BoltDB) NewRangeIterator(start, limit []byte) store.Iterator {
+	return store.NewRangePageIterator(start, limit, db.readPage)
+}
+
+func (db *BoltDB) readPage(start, end []byte, limit int) ([]store.KeyValue, error) {
+	var page []store.KeyValue
+	err := db.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, v := c.Seek(start); k != nil && len(page) < limit; k, v = c.Next() {
+			if end != nil && bytes.Compare(k, end) >= 0 {
+				break
+			}
+			page = append(page, store.KeyValue{Key: bytes.Clone(k), Value: bytes.Clone(v)})
+		}
+		return nil
+	})
+	return page, err
+}
+
+// NewBatch returns a batch written in a single update transaction
+func (db *BoltDB) NewBatch() store.Batch {
+	return store.NewOpBatch(func(ops []store.Op) error {
+		return db.db.Update(func(tx *bolt.Tx) error {
+			b := tx.Bucket(bucket)
+			for _, op := range ops {
+				var err error
+				if op.Delete {
+					err = b.Delete(op.Key)
+				} else {
+					err = b.Put(op.Key, op.Value)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (db *BoltDB) ClearStore() error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(bucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(bucket)
+		return err
+	})
+}
+
+func (db *BoltDB) Close() error {
+	return db.db.Close()
+}
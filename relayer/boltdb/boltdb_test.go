@@ -0,0 +1,17 @@
+package boltdb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/store/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltDB(t *testing.T) {
+	db, err := NewBoltDB(filepath.Join(t.TempDir(), "relayer.bolt"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	storetest.Run(t, db)
+}
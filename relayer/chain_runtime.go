@@ -3,6 +3,9 @@ package relayer
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/icon-project/centralized-relay/relayer/provider"
 	"github.com/icon-project/centralized-relay/relayer/types"
@@ -10,12 +13,81 @@ import (
 )
 
 type ChainRuntime struct {
-	Provider        provider.ChainProvider
-	listenerChan    chan *types.BlockInfo
-	log             *zap.Logger
-	LastBlockHeight uint64
-	LastSavedHeight uint64
-	MessageCache    *types.MessageCache
+	Provider     provider.ChainProvider
+	listenerChan chan *types.BlockInfo
+	log          *zap.Logger
+	MessageCache *types.MessageCache
+	// lastBlockHeight is the height of the last block processed, lastSavedHeight the last one
+	// persisted, they are read by the goroutines of the chain while the listener moves them
+	lastBlockHeight atomic.Uint64
+	lastSavedHeight atomic.Uint64
+	// blockMu guards the block records of the chain
+	blockMu sync.Mutex
+
+	heightMu  sync.Mutex
+	latest    cachedHeight
+	finalized cachedHeight
+
+	health chainHealth
+	wallet chainWallet
+
+	// cancel stops the goroutines of the chain, running tracks them
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+}
+
+// SourceHeightTTL is how long the queried latest and finalized heights are reused
+var SourceHeightTTL = 3 * time.Second
+
+type cachedHeight struct {
+	height    uint64
+	queriedAt time.Time
+}
+
+// sourceHeight returns the latest or the finalized height of the chain, queried at most once per SourceHeightTTL
+func (r *ChainRuntime) sourceHeight(ctx context.Context, finalized bool) (uint64, error) {
+	r.heightMu.Lock()
+	defer r.heightMu.Unlock()
+
+	cached, query := &r.latest, r.Provider.QueryLatestHeight
+	if finalized {
+		cached, query = &r.finalized, r.Provider.QueryFinalizedHeight
+	}
+	if time.Since(cached.queriedAt) < SourceHeightTTL {
+		return cached.height, nil
+	}
+	height, err := query(ctx)
+	if err != nil {
+		return 0, err
+	}
+	*cached = cachedHeight{height, time.Now()}
+	return height, nil
+}
+
+// isFinal reports whether the message block reached the finality required on the chain
+func (r *ChainRuntime) isFinal(ctx context.Context, m *types.RouteMessage, cfg *SourceConfig) (bool, error) {
+	if cfg == nil {
+		return true, nil
+	}
+	if cfg.Finalized {
+		height, err := r.sourceHeight(ctx, true)
+		if err != nil {
+			return false, err
+		}
+		if m.MessageHeight > height {
+			return false, nil
+		}
+	}
+	if cfg.Confirmations > 0 {
+		height, err := r.sourceHeight(ctx, false)
+		if err != nil {
+			return false, err
+		}
+		if m.MessageHeight+cfg.Confirmations > height {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 func NewChainRuntime(log *zap.Logger, chain *Chain) (*ChainRuntime, error) {
@@ -27,14 +99,42 @@ func NewChainRuntime(log *zap.Logger, chain *Chain) (*ChainRuntime, error) {
 		Provider:     chain.ChainProvider,
 		listenerChan: make(chan *types.BlockInfo, listenerChannelBufferSize),
 		MessageCache: types.NewMessageCache(),
+		health: chainHealth{
+			status: ChainStatus{State: ChainStateHealthy, Since: time.Now()},
+		},
 	}, nil
 }
 
-func (r *ChainRuntime) mergeMessages(ctx context.Context, messages []*types.Message) {
+// LastBlockHeight returns the height of the last block processed
+func (r *ChainRuntime) LastBlockHeight() uint64 {
+	return r.lastBlockHeight.Load()
+}
+
+// LastSavedHeight returns the last height persisted, the listener resumes from it
+func (r *ChainRuntime) LastSavedHeight() uint64 {
+	return r.lastSavedHeight.Load()
+}
+
+// inherit carries over the state of the runtime replaced by a config reload
+func (r *ChainRuntime) inherit(prev *ChainRuntime) {
+	r.MessageCache = prev.MessageCache
+	r.listenerChan = prev.listenerChan
+	r.lastSavedHeight.Store(prev.LastSavedHeight())
+	r.lastBlockHeight.Store(prev.LastBlockHeight())
+	r.health.status = prev.Status()
+	prev.wallet.mu.RLock()
+	r.wallet.balances, r.wallet.needed, r.wallet.queriedAt = prev.wallet.balances, prev.wallet.needed, prev.wallet.queriedAt
+	prev.wallet.mu.RUnlock()
+}
+
+func (r *ChainRuntime) mergeMessages(ctx context.Context, messages []*types.Message) []*types.RouteMessage {
+	routeMessages := make([]*types.RouteMessage, 0, len(messages))
 	for _, m := range messages {
 		routeMessage := types.NewRouteMessage(m)
 		r.MessageCache.Add(routeMessage)
+		routeMessages = append(routeMessages, routeMessage)
 	}
+	return routeMessages
 }
 
 func (r *ChainRuntime) clearMessageFromCache(msgs []*types.MessageKey) {
@@ -43,24 +143,35 @@ func (r *ChainRuntime) clearMessageFromCache(msgs []*types.MessageKey) {
 	}
 }
 
-func (dst *ChainRuntime) shouldSendMessage(ctx context.Context, routeMessage *types.RouteMessage, src *ChainRuntime) bool {
+// shouldSendMessage reports whether the message can be routed now, a message refused by
+// the filter rules is never routed and the reason is returned
+func (dst *ChainRuntime) shouldSendMessage(ctx context.Context, routeMessage *types.RouteMessage, src *ChainRuntime, filter *FilterConfig) (bool, string) {
 	if routeMessage == nil {
-		return false
+		return false, ""
 	}
 
 	if routeMessage.IsProcessing() {
-		return false
+		return false, ""
+	}
+
+	if reason := filter.check(routeMessage.Message); reason != "" {
+		return false, reason
 	}
 
 	if ok, err := dst.Provider.ShouldReceiveMessage(ctx, routeMessage.Message); !ok || err != nil {
-		return false
+		return false, ""
 	}
 
 	if ok, err := src.Provider.ShouldSendMessage(ctx, routeMessage.Message); !ok || err != nil {
-		return false
+		return false, ""
 	}
 
-	return true
+	reason, err := filter.checkFee(ctx, src, routeMessage.Message)
+	if err != nil {
+		src.log.Warn("failed to query the route fee", zap.String("dst", routeMessage.Dst), zap.Error(err))
+		return false, ""
+	}
+	return reason == "", reason
 }
 
 func (r *ChainRuntime) shouldExecuteCall(ctx context.Context, msg *types.RouteMessage) bool {
@@ -39,12 +39,14 @@ func TestChainRuntime(t *testing.T) {
 
 	t.Run("merge messages", func(t *testing.T) {
 		runtime.mergeMessages(ctx, info.Messages)
-		assert.Equal(t, len(runtime.MessageCache.Messages), len(info.Messages))
+		assert.Equal(t, runtime.MessageCache.Len(), len(info.Messages))
 	})
 
 	t.Run("clear messages", func(t *testing.T) {
 		runtime.clearMessageFromCache([]*types.MessageKey{m1.MessageKey()})
-		assert.Equal(t, len(runtime.MessageCache.Messages), len(info.Messages)-1)
-		assert.Equal(t, runtime.MessageCache.Messages[*m2.MessageKey()], types.NewRouteMessage(m2))
+		assert.Equal(t, runtime.MessageCache.Len(), len(info.Messages)-1)
+		cached, ok := runtime.MessageCache.Get(m2.MessageKey())
+		assert.True(t, ok)
+		assert.Equal(t, cached, types.NewRouteMessage(m2))
 	})
 }
@@ -0,0 +1,258 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/icon-project/centralized-relay/relayer/events"
+	"github.com/icon-project/centralized-relay/relayer/provider"
+	providerTypes "github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+)
+
+var _ provider.BatchRouter = (*Provider)(nil)
+
+// MethodAggregate3 is the method of the batch contract the batches are sent through
+const MethodAggregate3 = "aggregate3"
+
+// batchABI is the aggregate3 method of the batch contract, the one of Multicall3
+const batchABI = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+// call3 is a call of an aggregate3 batch
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// result3 is the result of a call of an aggregate3 batch
+type result3 struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// SupportsBatch reports whether the messages can be batched, they are when a batch contract is configured
+func (p *Provider) SupportsBatch() bool {
+	return p.cfg.BatchContract != ""
+}
+
+// RouteBatch delivers the messages in one aggregate3 call of the batch contract. The calls are
+// allowed to fail on their own: the batch is simulated first and the messages whose call fails
+// are reported failed and left out, the others are sent and each reports its share of the cost.
+func (p *Provider) RouteBatch(ctx context.Context, messages []*providerTypes.Message, callback providerTypes.TxResponseFunc) error {
+	if !p.SupportsBatch() {
+		return fmt.Errorf("batch contract is not configured")
+	}
+	parsed, err := abi.JSON(strings.NewReader(batchABI))
+	if err != nil {
+		return err
+	}
+	calls := make([]call3, 0, len(messages))
+	for _, message := range messages {
+		contract, input, err := p.callData(message)
+		if err != nil {
+			return err
+		}
+		calls = append(calls, call3{Target: contract, AllowFailure: true, CallData: input})
+	}
+
+	pool, err := p.walletPool(ctx)
+	if err != nil {
+		return fmt.Errorf("batch routing failed: %w", err)
+	}
+	wallet := pool.acquire()
+	defer wallet.release()
+
+	results, err := p.simulateBatch(ctx, parsed, wallet.key.Address, calls)
+	if err != nil {
+		return fmt.Errorf("batch simulation failed: %w", err)
+	}
+	sent, sentCalls := messages[:0:0], calls[:0:0]
+	for i, message := range messages {
+		if !results[i].Success {
+			err := fmt.Errorf("message delivery reverts in the batch")
+			p.log.Error("batch message dropped", zap.Any("message-key", message.MessageKey()), zap.Error(err))
+			callback(message.MessageKey(), &providerTypes.TxResponse{Code: providerTypes.Failed}, err)
+			continue
+		}
+		sent, sentCalls = append(sent, message), append(sentCalls, calls[i])
+	}
+	if len(sent) == 0 {
+		return nil
+	}
+	input, err := parsed.Pack(MethodAggregate3, sentCalls)
+	if err != nil {
+		return err
+	}
+
+	// lock here to prevent transcation replacement
+	wallet.mu.Lock()
+	tx, err := p.sendBatch(ctx, wallet, input)
+	wallet.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("batch routing failed: %w", err)
+	}
+	p.log.Info("batch transaction sent", zap.String("tx_hash", tx.Hash().String()), zap.Int("size", len(sent)))
+	return p.waitForBatchResult(ctx, parsed, tx, sent, callback)
+}
+
+// simulateBatch calls aggregate3 from the address without a transaction and returns the result of every call
+func (p *Provider) simulateBatch(ctx context.Context, parsed abi.ABI, from common.Address, calls []call3) ([]result3, error) {
+	input, err := parsed.Pack(MethodAggregate3, calls)
+	if err != nil {
+		return nil, err
+	}
+	contract := common.HexToAddress(p.cfg.BatchContract)
+	ctx, cancel := context.WithTimeout(ctx, defaultReadTimeout)
+	defer cancel()
+	output, err := p.client.CallContract(ctx, ethereum.CallMsg{From: from, To: &contract, Data: input}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return unpackBatchResults(parsed, output, len(calls))
+}
+
+// unpackBatchResults decodes the aggregate3 output of a batch of size calls
+func unpackBatchResults(parsed abi.ABI, output []byte, size int) ([]result3, error) {
+	var results []result3
+	if err := parsed.UnpackIntoInterface(&results, MethodAggregate3, output); err != nil {
+		return nil, err
+	}
+	if len(results) != size {
+		return nil, fmt.Errorf("batch returned %d results for %d calls", len(results), size)
+	}
+	return results, nil
+}
+
+// minedBatchResults returns the result of every call of the mined batch transaction
+func (p *Provider) minedBatchResults(ctx context.Context, parsed abi.ABI, tx *ethTypes.Transaction, size int) ([]result3, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultReadTimeout)
+	defer cancel()
+	output, err := p.client.TransactionOutput(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return unpackBatchResults(parsed, output, size)
+}
+
+// sendBatch signs and sends the batch transaction with the next nonce of the wallet
+func (p *Provider) sendBatch(ctx context.Context, wallet *poolWallet, input []byte) (*ethTypes.Transaction, error) {
+	opts, err := p.transactOpts(ctx, wallet.key)
+	if err != nil {
+		return nil, err
+	}
+	contract := common.HexToAddress(p.cfg.BatchContract)
+	gasLimit, err := p.client.EstimateGas(ctx, ethereum.CallMsg{From: opts.From, To: &contract, Data: input})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	if gasLimit > p.cfg.GasLimit {
+		return nil, fmt.Errorf("gas limit exceeded: %d", gasLimit)
+	}
+	tx, err := opts.Signer(opts.From, ethTypes.NewTx(&ethTypes.DynamicFeeTx{
+		ChainID:   p.client.GetChainID(),
+		Nonce:     opts.Nonce.Uint64(),
+		GasTipCap: opts.GasTipCap,
+		GasFeeCap: opts.GasFeeCap,
+		Gas:       gasLimit + (gasLimit * p.cfg.GasAdjustment / 100),
+		To:        &contract,
+		Data:      input,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.client.SendTransaction(ctx, tx); err != nil {
+		switch p.parseErr(err) {
+		case ErrNonceTooLow, ErrNonceTooHigh, ErrorLessGas:
+			if nonce, err := p.client.PendingNonceAt(ctx, opts.From, nil); err == nil {
+				p.NonceTracker.Set(opts.From, nonce)
+			}
+		}
+		return nil, err
+	}
+	return tx, nil
+}
+
+// waitForBatchResult reports the batch transaction to the callback of every message, each
+// message with its share of the cost of the transaction
+func (p *Provider) waitForBatchResult(ctx context.Context, parsed abi.ABI, tx *ethTypes.Transaction, messages []*providerTypes.Message, callback providerTypes.TxResponseFunc) error {
+	res := &providerTypes.TxResponse{
+		TxHash: tx.Hash().String(),
+		Nonce:  tx.Nonce(),
+	}
+	for _, message := range messages {
+		callback(message.MessageKey(), &providerTypes.TxResponse{TxHash: res.TxHash, Nonce: res.Nonce, Code: providerTypes.Pending}, nil)
+	}
+
+	receipt, err := p.WaitForResults(ctx, tx)
+	if err == nil {
+		res.Height = receipt.BlockNumber.Int64()
+		if receipt.Status != ethTypes.ReceiptStatusSuccessful {
+			err = fmt.Errorf("transaction failed to execute")
+		}
+	}
+	if err != nil {
+		p.log.Error("batch transaction failed", zap.String("tx_hash", res.TxHash), zap.Int("size", len(messages)), zap.Error(err))
+		for _, message := range messages {
+			callback(message.MessageKey(), res, err)
+		}
+		return err
+	}
+	res.GasUsed, res.Fee = txCost(receipt)
+	results, err := p.minedBatchResults(ctx, parsed, tx, len(messages))
+	if err != nil {
+		p.log.Warn("failed to trace the batch results", zap.String("tx_hash", res.TxHash), zap.Error(err))
+	}
+	for i, message := range messages {
+		share := res.Share(i, len(messages))
+		var result *result3
+		if results != nil {
+			result = &results[i]
+		}
+		if err := p.batchCallResult(ctx, message, receipt, result); err != nil {
+			share.Code = providerTypes.Failed
+			p.LogFailedTx(message.MessageKey(), receipt, err)
+			callback(message.MessageKey(), share, err)
+			continue
+		}
+		share.Code = providerTypes.Success
+		callback(message.MessageKey(), share, nil)
+		p.LogSuccessTx(message.MessageKey(), receipt)
+	}
+	return nil
+}
+
+// batchCallResult checks the call of the message in the mined batch, a call is allowed to fail
+// on its own. The result is traced from the batch transaction, a delivery is checked against the
+// connection contract when the node does not trace. A call that cannot be checked is reported
+// failed to be sent again.
+func (p *Provider) batchCallResult(ctx context.Context, message *providerTypes.Message, receipt *ethTypes.Receipt, result *result3) error {
+	if result != nil {
+		if result.Success {
+			return nil
+		}
+		if reason, err := abi.UnpackRevert(result.ReturnData); err == nil {
+			return fmt.Errorf("message call reverted in the batch: %s", reason)
+		}
+		return fmt.Errorf("message call reverted in the batch")
+	}
+	if message.EventType != events.EmitMessage {
+		return fmt.Errorf("the result of the message call in the batch is unknown")
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultReadTimeout)
+	defer cancel()
+	received, err := p.client.MessageReceived(&bind.CallOpts{Context: ctx, BlockNumber: receipt.BlockNumber}, message.Src, message.Sn)
+	if err != nil {
+		return fmt.Errorf("failed to check the batch delivery: %w", err)
+	}
+	if !received {
+		return fmt.Errorf("message delivery reverted in the batch")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/icon-project/centralized-relay/relayer/events"
+	providerTypes "github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revertData is the return data of a call reverted with reason
+func revertData(t *testing.T, reason string) []byte {
+	args := abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}
+	data, err := args.Pack(reason)
+	require.NoError(t, err)
+	return append([]byte{0x08, 0xc3, 0x79, 0xa0}, data...)
+}
+
+func TestBatchCallResult(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(batchABI))
+	require.NoError(t, err)
+	output, err := parsed.Methods[MethodAggregate3].Outputs.Pack([]result3{
+		{Success: true, ReturnData: []byte{}},
+		{Success: false, ReturnData: revertData(t, "invalid request id")},
+		{Success: true, ReturnData: []byte{}},
+	})
+	require.NoError(t, err)
+
+	results, err := unpackBatchResults(parsed, output, 3)
+	require.NoError(t, err)
+	_, err = unpackBatchResults(parsed, output, 2)
+	assert.Error(t, err)
+
+	p := &Provider{}
+	receipt := &ethTypes.Receipt{BlockNumber: big.NewInt(10)}
+	messages := []*providerTypes.Message{
+		{Src: "icon", Sn: big.NewInt(1), EventType: events.EmitMessage},
+		{Src: "icon", Sn: big.NewInt(2), EventType: events.CallMessage},
+		{Src: "icon", Sn: big.NewInt(3), EventType: events.RollbackMessage},
+	}
+
+	// the reverted call message fails on its own
+	assert.NoError(t, p.batchCallResult(context.Background(), messages[0], receipt, &results[0]))
+	err = p.batchCallResult(context.Background(), messages[1], receipt, &results[1])
+	assert.ErrorContains(t, err, "invalid request id")
+	assert.NoError(t, p.batchCallResult(context.Background(), messages[2], receipt, &results[2]))
+
+	// a call without its result is failed to be sent again
+	assert.Error(t, p.batchCallResult(context.Background(), messages[1], receipt, nil))
+}
@@ -13,6 +13,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -87,10 +88,12 @@ type IClient interface {
 	PendingNonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
 	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethTypes.Receipt, error)
+	TransactionOutput(ctx context.Context, txHash common.Hash) ([]byte, error)
 	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
 	SendTransaction(ctx context.Context, tx *ethTypes.Transaction) error
 	Subscribe(ctx context.Context, q ethereum.FilterQuery, ch chan<- ethTypes.Log) (ethereum.Subscription, error)
 	Reconnect() (IClient, error)
+	Close()
 
 	// abiContract for connection
 	ParseConnectionMessage(log ethTypes.Log) (*bridgeContract.ConnectionMessage, error)
@@ -99,7 +102,7 @@ type IClient interface {
 	MessageReceived(opts *bind.CallOpts, srcNetwork string, _connSn *big.Int) (bool, error)
 	SetAdmin(opts *bind.TransactOpts, newAdmin common.Address) (*ethTypes.Transaction, error)
 	RevertMessage(opts *bind.TransactOpts, sn *big.Int) (*ethTypes.Transaction, error)
-	GetFee(opts *bind.CallOpts, networkID string) (*big.Int, error)
+	GetFee(opts *bind.CallOpts, networkID string, responseFee bool) (*big.Int, error)
 	SetFee(opts *bind.TransactOpts, src string, msg, res *big.Int) (*ethTypes.Transaction, error)
 	ClaimFee(opts *bind.TransactOpts) (*ethTypes.Transaction, error)
 
@@ -122,6 +125,23 @@ func (cl *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*
 	return cl.eth.TransactionReceipt(ctx, txHash)
 }
 
+// TransactionOutput returns the data returned by the top call of the mined transaction,
+// read from the call tracer of the node
+func (cl *Client) TransactionOutput(ctx context.Context, txHash common.Hash) ([]byte, error) {
+	var trace struct {
+		Output hexutil.Bytes `json:"output"`
+		Error  string        `json:"error"`
+	}
+	config := map[string]any{"tracer": "callTracer", "tracerConfig": map[string]any{"onlyTopCall": true}}
+	if err := cl.ethRpc.Client().CallContext(ctx, &trace, "debug_traceTransaction", txHash, config); err != nil {
+		return nil, err
+	}
+	if trace.Error != "" {
+		return nil, fmt.Errorf("transaction failed: %s", trace.Error)
+	}
+	return trace.Output, nil
+}
+
 func (cl *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
 	return cl.eth.CallContract(ctx, msg, blockNumber)
 }
@@ -214,8 +234,8 @@ func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64,
 }
 
 // GetFee
-func (c *Client) GetFee(opts *bind.CallOpts, networkID string) (*big.Int, error) {
-	return c.connection.GetFee(opts, networkID, true)
+func (c *Client) GetFee(opts *bind.CallOpts, networkID string, responseFee bool) (*big.Int, error) {
+	return c.connection.GetFee(opts, networkID, responseFee)
 }
 
 // SetFee
@@ -242,3 +262,9 @@ func (c *Client) Subscribe(ctx context.Context, q ethereum.FilterQuery, ch chan<
 func (c *Client) Reconnect() (IClient, error) {
 	return c.reconnect()
 }
+
+// Close closes the websocket and the rpc connections
+func (c *Client) Close() {
+	c.eth.Close()
+	c.ethRpc.Close()
+}
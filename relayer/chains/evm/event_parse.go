@@ -24,6 +24,7 @@ func (p *Provider) getRelayMessageFromLog(log types.Log) (*providerTypes.Message
 			Src:           p.NID(),
 			Sn:            msg.Sn,
 			MessageHeight: log.BlockNumber,
+			TxHash:        log.TxHash.Hex(),
 			EventType:     p.GetEventName(EmitMessage),
 			Data:          msg.Msg,
 		}, nil
@@ -37,9 +38,12 @@ func (p *Provider) getRelayMessageFromLog(log types.Log) (*providerTypes.Message
 			Src:           p.NID(),
 			Sn:            msg.Sn,
 			MessageHeight: log.BlockNumber,
+			TxHash:        log.TxHash.Hex(),
 			EventType:     p.GetEventName(CallMessage),
 			Data:          msg.Data,
 			ReqID:         msg.ReqId,
+			// the from string is an indexed topic, only its keccak256 hash is logged
+			From: msg.From.Hex(),
 		}, nil
 	case RollbackMessageHash:
 		msg, err := p.client.ParseRollbackMessage(log)
@@ -51,6 +55,7 @@ func (p *Provider) getRelayMessageFromLog(log types.Log) (*providerTypes.Message
 			Src:           p.NID(),
 			Sn:            msg.Sn,
 			MessageHeight: log.BlockNumber,
+			TxHash:        log.TxHash.Hex(),
 			EventType:     p.GetEventName(RollbackMessage),
 		}, nil
 	default:
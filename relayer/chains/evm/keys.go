@@ -9,29 +9,34 @@ import (
 )
 
 func (p *Provider) RestoreKeystore(ctx context.Context) error {
-	path := p.keystorePath(p.cfg.Address)
-	keystoreCipher, err := os.ReadFile(path)
+	key, err := p.restoreKey(ctx, p.cfg.Address)
 	if err != nil {
 		return err
 	}
+	p.wallet = key
+	return nil
+}
+
+// restoreKey decrypts the keystore of the address
+func (p *Provider) restoreKey(ctx context.Context, addr string) (*keystore.Key, error) {
+	path := p.keystorePath(addr)
+	keystoreCipher, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 	keystoreJson, err := p.kms.Decrypt(ctx, keystoreCipher)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	authCipher, err := os.ReadFile(path + ".pass")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	secret, err := p.kms.Decrypt(ctx, authCipher)
 	if err != nil {
-		return err
-	}
-	key, err := keystore.DecryptKey(keystoreJson, string(secret))
-	if err != nil {
-		return err
+		return nil, err
 	}
-	p.wallet = key
-	return nil
+	return keystore.DecryptKey(keystoreJson, string(secret))
 }
 
 func (p *Provider) NewKeystore(password string) (string, error) {
@@ -123,6 +123,7 @@ func (p *Provider) Listener(ctx context.Context, lastSavedHeight uint64, blockIn
 					)
 					blockInfoChan <- &relayertypes.BlockInfo{
 						Height:   log.BlockNumber,
+						Hash:     log.BlockHash.Hex(),
 						Messages: []*relayertypes.Message{message},
 					}
 				}
@@ -242,6 +243,15 @@ func (p *Provider) Subscribe(ctx context.Context, blockInfoChan chan *relayertyp
 			resetCh <- err
 			return err
 		case log := <-ch:
+			if log.Removed {
+				// the block was reorged out, the relayer retracts its messages once the hash changes
+				p.log.Warn("log removed by chain reorg",
+					zap.String("tx_hash", log.TxHash.String()),
+					zap.Uint64("block_number", log.BlockNumber),
+					zap.String("block_hash", log.BlockHash.Hex()),
+				)
+				continue
+			}
 			message, err := p.getRelayMessageFromLog(log)
 			if err != nil {
 				p.log.Error("failed to get relay message from log", zap.Error(err))
@@ -256,6 +266,7 @@ func (p *Provider) Subscribe(ctx context.Context, blockInfoChan chan *relayertyp
 			)
 			blockInfoChan <- &relayertypes.BlockInfo{
 				Height:   log.BlockNumber,
+				Hash:     log.BlockHash.Hex(),
 				Messages: []*relayertypes.Message{message},
 			}
 		case <-time.After(time.Minute * 2):
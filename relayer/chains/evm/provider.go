@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"slices"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	coreTypes "github.com/ethereum/go-ethereum/core/types"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	bridgeContract "github.com/icon-project/centralized-relay/relayer/chains/evm/abi"
 	"github.com/icon-project/centralized-relay/relayer/chains/evm/types"
 	"github.com/icon-project/centralized-relay/relayer/events"
@@ -26,7 +28,10 @@ import (
 	"go.uber.org/zap"
 )
 
-var _ provider.Config = (*Config)(nil)
+var (
+	_ provider.Config           = (*Config)(nil)
+	_ provider.WalletPoolConfig = (*Config)(nil)
+)
 
 var (
 	// Connection contract
@@ -49,6 +54,12 @@ type Config struct {
 	GasLimit              uint64 `json:"gas-limit" yaml:"gas-limit"`
 	GasAdjustment         uint64 `json:"gas-adjustment" yaml:"gas-adjustment"`
 	BlockBatchSize        uint64 `json:"block-batch-size" yaml:"block-batch-size"`
+	// Wallets are signing along the configured address, the routes are spread over all of them
+	Wallets []string `json:"wallets,omitempty" yaml:"wallets,omitempty"`
+	// WalletSelection picks the wallet of a route, round-robin or least-pending
+	WalletSelection string `json:"wallet-selection,omitempty" yaml:"wallet-selection,omitempty"`
+	// BatchContract is the relayer owned contract the batches of messages are sent through, no batching when empty
+	BatchContract string `json:"batch-contract,omitempty" yaml:"batch-contract,omitempty"`
 }
 
 type Provider struct {
@@ -62,7 +73,9 @@ type Provider struct {
 	contracts           map[string]providerTypes.EventMap
 	NonceTracker        types.NonceTrackerI
 	LastSavedHeightFunc func() uint64
-	routerMutex         *sync.Mutex
+	// walletMu guards the restore of the wallets
+	walletMu sync.Mutex
+	pool     *walletPool
 }
 
 func (p *Config) NewProvider(ctx context.Context, log *zap.Logger, homepath string, debug bool, chainName string) (provider.ChainProvider, error) {
@@ -96,7 +109,6 @@ func (p *Config) NewProvider(ctx context.Context, log *zap.Logger, homepath stri
 		blockReq:     p.GetMonitorEventFilters(),
 		contracts:    p.eventMap(),
 		NonceTracker: types.NewNonceTracker(client.PendingNonceAt),
-		routerMutex:  new(sync.Mutex),
 	}, nil
 }
 
@@ -108,6 +120,11 @@ func (p *Config) Validate() error {
 	if err := p.Contracts.Validate(); err != nil {
 		return fmt.Errorf("contracts are not valid: %s", err)
 	}
+	switch p.WalletSelection {
+	case "", WalletSelectionRoundRobin, WalletSelectionLeastPending:
+	default:
+		return fmt.Errorf("unknown wallet-selection: %s", p.WalletSelection)
+	}
 	return nil
 }
 
@@ -129,6 +146,27 @@ func (p *Config) GetWallet() string {
 	return p.Address
 }
 
+// GetWallets returns the pool wallets signing along the configured address
+func (p *Config) GetWallets() []string {
+	return p.Wallets
+}
+
+// SetWallets sets the pool wallets
+func (p *Config) SetWallets(addrs []string) {
+	p.Wallets = addrs
+}
+
+// addresses returns the configured address followed by the pool wallets
+func (p *Config) addresses() []string {
+	addrs := []string{p.Address}
+	for _, addr := range p.Wallets {
+		if !slices.Contains(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
 // Enabled returns true if the chain is enabled
 func (c *Config) Enabled() bool {
 	return !c.Disabled
@@ -151,19 +189,13 @@ func (p *Provider) Name() string {
 	return p.cfg.ChainName
 }
 
+// Wallet returns the configured wallet, the admin of the connection
 func (p *Provider) Wallet() (*keystore.Key, error) {
-	ctx := context.Background()
-	if p.wallet == nil {
-		if err := p.RestoreKeystore(ctx); err != nil {
-			return nil, err
-		}
-		nonce, err := p.client.PendingNonceAt(ctx, p.wallet.Address, nil)
-		if err != nil {
-			return nil, err
-		}
-		p.NonceTracker.Set(p.wallet.Address, nonce)
+	pool, err := p.walletPool(context.Background())
+	if err != nil {
+		return nil, err
 	}
-	return p.wallet, nil
+	return pool.primary().key, nil
 }
 
 func (p *Provider) FinalityBlock(ctx context.Context) uint64 {
@@ -229,21 +261,26 @@ func (r *Provider) transferBalance(senderKey, recepientAddress string, amount *b
 	return
 }
 
-func (p *Provider) GetTransationOpts(ctx context.Context) (*bind.TransactOpts, error) {
-	newTransactOpts := func(w *keystore.Key) (*bind.TransactOpts, error) {
-		txo, err := bind.NewKeyedTransactorWithChainID(w.PrivateKey, p.client.GetChainID())
-		if err != nil {
-			return nil, err
-		}
-		return txo, nil
+// sendFromPrimary sends the transaction of the message from the configured wallet, the
+// wallet is locked from the nonce assignment to the broadcast like the routes of the pool
+func (p *Provider) sendFromPrimary(ctx context.Context, message *providerTypes.Message) (*ethTypes.Transaction, error) {
+	pool, err := p.walletPool(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	wallet, err := p.Wallet()
+	wallet := pool.primary()
+	wallet.mu.Lock()
+	defer wallet.mu.Unlock()
+	opts, err := p.transactOpts(ctx, wallet.key)
 	if err != nil {
 		return nil, err
 	}
+	return p.SendTransaction(ctx, opts, message)
+}
 
-	txOpts, err := newTransactOpts(p.wallet)
+// transactOpts returns the options of a transaction signed by the wallet with its next nonce
+func (p *Provider) transactOpts(ctx context.Context, wallet *keystore.Key) (*bind.TransactOpts, error) {
+	txOpts, err := bind.NewKeyedTransactorWithChainID(wallet.PrivateKey, p.client.GetChainID())
 	if err != nil {
 		return nil, err
 	}
@@ -265,11 +302,10 @@ func (p *Provider) GetTransationOpts(ctx context.Context) (*bind.TransactOpts, e
 
 // SetAdmin sets the admin address of the bridge contract
 func (p *Provider) SetAdmin(ctx context.Context, admin string) error {
-	opts, err := p.GetTransationOpts(ctx)
+	tx, err := p.sendFromPrimary(ctx, &providerTypes.Message{EventType: events.SetAdmin, Dst: admin})
 	if err != nil {
 		return err
 	}
-	tx, err := p.SendTransaction(ctx, opts, &providerTypes.Message{EventType: events.SetAdmin, Dst: admin})
 	receipt, err := p.WaitForResults(ctx, tx)
 	if err != nil {
 		return err
@@ -282,15 +318,11 @@ func (p *Provider) SetAdmin(ctx context.Context, admin string) error {
 
 // RevertMessage
 func (p *Provider) RevertMessage(ctx context.Context, sn *big.Int) error {
-	opts, err := p.GetTransationOpts(ctx)
-	if err != nil {
-		return err
-	}
 	msg := &providerTypes.Message{
 		EventType: events.RevertMessage,
 		Sn:        sn,
 	}
-	tx, err := p.SendTransaction(ctx, opts, msg)
+	tx, err := p.sendFromPrimary(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -309,11 +341,7 @@ func (p *Provider) ClaimFee(ctx context.Context) error {
 	msg := &providerTypes.Message{
 		EventType: events.ClaimFee,
 	}
-	opts, err := p.GetTransationOpts(ctx)
-	if err != nil {
-		return err
-	}
-	tx, err := p.SendTransaction(ctx, opts, msg)
+	tx, err := p.sendFromPrimary(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -329,17 +357,13 @@ func (p *Provider) ClaimFee(ctx context.Context) error {
 
 // SetFee
 func (p *Provider) SetFee(ctx context.Context, networkID string, msgFee, resFee *big.Int) error {
-	opts, err := p.GetTransationOpts(ctx)
-	if err != nil {
-		return err
-	}
 	msg := &providerTypes.Message{
 		EventType: events.SetFee,
 		Src:       networkID,
 		Sn:        msgFee,
 		ReqID:     resFee,
 	}
-	tx, err := p.SendTransaction(ctx, opts, msg)
+	tx, err := p.sendFromPrimary(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -355,24 +379,79 @@ func (p *Provider) SetFee(ctx context.Context, networkID string, msgFee, resFee
 
 // GetFee
 func (p *Provider) GetFee(ctx context.Context, networkID string, responseFee bool) (uint64, error) {
-	fee, err := p.client.GetFee(&bind.CallOpts{Context: ctx}, networkID)
+	fee, err := p.client.GetFee(&bind.CallOpts{Context: ctx}, networkID, responseFee)
 	if err != nil {
 		return 0, err
 	}
 	return fee.Uint64(), nil
 }
 
-// ExecuteRollback
-func (p *Provider) ExecuteRollback(ctx context.Context, sn *big.Int) error {
-	opts, err := p.GetTransationOpts(ctx)
+// EstimateCost returns the fee in wei of the transaction delivering the message
+func (p *Provider) EstimateCost(ctx context.Context, message *providerTypes.Message) (uint64, error) {
+	gasLimit, err := p.EstimateGas(ctx, message)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	gasLimit += gasLimit * p.cfg.GasAdjustment / 100
+	return gasPrice.Mul(gasPrice, new(big.Int).SetUint64(gasLimit)).Uint64(), nil
+}
+
+// QueryClaimableFee returns the balance of the connection contract, claimFees sends all of it to the admin
+func (p *Provider) QueryClaimableFee(ctx context.Context) (*big.Int, error) {
+	return p.client.GetBalance(ctx, p.cfg.Contracts[providerTypes.ConnectionContract])
+}
+
+// Transfer sends wei from the relayer wallet
+func (p *Provider) Transfer(ctx context.Context, to string, amount *big.Int) error {
+	pool, err := p.walletPool(ctx)
 	if err != nil {
 		return err
 	}
+	wallet := pool.primary()
+	wallet.mu.Lock()
+	opts, err := p.transactOpts(ctx, wallet.key)
+	if err != nil {
+		wallet.mu.Unlock()
+		return err
+	}
+	recipient := common.HexToAddress(to)
+	tx, err := opts.Signer(opts.From, ethTypes.NewTx(&ethTypes.DynamicFeeTx{
+		ChainID:   p.client.GetChainID(),
+		Nonce:     opts.Nonce.Uint64(),
+		GasTipCap: opts.GasTipCap,
+		GasFeeCap: opts.GasFeeCap,
+		Gas:       params.TxGas,
+		To:        &recipient,
+		Value:     amount,
+	}))
+	if err == nil {
+		err = p.client.SendTransaction(ctx, tx)
+	}
+	wallet.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to transfer: %w", err)
+	}
+	receipt, err := p.WaitForResults(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != 1 {
+		return fmt.Errorf("failed to transfer: %s", tx.Hash())
+	}
+	return nil
+}
+
+// ExecuteRollback
+func (p *Provider) ExecuteRollback(ctx context.Context, sn *big.Int) error {
 	msg := &providerTypes.Message{
 		EventType: events.RollbackMessage,
 		Sn:        sn,
 	}
-	tx, err := p.SendTransaction(ctx, opts, msg)
+	tx, err := p.sendFromPrimary(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -388,86 +467,107 @@ func (p *Provider) ExecuteRollback(ctx context.Context, sn *big.Int) error {
 
 // EstimateGas
 func (p *Provider) EstimateGas(ctx context.Context, message *providerTypes.Message) (uint64, error) {
-	contract := common.HexToAddress(p.cfg.Contracts[providerTypes.ConnectionContract])
-	msg := ethereum.CallMsg{
-		From: p.wallet.Address,
-		To:   &contract,
+	wallet, err := p.Wallet()
+	if err != nil {
+		return 0, err
+	}
+	return p.estimateGas(ctx, wallet.Address, message)
+}
+
+// estimateGas estimates the gas of the transaction sent by the address
+func (p *Provider) estimateGas(ctx context.Context, from common.Address, message *providerTypes.Message) (uint64, error) {
+	contract, input, err := p.callData(message)
+	if err != nil {
+		return 0, err
 	}
+	return p.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &contract, Data: input})
+}
+
+// callData returns the contract called to deliver the message and the input of the call
+func (p *Provider) callData(message *providerTypes.Message) (common.Address, []byte, error) {
+	var input []byte
+	contract := common.HexToAddress(p.cfg.Contracts[providerTypes.ConnectionContract])
 	switch message.EventType {
 	case events.EmitMessage:
 		abi, err := bridgeContract.ConnectionMetaData.GetAbi()
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
 		data, err := abi.Pack(MethodRecvMessage, message.Src, message.Sn, message.Data)
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
-		msg.Data = data
+		input = data
 	case events.SetAdmin:
 		abi, err := bridgeContract.ConnectionMetaData.GetAbi()
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
 		data, err := abi.Pack(MethodSetAdmin, message.Src)
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
-		msg.Data = data
+		input = data
 	case events.RevertMessage:
 		abi, err := bridgeContract.ConnectionMetaData.GetAbi()
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
 		data, err := abi.Pack(MethodRevertMessage, message.Sn)
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
-		msg.Data = data
+		input = data
 	case events.ClaimFee:
 		abi, err := bridgeContract.ConnectionMetaData.GetAbi()
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
 		data, err := abi.Pack(MethodClaimFees)
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
-		msg.Data = data
+		input = data
 	case events.SetFee:
 		abi, err := bridgeContract.ConnectionMetaData.GetAbi()
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
 		data, err := abi.Pack(MethodSetFee, message.Src, message.Sn, message.ReqID)
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
-		msg.Data = data
+		input = data
 	case events.CallMessage:
 		abi, err := bridgeContract.XcallMetaData.GetAbi()
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
 		data, err := abi.Pack(MethodExecuteCall, message.ReqID, message.Data)
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
-		msg.Data = data
+		input = data
 		contract = common.HexToAddress(p.cfg.Contracts[providerTypes.XcallContract])
 	case events.RollbackMessage:
 		abi, err := bridgeContract.XcallMetaData.GetAbi()
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
 		data, err := abi.Pack(MethodExecuteRollback, message.Sn)
 		if err != nil {
-			return 0, err
+			return contract, nil, err
 		}
-		msg.Data = data
+		input = data
 		contract = common.HexToAddress(p.cfg.Contracts[providerTypes.XcallContract])
 	}
-	return p.client.EstimateGas(ctx, msg)
+	return contract, input, nil
+}
+
+// Close releases the connections of the client
+func (p *Provider) Close() error {
+	p.client.Close()
+	return nil
 }
 
 // SetLastSavedBlockHeightFunc sets the function to save the last saved block height
@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	provider "github.com/icon-project/centralized-relay/relayer/chains/evm/types"
 	"github.com/icon-project/centralized-relay/relayer/events"
 	"github.com/icon-project/centralized-relay/relayer/types"
@@ -17,6 +18,24 @@ func (p *Provider) QueryLatestHeight(ctx context.Context) (uint64, error) {
 	return p.client.GetBlockNumber(ctx)
 }
 
+// QueryBlockHash returns the hash of the block at the height
+func (p *Provider) QueryBlockHash(ctx context.Context, height uint64) (string, error) {
+	header, err := p.client.GetHeaderByHeight(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return "", err
+	}
+	return header.Hash().Hex(), nil
+}
+
+// QueryFinalizedHeight returns the height of the block tagged as finalized
+func (p *Provider) QueryFinalizedHeight(ctx context.Context) (uint64, error) {
+	header, err := p.client.GetHeaderByHeight(ctx, big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
 func (p *Provider) ShouldReceiveMessage(ctx context.Context, messagekey *types.Message) (bool, error) {
 	return true, nil
 }
@@ -45,7 +64,7 @@ func (p *Provider) QueryBalance(ctx context.Context, addr string) (*types.Coin,
 	if err != nil {
 		return nil, err
 	}
-	return &types.Coin{Amount: balance.Uint64(), Denom: "eth"}, nil
+	return &types.Coin{Amount: balance, Denom: "eth"}, nil
 }
 
 // TODO: may not be need anytime soon so its ok to implement later on
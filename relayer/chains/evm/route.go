@@ -3,6 +3,7 @@ package evm
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -23,21 +24,28 @@ const (
 
 // this will be executed in go route
 func (p *Provider) Route(ctx context.Context, message *providerTypes.Message, callback providerTypes.TxResponseFunc) error {
+	pool, err := p.walletPool(ctx)
+	if err != nil {
+		return fmt.Errorf("routing failed: %w", err)
+	}
+	wallet := pool.acquire()
+	defer wallet.release()
+
 	// lock here to prevent transcation replacement
-	p.routerMutex.Lock()
+	wallet.mu.Lock()
 
-	p.log.Info("starting to route message", zap.Any("message", message))
+	p.log.Info("starting to route message", zap.Any("message", message), zap.String("wallet", wallet.key.Address.Hex()))
 
-	opts, err := p.GetTransationOpts(ctx)
+	opts, err := p.transactOpts(ctx, wallet.key)
 	if err != nil {
-		p.routerMutex.Unlock()
+		wallet.mu.Unlock()
 		return fmt.Errorf("routing failed: %w", err)
 	}
 
 	messageKey := message.MessageKey()
 
 	tx, err := p.SendTransaction(ctx, opts, message)
-	p.routerMutex.Unlock()
+	wallet.mu.Unlock()
 	if err != nil {
 		return fmt.Errorf("routing failed: %w", err)
 	}
@@ -51,7 +59,7 @@ func (p *Provider) SendTransaction(ctx context.Context, opts *bind.TransactOpts,
 		err error
 	)
 
-	gasLimit, err := p.EstimateGas(ctx, message)
+	gasLimit, err := p.estimateGas(ctx, opts.From, message)
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate gas: %w", err)
 	}
@@ -99,12 +107,12 @@ func (p *Provider) SendTransaction(ctx context.Context, opts *bind.TransactOpts,
 	if err != nil {
 		switch p.parseErr(err) {
 		case ErrNonceTooLow, ErrNonceTooHigh, ErrorLessGas:
-			nonce, err := p.client.PendingNonceAt(ctx, p.wallet.Address, nil)
+			nonce, err := p.client.PendingNonceAt(ctx, opts.From, nil)
 			if err != nil {
 				return nil, err
 			}
 			p.log.Info("nonce mismatch", zap.Uint64("tx", opts.Nonce.Uint64()), zap.Uint64("current", nonce.Uint64()), zap.Error(err))
-			p.NonceTracker.Set(p.wallet.Address, nonce)
+			p.NonceTracker.Set(opts.From, nonce)
 		default:
 			return nil, err
 		}
@@ -120,8 +128,12 @@ func (p *Provider) WaitForTxResult(ctx context.Context, tx *types.Transaction, m
 
 	res := &providerTypes.TxResponse{
 		TxHash: tx.Hash().String(),
+		Nonce:  tx.Nonce(),
 	}
 
+	// report the broadcast so that it can be reconciled if the relayer stops here
+	callback(m, &providerTypes.TxResponse{TxHash: res.TxHash, Nonce: res.Nonce, Code: providerTypes.Pending}, nil)
+
 	txReceipts, err := p.WaitForResults(ctx, tx)
 	if err != nil {
 		p.log.Error("failed to get tx result", zap.String("hash", res.TxHash), zap.Any("message", m), zap.Error(err))
@@ -138,11 +150,20 @@ func (p *Provider) WaitForTxResult(ctx context.Context, tx *types.Transaction, m
 		return err
 	}
 	res.Code = providerTypes.Success
+	res.GasUsed, res.Fee = txCost(txReceipts)
 	callback(m, res, nil)
 	p.LogSuccessTx(m, txReceipts)
 	return nil
 }
 
+// txCost returns the gas used by the transaction of the receipt and the fee paid for it in wei
+func txCost(receipt *types.Receipt) (uint64, uint64) {
+	if receipt.EffectiveGasPrice == nil {
+		return receipt.GasUsed, 0
+	}
+	return receipt.GasUsed, new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)).Uint64()
+}
+
 func (p *Provider) LogSuccessTx(message *providerTypes.MessageKey, receipt *types.Receipt) {
 	p.log.Info("successful transaction",
 		zap.Any("message-key", message),
@@ -0,0 +1,101 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+const (
+	// WalletSelectionRoundRobin hands out the wallets of the pool in turn
+	WalletSelectionRoundRobin = "round-robin"
+	// WalletSelectionLeastPending hands out the wallet with the fewest transactions waiting for a receipt
+	WalletSelectionLeastPending = "least-pending"
+)
+
+// poolWallet is a signing key of the wallet pool
+type poolWallet struct {
+	key *keystore.Key
+	// mu serializes the nonce assignment and the broadcast of the wallet transactions
+	mu sync.Mutex
+	// pending counts the routes of the wallet waiting for a receipt
+	pending atomic.Int32
+}
+
+// release marks a route of the wallet as done
+func (w *poolWallet) release() {
+	w.pending.Add(-1)
+}
+
+// walletPool spreads the routes over the wallets of the chain, every wallet has its own nonce
+// stream so the transactions of different wallets are broadcast concurrently
+type walletPool struct {
+	selection string
+	wallets   []*poolWallet
+	mu        sync.Mutex
+	next      int
+}
+
+// primary returns the configured wallet, the admin of the connection
+func (w *walletPool) primary() *poolWallet {
+	return w.wallets[0]
+}
+
+// acquire picks the wallet of the next route, the caller releases it once the route is done
+func (w *walletPool) acquire() *poolWallet {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(w.wallets)
+	pick := w.next % n
+	if w.selection == WalletSelectionLeastPending {
+		// ties go to the next wallet in turn
+		for i := 1; i < n; i++ {
+			idx := (w.next + i) % n
+			if w.wallets[idx].pending.Load() < w.wallets[pick].pending.Load() {
+				pick = idx
+			}
+		}
+	}
+	w.next = pick + 1
+	wallet := w.wallets[pick]
+	wallet.pending.Add(1)
+	return wallet
+}
+
+// walletPool returns the wallet pool of the chain, the keys are restored and their nonces
+// fetched on the first call
+func (p *Provider) walletPool(ctx context.Context) (*walletPool, error) {
+	p.walletMu.Lock()
+	defer p.walletMu.Unlock()
+	if p.pool != nil {
+		return p.pool, nil
+	}
+
+	if p.wallet == nil {
+		if err := p.RestoreKeystore(ctx); err != nil {
+			return nil, err
+		}
+	}
+	pool := &walletPool{selection: p.cfg.WalletSelection}
+	for _, addr := range p.cfg.addresses() {
+		key := p.wallet
+		if addr != p.cfg.Address {
+			var err error
+			if key, err = p.restoreKey(ctx, addr); err != nil {
+				return nil, fmt.Errorf("failed to restore pool wallet %s: %w", addr, err)
+			}
+		}
+		nonce, err := p.client.PendingNonceAt(ctx, key.Address, nil)
+		if err != nil {
+			return nil, err
+		}
+		p.NonceTracker.Set(key.Address, nonce)
+		pool.wallets = append(pool.wallets, &poolWallet{key: key})
+	}
+	p.pool = pool
+	return pool, nil
+}
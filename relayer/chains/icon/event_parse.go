@@ -80,6 +80,7 @@ func (p *Provider) parseCallMessage(e *types.EventLog, eventType string, height
 		Data:          e.Data[1],
 		Sn:            sn,
 		Src:           src[0],
+		From:          string(e.Indexed[1]),
 	}, nil
 }
 
@@ -168,6 +169,7 @@ func (p *Provider) parseCallMessageEvent(height uint64, e *types.EventNotificati
 		Data:          data,
 		Sn:            sn,
 		Src:           src[0],
+		From:          e.Indexed[1],
 	}, nil
 }
 
@@ -86,6 +86,7 @@ func (p *Provider) Listener(ctx context.Context, lastSavedHeight uint64, incomin
 							return err
 						}
 						for _, msg := range msgs {
+							msg.TxHash = v.Hash.String()
 							p.log.Info("Detected eventlog",
 								zap.Uint64("height", msg.MessageHeight),
 								zap.String("target_network", msg.Dst),
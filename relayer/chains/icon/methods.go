@@ -14,4 +14,10 @@ var (
 	// XCALL Methods
 	MethodExecuteCall     = "executeCall"
 	MethodExecuteRollback = "executeRollback"
+
+	// Governance Methods
+	MethodGetStepPrice = "getStepPrice"
 )
+
+// GovernanceContract is the chain score exposing the network parameters
+var GovernanceContract = "cx0000000000000000000000000000000000000001"
@@ -193,9 +193,13 @@ func (p *Provider) SetAdmin(ctx context.Context, admin string) error {
 
 // GetFee
 func (p *Provider) GetFee(ctx context.Context, networkID string, responseFee bool) (uint64, error) {
+	response := types.NewHexInt(0)
+	if responseFee {
+		response = types.NewHexInt(1)
+	}
 	callParam := p.prepareCallParams(MethodGetFee, p.cfg.Contracts[providerTypes.ConnectionContract], map[string]interface{}{
 		"to":       networkID,
-		"response": types.NewHexInt(1),
+		"response": response,
 	})
 
 	var status types.HexInt
@@ -233,6 +237,47 @@ func (p *Provider) SetFee(ctx context.Context, networkID string, msgFee, resFee
 	return nil
 }
 
+// QueryClaimableFee returns the balance of the connection contract, claimFees sends all of it to the admin
+func (p *Provider) QueryClaimableFee(ctx context.Context) (*big.Int, error) {
+	return p.client.GetBalance(&types.AddressParam{
+		Address: types.Address(p.cfg.Contracts[providerTypes.ConnectionContract]),
+	})
+}
+
+// Transfer sends loop from the relayer wallet
+func (p *Provider) Transfer(ctx context.Context, to string, amount *big.Int) error {
+	wallet, err := p.Wallet()
+	if err != nil {
+		return err
+	}
+	txParam := types.TransactionParam{
+		Version:     types.NewHexInt(JsonrpcApiVersion),
+		FromAddress: types.NewAddress(wallet.Address().Bytes()),
+		ToAddress:   types.Address(to),
+		Value:       types.NewHexString(amount.Text(16)),
+		NetworkID:   p.NetworkID(),
+	}
+	steps, err := p.estimateSteps(txParam)
+	if err != nil {
+		return fmt.Errorf("Transfer: %v", err)
+	}
+	txParam.StepLimit = types.NewHexInt(steps)
+	if err := p.client.SignTransaction(wallet, &txParam); err != nil {
+		return fmt.Errorf("Transfer: %v", err)
+	}
+	if _, err := p.client.SendTransaction(&txParam); err != nil {
+		return fmt.Errorf("Transfer: %v", err)
+	}
+	txr, err := p.client.WaitForResults(ctx, &types.TransactionHashParam{Hash: txParam.TxHash})
+	if err != nil {
+		return fmt.Errorf("Transfer: WaitForResults: %v", err)
+	}
+	if txr.Status != types.NewHexInt(1) {
+		return fmt.Errorf("Transfer: failed to transfer: %s", txr.TxHash)
+	}
+	return nil
+}
+
 // ClaimFees
 func (p *Provider) ClaimFee(ctx context.Context) error {
 	msg := p.NewIconMessage(types.Address(p.cfg.Contracts[providerTypes.ConnectionContract]), map[string]interface{}{}, MethodClaimFees)
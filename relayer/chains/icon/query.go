@@ -63,7 +63,7 @@ func (ip *Provider) QueryBalance(ctx context.Context, addr string) (*providerTyp
 	if err != nil {
 		return nil, err
 	}
-	return providerTypes.NewCoin("ICX", balance.Uint64()), nil
+	return providerTypes.NewCoin("ICX", balance), nil
 }
 
 func (p *Provider) GenerateMessages(ctx context.Context, key *providerTypes.MessageKeyWithMessageHeight) ([]*providerTypes.Message, error) {
@@ -115,6 +115,7 @@ func (p *Provider) GenerateMessages(ctx context.Context, key *providerTypes.Mess
 				}
 				msg := &providerTypes.Message{
 					MessageHeight: height.Uint64(),
+					TxHash:        res.TxHash.String(),
 					EventType:     eventType,
 					Dst:           dst,
 					Src:           key.Src,
@@ -143,6 +144,7 @@ func (p *Provider) GenerateMessages(ctx context.Context, key *providerTypes.Mess
 				}
 				msg := &providerTypes.Message{
 					MessageHeight: height.Uint64(),
+					TxHash:        res.TxHash.String(),
 					EventType:     p.GetEventName(el.Indexed[0]),
 					Dst:           dst,
 					Src:           src[0],
@@ -161,6 +163,7 @@ func (p *Provider) GenerateMessages(ctx context.Context, key *providerTypes.Mess
 				}
 				msg := &providerTypes.Message{
 					MessageHeight: height.Uint64(),
+					TxHash:        res.TxHash.String(),
 					EventType:     p.GetEventName(el.Indexed[0]),
 					Dst:           p.NID(),
 					Src:           p.NID(),
@@ -176,6 +179,16 @@ func (p *Provider) GenerateMessages(ctx context.Context, key *providerTypes.Mess
 	return messages, nil
 }
 
+// QueryBlockHash is not tracked, icon blocks are final once committed
+func (p *Provider) QueryBlockHash(ctx context.Context, height uint64) (string, error) {
+	return "", nil
+}
+
+// QueryFinalizedHeight returns the latest height, icon blocks are final once committed
+func (p *Provider) QueryFinalizedHeight(ctx context.Context) (uint64, error) {
+	return p.QueryLatestHeight(ctx)
+}
+
 // QueryTransactionReceipt ->
 // TxHash should be in hex string
 func (p *Provider) QueryTransactionReceipt(ctx context.Context, txHash string) (*providerTypes.Receipt, error) {
@@ -3,10 +3,12 @@ package icon
 import (
 	"context"
 	"fmt"
+	"math/big"
 
 	"github.com/icon-project/centralized-relay/relayer/chains/icon/types"
 	"github.com/icon-project/centralized-relay/relayer/events"
 	providerTypes "github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/icon-project/goloop/module"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -75,49 +77,90 @@ func (p *Provider) SendTransaction(ctx context.Context, msg *IconMessage) ([]byt
 		return nil, err
 	}
 
-	txParam := types.TransactionParam{
+	txParam := p.transactionParam(wallet, msg)
+	steps, err := p.estimateSteps(txParam)
+	if err != nil {
+		return nil, err
+	}
+
+	txParam.StepLimit = types.NewHexInt(steps)
+
+	if err := p.client.SignTransaction(wallet, &txParam); err != nil {
+		return nil, err
+	}
+
+	_, err = p.client.SendTransaction(&txParam)
+	if err != nil {
+		return nil, err
+	}
+	return txParam.TxHash.Value()
+}
+
+func (p *Provider) transactionParam(wallet module.Wallet, msg *IconMessage) types.TransactionParam {
+	return types.TransactionParam{
 		Version:     types.NewHexInt(JsonrpcApiVersion),
 		FromAddress: types.NewAddress(wallet.Address().Bytes()),
 		ToAddress:   msg.Address,
 		NetworkID:   p.NetworkID(),
 		DataType:    "call",
-		Data: types.CallData{
+		Data: &types.CallData{
 			Method: msg.Method,
 			Params: msg.Params,
 		},
 	}
+}
 
+// estimateSteps returns the adjusted step limit of the transaction
+func (p *Provider) estimateSteps(txParam types.TransactionParam) (int64, error) {
 	step, err := p.client.EstimateStep(txParam)
 	if err != nil {
-		return nil, fmt.Errorf("failed estimating step: %w", err)
+		return 0, fmt.Errorf("failed estimating step: %w", err)
 	}
 
 	steps, err := step.Int64()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	if steps > p.cfg.StepLimit {
-		return nil, fmt.Errorf("step limit is too high: %d", steps)
+		return 0, fmt.Errorf("step limit is too high: %d", steps)
 	}
 
 	if steps < p.cfg.StepMin {
-		return nil, fmt.Errorf("step limit is too low: %d", steps)
+		return 0, fmt.Errorf("step limit is too low: %d", steps)
 	}
 
-	steps += steps * p.cfg.StepAdjustment / 100
-
-	txParam.StepLimit = types.NewHexInt(steps)
+	return steps + steps*p.cfg.StepAdjustment/100, nil
+}
 
-	if err := p.client.SignTransaction(wallet, &txParam); err != nil {
-		return nil, err
+// EstimateCost returns the fee in loop of the transaction delivering the message
+func (p *Provider) EstimateCost(ctx context.Context, message *providerTypes.Message) (uint64, error) {
+	iconMessage, err := p.MakeIconMessage(message)
+	if err != nil {
+		return 0, err
+	}
+	wallet, err := p.Wallet()
+	if err != nil {
+		return 0, err
+	}
+	steps, err := p.estimateSteps(p.transactionParam(wallet, iconMessage))
+	if err != nil {
+		return 0, err
 	}
 
-	_, err = p.client.SendTransaction(&txParam)
+	callParam := p.prepareCallParams(MethodGetStepPrice, GovernanceContract, nil)
+	// the method takes no params, a null value is refused
+	callParam.Data.Params = nil
+
+	var stepPrice types.HexInt
+	if err := p.client.Call(callParam, &stepPrice); err != nil {
+		return 0, fmt.Errorf("failed to get step price: %w", err)
+	}
+	price, err := stepPrice.BigInt()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return txParam.TxHash.Value()
+	return price.Mul(price, big.NewInt(steps)).Uint64(), nil
 }
 
 // TODO: review try to remove wait for Tx from packet-transfer and only use this for client and connection creation
@@ -138,6 +181,9 @@ func (p *Provider) WaitForTxResult(
 		TxHash: string(txhash),
 	}
 
+	// report the broadcast so that it can be reconciled if the relayer stops here
+	callback(messageKey, &providerTypes.TxResponse{TxHash: res.TxHash, Code: providerTypes.Pending}, nil)
+
 	txRes, err := p.client.WaitForResults(ctx, &types.TransactionHashParam{Hash: txhash})
 	if err != nil {
 		p.log.Error("get txn result failed", zap.String("txHash", string(txhash)), zap.String("method", method), zap.Error(err))
@@ -159,11 +205,25 @@ func (p *Provider) WaitForTxResult(
 		return err
 	}
 	res.Code = providerTypes.Success
+	res.GasUsed, res.Fee = txCost(txRes)
 	callback(messageKey, res, nil)
 	p.LogSuccessTx(method, txRes)
 	return nil
 }
 
+// txCost returns the steps used by the transaction of the result and the fee paid for them in loop
+func txCost(result *types.TransactionResult) (uint64, uint64) {
+	used, err := result.StepUsed.BigInt()
+	if err != nil {
+		return 0, 0
+	}
+	price, err := result.StepPrice.BigInt()
+	if err != nil {
+		return used.Uint64(), 0
+	}
+	return used.Uint64(), new(big.Int).Mul(used, price).Uint64()
+}
+
 func (p *Provider) LogSuccessTx(method string, result *types.TransactionResult) {
 	stepUsed, err := result.StepUsed.Value()
 	if err != nil {
@@ -2,7 +2,11 @@ package mockchain
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/icon-project/centralized-relay/relayer/kms"
@@ -17,7 +21,9 @@ type MockProviderConfig struct {
 	SendMessages    map[types.MessageKey]*types.Message
 	ReceiveMessages map[types.MessageKey]*types.Message
 	StartHeight     uint64
-	chainName       string
+	// Wallets are the pool wallets signing along the relayer wallet
+	Wallets   []string
+	chainName string
 }
 
 // NewProvider should provide a new Mock provider
@@ -50,10 +56,133 @@ func (pp *MockProviderConfig) GetWallet() string {
 func (pp *MockProviderConfig) SetWallet(string) {
 }
 
+func (pp *MockProviderConfig) GetWallets() []string {
+	return pp.Wallets
+}
+
+func (pp *MockProviderConfig) SetWallets(addrs []string) {
+	pp.Wallets = addrs
+}
+
 type MockProvider struct {
 	log    *zap.Logger
 	PCfg   *MockProviderConfig
 	Height uint64
+	mu     sync.Mutex
+	// hashes overrides the hash of the blocks, to simulate reorgs
+	hashes map[uint64]string
+	// listenerErrs are returned by the next listener runs, to simulate a failing rpc
+	listenerErrs []error
+	// watchers counts the goroutines started by the listener runs, each lives until the ctx of its run is done
+	watchers atomic.Int32
+	// fee and resFee are charged for every route and cost is paid for every delivery, in the chain denomination
+	fee, resFee, cost uint64
+	// claimable is accrued on the connection, claimed is the total claimed and transfers the total sent per recipient
+	claimable, claimed uint64
+	// reserve is kept on the connection by the claims
+	reserve   uint64
+	transfers map[string]uint64
+	// balance is the balance of the relayer wallets, not reported when nil,
+	// walletBalances overrides it per wallet
+	balance        *types.Coin
+	walletBalances map[string]*types.Coin
+	// batches are the sizes of the routed batches, batchErr fails the next batch and
+	// batchFails the message of the sn in the next batch it is part of
+	batches    []int
+	batchErr   error
+	batchFails map[string]error
+	// finality is the depth of the destination finality, the deliveries are final at once when 0
+	finality uint64
+	// closed is set once the provider is closed
+	closed bool
+}
+
+// FailBatch makes the next batch fail with the error
+func (p *MockProvider) FailBatch(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.batchErr = err
+}
+
+// FailInBatch makes the message of the sn fail on its own in the next batch it is part of
+func (p *MockProvider) FailInBatch(sn *big.Int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.batchFails == nil {
+		p.batchFails = make(map[string]error)
+	}
+	p.batchFails[sn.String()] = err
+}
+
+// Batches returns the sizes of the routed batches
+func (p *MockProvider) Batches() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.batches)
+}
+
+// SetBalance sets the balance of the relayer wallets
+func (p *MockProvider) SetBalance(amount *big.Int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.balance = types.NewCoin("mock", amount)
+}
+
+// SetWalletBalance sets the balance of a single relayer wallet
+func (p *MockProvider) SetWalletBalance(addr string, amount *big.Int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.walletBalances == nil {
+		p.walletBalances = make(map[string]*types.Coin)
+	}
+	p.walletBalances[addr] = types.NewCoin("mock", amount)
+}
+
+// SetClaimableFee sets the fees accrued on the connection
+func (p *MockProvider) SetClaimableFee(amount uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.claimable = amount
+}
+
+// SetClaimReserve makes the claims keep the amount on the connection
+func (p *MockProvider) SetClaimReserve(amount uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reserve = amount
+}
+
+// Claimed returns the total of the claimed fees
+func (p *MockProvider) Claimed() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.claimed
+}
+
+// Transferred returns the total sent to the recipient
+func (p *MockProvider) Transferred(to string) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.transfers[to]
+}
+
+// SetFees sets the fee charged by the connection and the cost of delivering a message
+func (p *MockProvider) SetFees(fee, cost uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fee, p.cost = fee, cost
+}
+
+// Watchers returns the number of goroutines of the listener runs still alive
+func (p *MockProvider) Watchers() int {
+	return int(p.watchers.Load())
+}
+
+// FailListener makes the next listener runs fail with the given errors, one per run
+func (p *MockProvider) FailListener(errs ...error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listenerErrs = append(p.listenerErrs, errs...)
 }
 
 func (p *MockProvider) NID() string {
@@ -65,7 +194,16 @@ func (p *MockProvider) Name() string {
 }
 
 func (p *MockProvider) FinalityBlock(ctx context.Context) uint64 {
-	return 0
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.finality
+}
+
+// SetFinalityBlock sets the depth of the destination finality
+func (p *MockProvider) SetFinalityBlock(depth uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finality = depth
 }
 
 func (p *MockProvider) Type() string {
@@ -81,18 +219,36 @@ func (p *MockProvider) ChainName() string {
 }
 
 func (p *MockProvider) QueryLatestHeight(ctx context.Context) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	return p.Height, nil
 }
 
 func (p *MockProvider) Listener(ctx context.Context, lastSavedHeight uint64, blockInfo chan *types.BlockInfo) error {
-	ticker := time.NewTicker(1 * time.Second)
-
+	// a subscription outliving the run, as the chain listeners start
+	p.watchers.Add(1)
+	go func() {
+		defer p.watchers.Add(-1)
+		<-ctx.Done()
+	}()
+
+	p.mu.Lock()
+	if len(p.listenerErrs) > 0 {
+		err := p.listenerErrs[0]
+		p.listenerErrs = p.listenerErrs[1:]
+		p.mu.Unlock()
+		return err
+	}
 	if p.Height == 0 {
 		if lastSavedHeight != 0 {
 			p.Height = lastSavedHeight
 		}
 	}
-	p.log.Info("listening to mock provider from height", zap.Uint64("Height", p.Height))
+	p.mu.Unlock()
+	height, _ := p.QueryLatestHeight(ctx)
+	p.log.Info("listening to mock provider from height", zap.Uint64("Height", height))
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
@@ -102,10 +258,13 @@ func (p *MockProvider) Listener(ctx context.Context, lastSavedHeight uint64, blo
 			msgs := p.FindMessages()
 			d := types.BlockInfo{
 				Height:   uint64(height),
+				Hash:     p.blockHash(height),
 				Messages: msgs,
 			}
 			blockInfo <- &d
+			p.mu.Lock()
 			p.Height += 1
+			p.mu.Unlock()
 		}
 	}
 }
@@ -114,24 +273,114 @@ func (p *MockProvider) Route(ctx context.Context, message *types.Message, callba
 	p.log.Info("message received", zap.Any("message", message))
 	messageKey := message.MessageKey()
 
+	txHash := fmt.Sprintf("%s-%s", message.Src, message.Sn)
+	callback(messageKey, &types.TxResponse{
+		TxHash: txHash,
+		Code:   types.Pending,
+	}, nil)
+
 	p.DeleteMessage(message)
+	p.mu.Lock()
+	cost, height := p.cost, p.Height
+	p.mu.Unlock()
 	callback(messageKey, &types.TxResponse{
-		Code: types.Success,
+		TxHash: txHash,
+		Height: int64(height),
+		Code:   types.Success,
+		Fee:    cost,
 	}, nil)
 	return nil
 }
 
+func (p *MockProvider) SupportsBatch() bool {
+	return true
+}
+
+func (p *MockProvider) RouteBatch(ctx context.Context, messages []*types.Message, callback types.TxResponseFunc) error {
+	p.mu.Lock()
+	p.batches = append(p.batches, len(messages))
+	err := p.batchErr
+	p.batchErr = nil
+	fails := make(map[string]error)
+	for _, message := range messages {
+		if err, ok := p.batchFails[message.Sn.String()]; ok {
+			fails[message.Sn.String()] = err
+			delete(p.batchFails, message.Sn.String())
+		}
+	}
+	cost := p.cost
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	txHash := fmt.Sprintf("batch-%s-%s", messages[0].Src, messages[0].Sn)
+	for _, message := range messages {
+		callback(message.MessageKey(), &types.TxResponse{TxHash: txHash, Code: types.Pending}, nil)
+	}
+	// the messages share the cost of the transaction
+	res := &types.TxResponse{TxHash: txHash, Fee: cost}
+	for i, message := range messages {
+		share := res.Share(i, len(messages))
+		if err, ok := fails[message.Sn.String()]; ok {
+			share.Code = types.Failed
+			callback(message.MessageKey(), share, err)
+			continue
+		}
+		p.log.Info("message received", zap.Any("message", message))
+		p.DeleteMessage(message)
+		share.Code = types.Success
+		callback(message.MessageKey(), share, nil)
+	}
+	return nil
+}
+
 func (p *MockProvider) FindMessages() []*types.Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.findMessages(p.Height)
+}
+
+func (p *MockProvider) findMessages(height uint64) []*types.Message {
 	messages := make([]*types.Message, 0)
 	for _, m := range p.PCfg.SendMessages {
-		if m.MessageHeight == p.Height {
+		if m.MessageHeight == height {
 			messages = append(messages, m)
 		}
 	}
 	return messages
 }
 
+// SetBlockHash replaces the hash of the block at the height, as a reorg would
+func (p *MockProvider) SetBlockHash(height uint64, hash string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.hashes == nil {
+		p.hashes = make(map[uint64]string)
+	}
+	p.hashes[height] = hash
+}
+
+func (p *MockProvider) blockHash(height uint64) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if hash, ok := p.hashes[height]; ok {
+		return hash
+	}
+	return fmt.Sprintf("%s-%d", p.PCfg.NId, height)
+}
+
+func (p *MockProvider) QueryFinalizedHeight(ctx context.Context) (uint64, error) {
+	return p.QueryLatestHeight(ctx)
+}
+
+func (p *MockProvider) QueryBlockHash(ctx context.Context, height uint64) (string, error) {
+	return p.blockHash(height), nil
+}
+
 func (p *MockProvider) DeleteMessage(msg *types.Message) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	var deleteKey types.MessageKey
 
 	for key := range p.PCfg.ReceiveMessages {
@@ -144,6 +393,28 @@ func (p *MockProvider) DeleteMessage(msg *types.Message) {
 	delete(p.PCfg.ReceiveMessages, deleteKey)
 }
 
+// Close marks the provider closed
+func (p *MockProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+// Closed reports whether the provider was closed
+func (p *MockProvider) Closed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// PendingReceiveCount returns the number of messages still expected on the chain
+func (p *MockProvider) PendingReceiveCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.PCfg.ReceiveMessages)
+}
+
 func (p *MockProvider) ShouldReceiveMessage(ctx context.Context, message *types.Message) (bool, error) {
 	return true, nil
 }
@@ -153,15 +424,22 @@ func (p *MockProvider) ShouldSendMessage(ctx context.Context, message *types.Mes
 }
 
 func (p *MockProvider) QueryBalance(ctx context.Context, addr string) (*types.Coin, error) {
-	return nil, nil
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if balance, ok := p.walletBalances[addr]; ok {
+		return balance, nil
+	}
+	return p.balance, nil
 }
 
 func (p *MockProvider) QueryTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
 	return nil, nil
 }
 
-func (ip *MockProvider) GenerateMessages(ctx context.Context, messageKey *types.MessageKeyWithMessageHeight) ([]*types.Message, error) {
-	return nil, nil
+func (p *MockProvider) GenerateMessages(ctx context.Context, messageKey *types.MessageKeyWithMessageHeight) ([]*types.Message, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.findMessages(messageKey.Height), nil
 }
 
 func (p *MockProvider) MessageReceived(ctx context.Context, key *types.MessageKey) (bool, error) {
@@ -169,11 +447,43 @@ func (p *MockProvider) MessageReceived(ctx context.Context, key *types.MessageKe
 }
 
 func (p *MockProvider) ClaimFee(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	kept := min(p.reserve, p.claimable)
+	p.claimed += p.claimable - kept
+	p.claimable = kept
 	return nil
 }
 
-func (p *MockProvider) GetFee(context.Context, string, bool) (uint64, error) {
-	return 0, nil
+func (p *MockProvider) QueryClaimableFee(context.Context) (*big.Int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return new(big.Int).SetUint64(p.claimable), nil
+}
+
+func (p *MockProvider) Transfer(_ context.Context, to string, amount *big.Int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.transfers == nil {
+		p.transfers = make(map[string]uint64)
+	}
+	p.transfers[to] += amount.Uint64()
+	return nil
+}
+
+func (p *MockProvider) GetFee(_ context.Context, _ string, responseFee bool) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if responseFee {
+		return p.fee + p.resFee, nil
+	}
+	return p.fee, nil
+}
+
+func (p *MockProvider) EstimateCost(context.Context, *types.Message) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cost, nil
 }
 
 func (p *MockProvider) NewKeystore(string) (string, error) {
@@ -198,7 +508,10 @@ func (p *MockProvider) SetAdmin(context.Context, string) error {
 	return nil
 }
 
-func (p *MockProvider) SetFee(context.Context, string, *big.Int, *big.Int) error {
+func (p *MockProvider) SetFee(_ context.Context, _ string, msgFee, resFee *big.Int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fee, p.resFee = msgFee.Uint64(), resFee.Uint64()
 	return nil
 }
 
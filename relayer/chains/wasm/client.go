@@ -2,6 +2,7 @@ package wasm
 
 import (
 	"context"
+	"errors"
 	"strconv"
 
 	jsoniter "github.com/json-iterator/go"
@@ -28,6 +29,7 @@ type IClient interface {
 	HTTP(rpcUrl string) (*http.HTTP, error)
 	IsConnected() bool
 	Reconnect() error
+	Close() error
 	GetLatestBlockHeight(ctx context.Context) (uint64, error)
 	GetTransactionReceipt(ctx context.Context, txHash string) (*txTypes.GetTxResponse, error)
 	GetBalance(ctx context.Context, addr string, denomination string) (*sdkTypes.Coin, error)
@@ -246,6 +248,18 @@ func (c *Client) IsConnected() bool {
 	return c.ctx.Client.(*http.HTTP).IsRunning()
 }
 
+// Close stops the rpc client and closes the grpc connection
+func (c *Client) Close() error {
+	var errs []error
+	if client := c.ctx.Client.(*http.HTTP); client.IsRunning() {
+		errs = append(errs, client.Stop())
+	}
+	if c.ctx.GRPCClient != nil {
+		errs = append(errs, c.ctx.GRPCClient.Close())
+	}
+	return errors.Join(errs...)
+}
+
 // RestartClient restarts the client
 func (c *Client) Reconnect() error {
 	client, err := c.HTTP(c.ctx.NodeURI)
@@ -79,6 +79,7 @@ func (p *Provider) ParseMessageFromEvents(eventsList []abiTypes.Event) ([]*relay
 					msg.Data = []byte(attr.Value)
 				case EventAttrKeyFrom:
 					msg.Src = attr.Value
+					msg.From = attr.Value
 				case EventAttrKeySn:
 					sn, ok := new(big.Int).SetString(attr.Value, 10)
 					if !ok {
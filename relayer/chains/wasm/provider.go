@@ -13,6 +13,7 @@ import (
 	coreTypes "github.com/cometbft/cometbft/rpc/core/types"
 	sdkTypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/errors"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/icon-project/centralized-relay/relayer/chains/wasm/types"
 	"github.com/icon-project/centralized-relay/relayer/events"
 	"github.com/icon-project/centralized-relay/relayer/kms"
@@ -22,7 +23,10 @@ import (
 	"go.uber.org/zap"
 )
 
-var _ provider.ChainProvider = (*Provider)(nil)
+var (
+	_ provider.ChainProvider = (*Provider)(nil)
+	_ provider.BatchRouter   = (*Provider)(nil)
+)
 
 type Provider struct {
 	logger              *zap.Logger
@@ -40,6 +44,16 @@ func (p *Provider) QueryLatestHeight(ctx context.Context) (uint64, error) {
 	return p.client.GetLatestBlockHeight(ctx)
 }
 
+// QueryBlockHash is not tracked, cometbft blocks are final once committed
+func (p *Provider) QueryBlockHash(ctx context.Context, height uint64) (string, error) {
+	return "", nil
+}
+
+// QueryFinalizedHeight returns the latest height, cometbft blocks are final once committed
+func (p *Provider) QueryFinalizedHeight(ctx context.Context) (uint64, error) {
+	return p.QueryLatestHeight(ctx)
+}
+
 func (p *Provider) QueryTransactionReceipt(ctx context.Context, txHash string) (*relayTypes.Receipt, error) {
 	res, err := p.client.GetTransactionReceipt(ctx, txHash)
 	if err != nil {
@@ -162,15 +176,81 @@ func (p *Provider) Route(ctx context.Context, message *relayTypes.Message, callb
 	if err != nil {
 		return err
 	}
-	seq := p.wallet.GetSequence() + 1
-	if err := p.wallet.SetSequence(seq); err != nil {
+	seq := p.wallet.GetSequence()
+	if err := p.wallet.SetSequence(seq + 1); err != nil {
 		p.logger.Error("failed to set sequence", zap.Error(err))
 	}
+	// report the broadcast so that it can be reconciled if the relayer stops here
+	callback(message.MessageKey(), &relayTypes.TxResponse{TxHash: res.TxHash, Nonce: seq, Code: relayTypes.Pending}, nil)
 	return p.waitForTxResult(ctx, message.MessageKey(), res, callback)
 }
 
+// SupportsBatch reports whether the messages can be batched, a cosmos transaction takes several messages
+func (p *Provider) SupportsBatch() bool {
+	return true
+}
+
+// RouteBatch delivers the messages in one transaction, one contract execution per message. The
+// transaction is atomic, the messages succeed or fail together and each reports its share of the cost.
+func (p *Provider) RouteBatch(ctx context.Context, messages []*relayTypes.Message, callback relayTypes.TxResponseFunc) error {
+	p.logger.Info("starting to route batch", zap.Int("size", len(messages)))
+	msgs := make([]sdkTypes.Msg, 0, len(messages))
+	for _, message := range messages {
+		msg, err := p.executeContractMsg(message)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+	}
+	res, err := p.broadcast(ctx, msgs...)
+	if err != nil {
+		return err
+	}
+	seq := p.wallet.GetSequence()
+	if err := p.wallet.SetSequence(seq + 1); err != nil {
+		p.logger.Error("failed to set sequence", zap.Error(err))
+	}
+	for _, message := range messages {
+		callback(message.MessageKey(), &relayTypes.TxResponse{TxHash: res.TxHash, Nonce: seq, Code: relayTypes.Pending}, nil)
+	}
+
+	result, err := p.subscribeTxResult(ctx, res, p.cfg.TxConfirmationInterval)
+	for i, message := range messages {
+		callback(message.MessageKey(), result.TxResult.Share(i, len(messages)), err)
+	}
+	if err != nil {
+		p.logTxFailed(err, res)
+		return err
+	}
+	p.logTxSuccess(result)
+	return nil
+}
+
 // call the smart contract to send the message
 func (p *Provider) call(ctx context.Context, message *relayTypes.Message) (*sdkTypes.TxResponse, error) {
+	msg, err := p.executeContractMsg(message)
+	if err != nil {
+		return nil, err
+	}
+	return p.broadcast(ctx, msg)
+}
+
+// broadcast sends the messages in one transaction, the sequence is synced once on a mismatch
+func (p *Provider) broadcast(ctx context.Context, msgs ...sdkTypes.Msg) (*sdkTypes.TxResponse, error) {
+	res, err := p.sendMessage(ctx, msgs...)
+	if err != nil {
+		if strings.Contains(err.Error(), errors.ErrWrongSequence.Error()) {
+			if mmErr := p.handleSequence(ctx); mmErr != nil {
+				return res, fmt.Errorf("failed to handle sequence mismatch error: %v || %v", mmErr, err)
+			}
+			return p.sendMessage(ctx, msgs...)
+		}
+	}
+	return res, err
+}
+
+// executeContractMsg builds the contract execution delivering the message
+func (p *Provider) executeContractMsg(message *relayTypes.Message) (sdkTypes.Msg, error) {
 	rawMsg, err := p.getRawContractMessage(message)
 	if err != nil {
 		return nil, err
@@ -187,24 +267,43 @@ func (p *Provider) call(ctx context.Context, message *relayTypes.Message) (*sdkT
 		return nil, fmt.Errorf("unknown event type: %s ", message.EventType)
 	}
 
-	msg := &wasmTypes.MsgExecuteContract{
+	return &wasmTypes.MsgExecuteContract{
 		Sender:   p.Wallet().String(),
 		Contract: contract,
 		Msg:      rawMsg,
-	}
+	}, nil
+}
 
-	msgs := []sdkTypes.Msg{msg}
+// EstimateCost returns the fee in the chain denomination of the transaction delivering the message,
+// the gas is simulated and priced with the configured gas prices
+func (p *Provider) EstimateCost(ctx context.Context, message *relayTypes.Message) (uint64, error) {
+	msg, err := p.executeContractMsg(message)
+	if err != nil {
+		return 0, err
+	}
+	txf, err := p.client.BuildTxFactory()
+	if err != nil {
+		return 0, err
+	}
+	txf = txf.
+		WithGasPrices(p.cfg.GasPrices).
+		WithGasAdjustment(p.cfg.GasAdjustment).
+		WithAccountNumber(p.wallet.GetAccountNumber()).
+		WithSequence(p.wallet.GetSequence())
+	_, gas, err := p.client.EstimateGas(txf, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to simulate: %w", err)
+	}
+	return types.TxFee(p.cfg.GasPrices, p.cfg.Denomination, gas)
+}
 
-	res, err := p.sendMessage(ctx, msgs...)
+// txFee returns the fee paid for the gas used by a transaction, nothing when the gas prices are invalid
+func (p *Provider) txFee(gasUsed int64) uint64 {
+	fee, err := types.TxFee(p.cfg.GasPrices, p.cfg.Denomination, uint64(gasUsed))
 	if err != nil {
-		if strings.Contains(err.Error(), errors.ErrWrongSequence.Error()) {
-			if mmErr := p.handleSequence(ctx); mmErr != nil {
-				return res, fmt.Errorf("failed to handle sequence mismatch error: %v || %v", mmErr, err)
-			}
-			return p.sendMessage(ctx, msgs...)
-		}
+		p.logger.Warn("failed to compute the transaction fee", zap.Error(err))
 	}
-	return res, err
+	return fee
 }
 
 func (p *Provider) sendMessage(ctx context.Context, msgs ...sdkTypes.Msg) (*sdkTypes.TxResponse, error) {
@@ -307,6 +406,8 @@ func (p *Provider) pollTxResultStream(ctx context.Context, txHash string, maxWai
 						Codespace: res.TxResponse.Codespace,
 						Code:      relayTypes.ResponseCode(res.TxResponse.Code),
 						Data:      res.TxResponse.Data,
+						GasUsed:   uint64(res.TxResponse.GasUsed),
+						Fee:       p.txFee(res.TxResponse.GasUsed),
 					},
 				}
 				return
@@ -358,6 +459,8 @@ func (p *Provider) subscribeTxResult(ctx context.Context, tx *sdkTypes.TxRespons
 					TxHash:    tx.TxHash,
 					Codespace: txRes.Result.Codespace,
 					Data:      string(txRes.Result.Data),
+					GasUsed:   uint64(txRes.Result.GasUsed),
+					Fee:       p.txFee(txRes.Result.GasUsed),
 				},
 			}
 			if uint32(txRes.Result.Code) != types.CodeTypeOK {
@@ -408,7 +511,7 @@ func (p *Provider) QueryBalance(ctx context.Context, addr string) (*relayTypes.C
 	}
 	return &relayTypes.Coin{
 		Denom:  coin.Denom,
-		Amount: coin.Amount.BigInt().Uint64(),
+		Amount: coin.Amount.BigInt(),
 	}, nil
 }
 
@@ -457,6 +560,33 @@ func (p *Provider) SetFee(ctx context.Context, networkdID string, msgFee, resFee
 	return err
 }
 
+// QueryClaimableFee returns the balance of the connection contract, claim_fees sends all of it to the admin
+func (p *Provider) QueryClaimableFee(ctx context.Context) (*big.Int, error) {
+	coin, err := p.client.GetBalance(ctx, p.cfg.Contracts[relayTypes.ConnectionContract], p.cfg.Denomination)
+	if err != nil {
+		return nil, err
+	}
+	return coin.Amount.BigInt(), nil
+}
+
+// Transfer sends the chain denomination from the relayer wallet
+func (p *Provider) Transfer(ctx context.Context, to string, amount *big.Int) error {
+	msg := &bankTypes.MsgSend{
+		FromAddress: p.Wallet().String(),
+		ToAddress:   to,
+		Amount:      sdkTypes.NewCoins(sdkTypes.NewCoin(p.cfg.Denomination, sdkTypes.NewIntFromBigInt(amount))),
+	}
+	res, err := p.sendMessage(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if err := p.wallet.SetSequence(p.wallet.GetSequence() + 1); err != nil {
+		p.logger.Error("failed to set sequence", zap.Error(err))
+	}
+	_, err = p.subscribeTxResult(ctx, res, p.cfg.TxConfirmationInterval)
+	return err
+}
+
 // ClaimFee
 func (p *Provider) ClaimFee(ctx context.Context) error {
 	msg := &relayTypes.Message{
@@ -617,6 +747,7 @@ func (p *Provider) getMessagesFromTxList(resultTxList []*coreTypes.ResultTx) ([]
 		}
 		for _, msg := range msgs {
 			msg.MessageHeight = uint64(resultTx.Height)
+			msg.TxHash = resultTx.Hash.String()
 			p.logger.Info("Detected eventlog",
 				zap.Uint64("height", msg.MessageHeight),
 				zap.String("target_network", msg.Dst),
@@ -732,6 +863,11 @@ func (p *Provider) SubscribeMessageEvents(ctx context.Context, blockInfoChan cha
 				p.logger.Error("failed to parse message from events", zap.Error(err))
 				continue
 			}
+			if hashes := e.Events["tx.hash"]; len(hashes) > 0 {
+				for _, msg := range msgs {
+					msg.TxHash = hashes[0]
+				}
+			}
 			messages = append(messages, msgs...)
 			blockInfo := &relayTypes.BlockInfo{
 				Height:   uint64(res.Height),
@@ -763,6 +899,11 @@ func (p *Provider) SubscribeMessageEvents(ctx context.Context, blockInfoChan cha
 	}
 }
 
+// Close releases the connections of the client
+func (p *Provider) Close() error {
+	return p.client.Close()
+}
+
 // SetLastSavedHeightFunc sets the function to save the last saved height
 func (p *Provider) SetLastSavedHeightFunc(f func() uint64) {
 	p.LastSavedHeightFunc = f
@@ -63,6 +63,7 @@ type TxResultResponse struct {
 		Codespace string           `json:"codespace"`
 		Data      []byte           `json:"data"`
 		Log       string           `json:"log"`
+		GasUsed   int64            `json:"gas_used"`
 		Events    []abiTypes.Event `json:"events"`
 	} `json:"result"`
 }
@@ -72,6 +73,15 @@ type TxResult struct {
 	Error    error
 }
 
+// TxFee returns the fee of the gas at the gas prices, in the smallest unit of denom
+func TxFee(gasPrices, denom string, gas uint64) (uint64, error) {
+	prices, err := types.ParseDecCoins(gasPrices)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gas prices: %w", err)
+	}
+	return prices.AmountOf(denom).MulInt64(int64(gas)).Ceil().TruncateInt().Uint64(), nil
+}
+
 // HexBytes
 type HexBytes []byte
 
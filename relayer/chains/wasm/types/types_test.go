@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxFee(t *testing.T) {
+	fee, err := TxFee("0.025uarch", "uarch", 200000)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5000), fee)
+
+	// rounded up to the smallest unit
+	fee, err = TxFee("0.025uarch", "uarch", 201)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), fee)
+
+	// priced in another denom
+	fee, err = TxFee("0.025uatom", "uarch", 200000)
+	require.NoError(t, err)
+	assert.Zero(t, fee)
+
+	_, err = TxFee("uarch", "uarch", 200000)
+	assert.Error(t, err)
+}
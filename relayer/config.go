@@ -0,0 +1,60 @@
+package relayer
+
+import "github.com/icon-project/centralized-relay/relayer/types"
+
+// Config holds the relayer settings of the global config
+type Config struct {
+	Retry     *RetryConfig     `yaml:"retry,omitempty" json:"retry,omitempty"`
+	Scheduler *SchedulerConfig `yaml:"scheduler,omitempty" json:"scheduler,omitempty"`
+	Filter    *FilterConfig    `yaml:"filter,omitempty" json:"filter,omitempty"`
+	Profit    *ProfitConfig    `yaml:"profitability,omitempty" json:"profitability,omitempty"`
+	FeeClaim  *FeeClaimConfig  `yaml:"fee-claim,omitempty" json:"fee-claim,omitempty"`
+	Balance   *BalanceConfig   `yaml:"balance,omitempty" json:"balance,omitempty"`
+	Metrics   *MetricsConfig   `yaml:"metrics,omitempty" json:"metrics,omitempty"`
+	History   *HistoryConfig   `yaml:"history,omitempty" json:"history,omitempty"`
+}
+
+// Validate checks all the relayer settings
+func (c *Config) Validate() error {
+	if err := c.Retry.Validate(); err != nil {
+		return err
+	}
+	if err := c.Scheduler.Validate(); err != nil {
+		return err
+	}
+	if err := c.Filter.Validate(); err != nil {
+		return err
+	}
+	if err := c.Profit.Validate(); err != nil {
+		return err
+	}
+	if err := c.FeeClaim.Validate(); err != nil {
+		return err
+	}
+	if err := c.Balance.Validate(); err != nil {
+		return err
+	}
+	if err := c.Metrics.Validate(); err != nil {
+		return err
+	}
+	return c.History.Validate()
+}
+
+// SetConfig applies the relayer settings, it must be called before Start
+func (r *Relayer) SetConfig(cfg *Config) {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+	r.cfg = cfg
+	r.profit = newProfitChecker(cfg.Profit)
+	r.chainsMu.RLock()
+	defer r.chainsMu.RUnlock()
+	for nId, q := range r.queues {
+		q.setStrictOrdering(cfg.Scheduler.strictOrdering(nId))
+	}
+}
+
+// retryPolicy returns the retry policy of the message
+func (r *Relayer) retryPolicy(m *types.RouteMessage) *RetryPolicy {
+	return r.cfg.Retry.Policy(m.Message)
+}
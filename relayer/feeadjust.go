@@ -0,0 +1,248 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultFeeAdjustInterval is how frequently the route fees are compared with the delivery costs
+	DefaultFeeAdjustInterval = time.Hour
+	// DefaultFeeAdjustThreshold is the relative drift of a fee from its target that triggers a change
+	DefaultFeeAdjustThreshold = 0.1
+	// DefaultFeeAdjustWindow is how far back the delivery costs are averaged
+	DefaultFeeAdjustWindow = 24 * time.Hour
+)
+
+// FeeAdjustConfig drives the fee adjuster. It computes the fee of every route from the average
+// cost paid for the messages delivered by the relayer, read from the relay history and converted
+// with the profitability prices, and sets it on the source connection when the current fee
+// drifted too far from it.
+type FeeAdjustConfig struct {
+	// Interval between two adjustments
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Margin is added on top of the cost, 0.2 sets the fee 20% over the cost
+	Margin float64 `yaml:"margin,omitempty" json:"margin,omitempty"`
+	// Threshold is the relative drift of the current fee from the computed one above which the fee is set
+	Threshold float64 `yaml:"threshold,omitempty" json:"threshold,omitempty"`
+	// Window is how far back the costs are averaged
+	Window time.Duration `yaml:"window,omitempty" json:"window,omitempty"`
+	// DryRun only records the changes in the audit log
+	DryRun bool `yaml:"dry-run,omitempty" json:"dry-run,omitempty"`
+}
+
+// Validate checks the fee adjustment values
+func (c *FeeAdjustConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("fee-adjustment interval cannot be negative: %s", c.Interval)
+	}
+	if c.Margin < 0 {
+		return fmt.Errorf("fee-adjustment margin cannot be negative: %f", c.Margin)
+	}
+	if c.Threshold < 0 {
+		return fmt.Errorf("fee-adjustment threshold cannot be negative: %f", c.Threshold)
+	}
+	if c.Window < 0 {
+		return fmt.Errorf("fee-adjustment window cannot be negative: %s", c.Window)
+	}
+	return nil
+}
+
+func (c *FeeAdjustConfig) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultFeeAdjustInterval
+	}
+	return c.Interval
+}
+
+func (c *FeeAdjustConfig) threshold() float64 {
+	if c.Threshold == 0 {
+		return DefaultFeeAdjustThreshold
+	}
+	return c.Threshold
+}
+
+func (c *FeeAdjustConfig) window() time.Duration {
+	if c.Window == 0 {
+		return DefaultFeeAdjustWindow
+	}
+	return c.Window
+}
+
+// drifted reports whether the current fee is too far from the target
+func (c *FeeAdjustConfig) drifted(current, target uint64) bool {
+	if current == 0 {
+		return target > 0
+	}
+	return math.Abs(float64(target)-float64(current))/float64(current) > c.threshold()
+}
+
+// StartFeeAdjuster adjusts the route fees every interval until ctx is done,
+// it returns right away when the fee adjustment is not configured
+func (r *Relayer) StartFeeAdjuster(ctx context.Context) {
+	if r.profit == nil || r.profit.cfg.Adjust == nil {
+		return
+	}
+	ticker := time.NewTicker(r.profit.cfg.Adjust.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.adjustFees(ctx)
+		}
+	}
+}
+
+// adjustFees adjusts the fee of every route between two running chains with recent costs
+func (r *Relayer) adjustFees(ctx context.Context) {
+	chains := r.chainRuntimes()
+	for src, srcChain := range chains {
+		for dst := range chains {
+			if src == dst {
+				continue
+			}
+			if _, err := r.adjustFee(ctx, srcChain, dst); err != nil {
+				srcChain.log.Warn("failed to adjust fee", zap.String("dst", dst), zap.Error(err))
+			}
+		}
+	}
+}
+
+// adjustFee sets the fees of the route from src to dst when they drifted from the costs, the
+// message fee follows the delivery cost on dst and the response fee the delivery cost back on src.
+// It returns the recorded change, nil when the fees are left as they are.
+func (r *Relayer) adjustFee(ctx context.Context, src *ChainRuntime, dst string) (*types.FeeChange, error) {
+	cfg := r.profit.cfg.Adjust
+	nId := src.Provider.NID()
+	since := time.Now().Add(-cfg.window())
+	cost, ok, err := r.averageDeliveryCost(nId, dst, since)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	srcPrice, err := r.profit.price(ctx, nId)
+	if err != nil {
+		return nil, err
+	}
+	dstPrice, err := r.profit.price(ctx, dst)
+	if err != nil {
+		return nil, err
+	}
+	if srcPrice == 0 {
+		return nil, fmt.Errorf("%s: %w", nId, ErrNoPrice)
+	}
+	if dstPrice == 0 {
+		return nil, fmt.Errorf("%s: %w", dst, ErrNoPrice)
+	}
+
+	msgFee, err := src.Provider.GetFee(ctx, dst, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee: %w", err)
+	}
+	totalFee, err := src.Provider.GetFee(ctx, dst, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response fee: %w", err)
+	}
+	resFee := totalFee - min(msgFee, totalFee)
+
+	costValue := r.profit.value(cost, r.profit.cfg.decimals(dst), dstPrice)
+	change := &types.FeeChange{
+		Src:       nId,
+		Dst:       dst,
+		OldMsgFee: msgFee,
+		NewMsgFee: uint64(math.Ceil(costValue * (1 + cfg.Margin) / srcPrice * math.Pow10(int(r.profit.cfg.decimals(nId))))),
+		OldResFee: resFee,
+		NewResFee: resFee,
+		Cost:      cost,
+		DryRun:    cfg.DryRun,
+		At:        time.Now(),
+	}
+	// the response is delivered on the source chain, its cost is already in the source denomination
+	resCost, ok, err := r.averageDeliveryCost(dst, nId, since)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		change.NewResFee = uint64(math.Ceil(float64(resCost) * (1 + cfg.Margin)))
+	}
+	// a fee is never lowered to nothing, the deliveries would be free
+	if change.NewMsgFee == 0 {
+		return nil, fmt.Errorf("computed a zero fee from the cost %d", cost)
+	}
+	if !cfg.drifted(change.OldMsgFee, change.NewMsgFee) && !cfg.drifted(change.OldResFee, change.NewResFee) {
+		return nil, nil
+	}
+
+	if !cfg.DryRun {
+		if err := src.Provider.SetFee(ctx, dst, new(big.Int).SetUint64(change.NewMsgFee), new(big.Int).SetUint64(change.NewResFee)); err != nil {
+			change.Error = err.Error()
+		}
+	}
+	if err := r.feeAuditStore.StoreFeeChange(change); err != nil {
+		src.log.Error("failed to record fee change", zap.Error(err))
+	}
+
+	fields := []zap.Field{
+		zap.String("dst", dst),
+		zap.Uint64("old_msg_fee", change.OldMsgFee),
+		zap.Uint64("new_msg_fee", change.NewMsgFee),
+		zap.Uint64("old_res_fee", change.OldResFee),
+		zap.Uint64("new_res_fee", change.NewResFee),
+		zap.Uint64("cost", change.Cost),
+		zap.Bool("dry_run", change.DryRun),
+	}
+	if change.Error != "" {
+		src.log.Error("failed to set fee", append(fields, zap.String("error", change.Error))...)
+	} else {
+		src.log.Info("fee adjusted", fields...)
+	}
+	return change, nil
+}
+
+// averageDeliveryCost returns the average fee paid for the messages from src delivered on dst
+// since the given time, in the smallest denomination of dst. The deliveries observed on dst
+// or without a recorded fee are left out.
+func (r *Relayer) averageDeliveryCost(src, dst string, since time.Time) (uint64, bool, error) {
+	filter := &store.HistoryFilter{Src: src, Dst: dst, Since: since}
+	p := store.NewPagination().WithLimit(store.DefaultPageSize)
+	var total, count uint64
+	for {
+		records, next, err := r.historyStore.ListRecords(filter, p)
+		if err != nil {
+			return 0, false, err
+		}
+		for _, record := range records {
+			if record.DstTxHash == "" || record.Fee == 0 {
+				continue
+			}
+			total += record.Fee
+			count++
+		}
+		if next == nil {
+			break
+		}
+		p = store.NewPagination().WithLimit(store.DefaultPageSize).WithCursor(next)
+	}
+	if count == 0 {
+		return 0, false, nil
+	}
+	return total / count, true, nil
+}
+
+// GetFeeAuditStore returns the fee audit store
+func (r *Relayer) GetFeeAuditStore() *store.FeeAuditStore {
+	return r.feeAuditStore
+}
@@ -0,0 +1,163 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+)
+
+// DefaultFeeClaimInterval is how frequently the fees accrued on the connections are checked
+var DefaultFeeClaimInterval = time.Hour
+
+// FeeClaimConfig claims the fees accrued on the connection contracts of the listed chains,
+// the claimed fees go to the connection admin, the relayer wallet
+type FeeClaimConfig struct {
+	// Interval between two checks of the accrued fees
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Chains to claim the fees of, by nid
+	Chains map[string]*ChainFeeClaim `yaml:"chains,omitempty" json:"chains,omitempty"`
+}
+
+// ChainFeeClaim decides when the fees of a chain are claimed, on a schedule, above a threshold or both
+type ChainFeeClaim struct {
+	// Every claims the fees when the last claim is older
+	Every time.Duration `yaml:"every,omitempty" json:"every,omitempty"`
+	// Threshold claims the fees as soon as they reach it, in the smallest denomination of the chain
+	Threshold uint64 `yaml:"threshold,omitempty" json:"threshold,omitempty"`
+	// Treasury receives the claimed fees from the relayer wallet, they are kept when empty
+	Treasury string `yaml:"treasury,omitempty" json:"treasury,omitempty"`
+}
+
+// Validate checks the fee claim values
+func (c *FeeClaimConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("fee-claim interval cannot be negative: %s", c.Interval)
+	}
+	for nid, chain := range c.Chains {
+		if chain == nil || (chain.Every <= 0 && chain.Threshold == 0) {
+			return fmt.Errorf("fee-claim of %s needs every or threshold", nid)
+		}
+	}
+	return nil
+}
+
+func (c *FeeClaimConfig) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultFeeClaimInterval
+	}
+	return c.Interval
+}
+
+// StartFeeClaimer claims the accrued fees every interval until ctx is done,
+// it returns right away when the fee claim is not configured
+func (r *Relayer) StartFeeClaimer(ctx context.Context) {
+	if r.cfg.FeeClaim == nil {
+		return
+	}
+	ticker := time.NewTicker(r.cfg.FeeClaim.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for nId := range r.cfg.FeeClaim.Chains {
+				chain, ok := r.chain(nId)
+				if !ok {
+					continue
+				}
+				if _, err := r.claimFee(ctx, chain); err != nil {
+					chain.log.Warn("failed to check accrued fees", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+// claimFee claims the fees of the chain when they are due and sweeps them to the treasury,
+// it returns the recorded claim, nil when nothing is due. The amount swept is what the claim
+// credited, the drop of the connection balance across the claim.
+func (r *Relayer) claimFee(ctx context.Context, chain *ChainRuntime) (*types.FeeClaim, error) {
+	nId := chain.Provider.NID()
+	cfg := r.cfg.FeeClaim.Chains[nId]
+	if cfg == nil {
+		return nil, nil
+	}
+	claimable, err := chain.Provider.QueryClaimableFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claimable.Sign() <= 0 {
+		return nil, nil
+	}
+
+	due := cfg.Threshold > 0 && claimable.Cmp(new(big.Int).SetUint64(cfg.Threshold)) >= 0
+	if !due && cfg.Every > 0 {
+		last, err := r.feeClaimStore.LastFeeClaim(nId)
+		if err != nil {
+			return nil, err
+		}
+		due = last == nil || time.Since(last.At) >= cfg.Every
+	}
+	if !due {
+		return nil, nil
+	}
+
+	claim := &types.FeeClaim{Nid: nId, Amount: claimable, At: time.Now()}
+	if err := chain.Provider.ClaimFee(ctx); err != nil {
+		claim.Error = err.Error()
+	} else if credited, err := r.claimedFee(ctx, chain, claimable); err != nil {
+		// the fees are claimed, they are not swept without knowing how much
+		claim.SweepError = err.Error()
+	} else {
+		claim.Amount = credited
+		if cfg.Treasury != "" && credited.Sign() > 0 {
+			claim.Treasury = cfg.Treasury
+			if err := chain.Provider.Transfer(ctx, cfg.Treasury, credited); err != nil {
+				claim.SweepError = err.Error()
+			}
+		}
+	}
+	if err := r.feeClaimStore.StoreFeeClaim(claim); err != nil {
+		chain.log.Error("failed to record fee claim", zap.Error(err))
+	}
+
+	fields := []zap.Field{zap.Stringer("amount", claim.Amount), zap.String("treasury", claim.Treasury)}
+	switch {
+	case claim.Error != "":
+		chain.log.Error("failed to claim fees", append(fields, zap.String("error", claim.Error))...)
+	case claim.SweepError != "":
+		chain.log.Error("fees claimed, failed to sweep them to the treasury", append(fields, zap.String("error", claim.SweepError))...)
+	default:
+		chain.log.Info("fees claimed", fields...)
+	}
+	return claim, nil
+}
+
+// claimedFee returns the fee credited by a claim of the connection holding the claimable fee before it,
+// the fees accrued since the claim are left out and the ones accrued just before it are counted later
+func (r *Relayer) claimedFee(ctx context.Context, chain *ChainRuntime, claimable *big.Int) (*big.Int, error) {
+	left, err := chain.Provider.QueryClaimableFee(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the fees left after the claim: %w", err)
+	}
+	credited := new(big.Int).Sub(claimable, left)
+	if credited.Sign() < 0 {
+		credited.SetInt64(0)
+	}
+	return credited, nil
+}
+
+// GetFeeClaimStore returns the fee claim store
+func (r *Relayer) GetFeeClaimStore() *store.FeeClaimStore {
+	return r.feeClaimStore
+}
@@ -0,0 +1,119 @@
+package relayer
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/icon-project/centralized-relay/relayer/events"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"golang.org/x/crypto/sha3"
+)
+
+// FilterConfig holds the rules deciding which messages the relayer serves,
+// a message refused by the rules is not relayed and is recorded with the reason
+type FilterConfig struct {
+	// Allow restricts the messages to the listed values, only the non-empty lists are checked
+	Allow *FilterRule `yaml:"allow,omitempty" json:"allow,omitempty"`
+	// Deny refuses the messages matching any of the listed values
+	Deny *FilterRule `yaml:"deny,omitempty" json:"deny,omitempty"`
+	// MaxDataSize refuses the messages with a larger payload in bytes, no limit when zero
+	MaxDataSize int `yaml:"max-data-size,omitempty" json:"max-data-size,omitempty"`
+	// MinFee is the fee the source connection must charge for the route of an emitted message,
+	// per source nid, in the smallest denomination of the source chain
+	MinFee map[string]uint64 `yaml:"min-fee,omitempty" json:"min-fee,omitempty"`
+}
+
+// FilterRule lists the values matched against the messages
+type FilterRule struct {
+	Src        []string `yaml:"src,omitempty" json:"src,omitempty"`
+	Dst        []string `yaml:"dst,omitempty" json:"dst,omitempty"`
+	EventTypes []string `yaml:"event-types,omitempty" json:"event-types,omitempty"`
+	// From are the xcall source network addresses, they are checked on the call messages only
+	From []string `yaml:"from,omitempty" json:"from,omitempty"`
+}
+
+// Validate checks the filter values
+func (c *FilterConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxDataSize < 0 {
+		return fmt.Errorf("filter max-data-size cannot be negative: %d", c.MaxDataSize)
+	}
+	return nil
+}
+
+// check returns the reason the message is refused by the static rules, empty when it is not
+func (c *FilterConfig) check(m *types.Message) string {
+	if c == nil {
+		return ""
+	}
+	if deny := c.Deny; deny != nil {
+		switch {
+		case slices.Contains(deny.Src, m.Src):
+			return fmt.Sprintf("src %s is denied", m.Src)
+		case slices.Contains(deny.Dst, m.Dst):
+			return fmt.Sprintf("dst %s is denied", m.Dst)
+		case slices.Contains(deny.EventTypes, m.EventType):
+			return fmt.Sprintf("event type %s is denied", m.EventType)
+		case m.From != "" && matchFrom(deny.From, m.From):
+			return fmt.Sprintf("from %s is denied", m.From)
+		}
+	}
+	if allow := c.Allow; allow != nil {
+		switch {
+		case len(allow.Src) > 0 && !slices.Contains(allow.Src, m.Src):
+			return fmt.Sprintf("src %s is not allowed", m.Src)
+		case len(allow.Dst) > 0 && !slices.Contains(allow.Dst, m.Dst):
+			return fmt.Sprintf("dst %s is not allowed", m.Dst)
+		case len(allow.EventTypes) > 0 && !slices.Contains(allow.EventTypes, m.EventType):
+			return fmt.Sprintf("event type %s is not allowed", m.EventType)
+		case len(allow.From) > 0 && m.EventType == events.CallMessage && !matchFrom(allow.From, m.From):
+			return fmt.Sprintf("from %s is not allowed", m.From)
+		}
+	}
+	if c.MaxDataSize > 0 && len(m.Data) > c.MaxDataSize {
+		return fmt.Sprintf("payload size %d exceeds %d", len(m.Data), c.MaxDataSize)
+	}
+	return ""
+}
+
+// checkFee returns the reason the message is refused when the source connection charges
+// less than the minimum fee for its route, empty when it is not
+func (c *FilterConfig) checkFee(ctx context.Context, src *ChainRuntime, m *types.Message) (string, error) {
+	if c == nil || m.EventType != events.EmitMessage {
+		return "", nil
+	}
+	minFee, ok := c.MinFee[m.Src]
+	if !ok {
+		return "", nil
+	}
+	fee, err := src.Provider.GetFee(ctx, m.Dst, false)
+	if err != nil {
+		return "", err
+	}
+	if fee < minFee {
+		return fmt.Sprintf("fee %d is lower than %d", fee, minFee), nil
+	}
+	return "", nil
+}
+
+// matchFrom reports whether from is one of the addresses, the chains that log the
+// address as an indexed topic only expose its keccak256 hash so the hash matches too
+func matchFrom(addresses []string, from string) bool {
+	for _, addr := range addresses {
+		if strings.EqualFold(addr, from) || strings.EqualFold(keccak256Hex(addr), from) {
+			return true
+		}
+	}
+	return false
+}
+
+func keccak256Hex(s string) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(s))
+	return "0x" + hex.EncodeToString(h.Sum(nil))
+}
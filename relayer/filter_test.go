@@ -0,0 +1,74 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFilterConfig(t *testing.T) {
+	data := `
+filter:
+  allow:
+    dst: [0x2.icon, archway-1]
+    from: [0x1.icon/hx123, 0xa869.fuji/0xabc]
+  deny:
+    src: [0x1.bsc]
+    event-types: [rollbackMessage]
+  max-data-size: 8
+  min-fee:
+    mock-1: 10
+`
+	cfg := new(Config)
+	assert.NoError(t, yaml.Unmarshal([]byte(data), cfg))
+	assert.NoError(t, cfg.Validate())
+	filter := cfg.Filter
+
+	tests := []struct {
+		name    string
+		message *types.Message
+		reason  string
+	}{
+		{"allowed", &types.Message{Src: "0xa869.fuji", Dst: "0x2.icon", EventType: "emitMessage"}, ""},
+		{"denied src", &types.Message{Src: "0x1.bsc", Dst: "0x2.icon", EventType: "emitMessage"}, "src 0x1.bsc is denied"},
+		{"denied event", &types.Message{Src: "0x2.icon", Dst: "0x2.icon", EventType: "rollbackMessage"}, "event type rollbackMessage is denied"},
+		{"dst not allowed", &types.Message{Src: "0xa869.fuji", Dst: "0x38.bsc", EventType: "emitMessage"}, "dst 0x38.bsc is not allowed"},
+		{"allowed dapp", &types.Message{Src: "0x1.icon", Dst: "0x2.icon", EventType: "callMessage", From: "0x1.icon/hx123"}, ""},
+		{"dapp not allowed", &types.Message{Src: "0x1.icon", Dst: "0x2.icon", EventType: "callMessage", From: "0x1.icon/hx456"}, "from 0x1.icon/hx456 is not allowed"},
+		{"hashed dapp", &types.Message{Src: "0x2.icon", Dst: "0x2.icon", EventType: "callMessage", From: keccak256Hex("0xa869.fuji/0xabc")}, ""},
+		{"payload too large", &types.Message{Src: "0xa869.fuji", Dst: "archway-1", EventType: "emitMessage", Data: make([]byte, 9)}, "payload size 9 exceeds 8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.reason, filter.check(tt.message))
+		})
+	}
+
+	t.Run("no filter", func(t *testing.T) {
+		var filter *FilterConfig
+		assert.Empty(t, filter.check(&types.Message{Src: "0x1.bsc", EventType: "rollbackMessage"}))
+	})
+
+	t.Run("min fee", func(t *testing.T) {
+		provider, err := GetMockChainProvider(zap.NewNop(), time.Second, "mock-1", "mock-2", 10, 20)
+		assert.NoError(t, err)
+		src, err := NewChainRuntime(zap.NewNop(), NewChain(zap.NewNop(), provider, false))
+		assert.NoError(t, err)
+
+		// the mock connection charges no fee
+		reason, err := filter.checkFee(context.Background(), src, &types.Message{Src: "mock-1", Dst: "mock-2", Sn: big.NewInt(1), EventType: "emitMessage"})
+		assert.NoError(t, err)
+		assert.Equal(t, "fee 0 is lower than 10", reason)
+
+		// call messages are executed on the chain they are emitted on, there is no route fee
+		reason, err = filter.checkFee(context.Background(), src, &types.Message{Src: "mock-1", Dst: "mock-1", Sn: big.NewInt(1), EventType: "callMessage"})
+		assert.NoError(t, err)
+		assert.Empty(t, reason)
+	})
+}
@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"go.uber.org/zap"
+)
+
+// DefaultHistoryPruneInterval is how frequently the retention policy of the relay history is applied
+var DefaultHistoryPruneInterval = time.Hour
+
+// HistoryConfig is the retention policy of the relay history, the records of the delivered
+// messages are kept forever when it is not set
+type HistoryConfig struct {
+	// Retention is how long a record is kept after the delivery
+	Retention time.Duration `yaml:"retention,omitempty" json:"retention,omitempty"`
+	// MaxRecords is the number of the most recent records kept
+	MaxRecords uint `yaml:"max-records,omitempty" json:"max-records,omitempty"`
+	// Interval between two applications of the policy
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+}
+
+// Validate checks the history values
+func (c *HistoryConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Retention < 0 || c.Interval < 0 {
+		return fmt.Errorf("history retention and interval cannot be negative")
+	}
+	if c.Retention == 0 && c.MaxRecords == 0 {
+		return fmt.Errorf("history needs retention or max-records")
+	}
+	return nil
+}
+
+func (c *HistoryConfig) interval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultHistoryPruneInterval
+	}
+	return c.Interval
+}
+
+// StartHistoryPruner applies the retention policy of the relay history every interval until
+// ctx is done, it returns right away when there is no policy
+func (r *Relayer) StartHistoryPruner(ctx context.Context) {
+	if r.cfg.History == nil {
+		return
+	}
+	ticker := time.NewTicker(r.cfg.History.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.pruneHistory(); err != nil {
+				r.log.Warn("failed to prune relay history", zap.Error(err))
+			}
+		}
+	}
+}
+
+// pruneHistory removes the records of the relay history out of the retention policy,
+// it returns the number of records removed
+func (r *Relayer) pruneHistory() (int, error) {
+	cfg := r.cfg.History
+	var before time.Time
+	if cfg.Retention > 0 {
+		before = time.Now().Add(-cfg.Retention)
+	}
+	count, err := r.historyStore.Prune(before, cfg.MaxRecords)
+	if count > 0 {
+		r.log.Info("relay history pruned", zap.Int("records", count))
+	}
+	return count, err
+}
+
+// GetHistoryStore returns the relay history store
+func (r *Relayer) GetHistoryStore() *store.HistoryStore {
+	return r.historyStore
+}
@@ -3,12 +3,14 @@ package lvldb
 import (
 	"os"
 
+	"github.com/icon-project/centralized-relay/relayer/store"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
+var _ store.Store = (*LVLDB)(nil)
+
 type LVLDB struct {
 	db *leveldb.DB
 }
@@ -19,7 +21,11 @@ func NewLvlDB(path string) (*LVLDB, error) {
 }
 
 func (db *LVLDB) GetByKey(key []byte) ([]byte, error) {
-	return db.db.Get(key, nil)
+	value, err := db.db.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, store.ErrNotFound
+	}
+	return value, err
 }
 
 func (db *LVLDB) SetByKey(key []byte, value []byte) error {
@@ -30,10 +36,19 @@ func (db *LVLDB) DeleteByKey(key []byte) error {
 	return db.db.Delete(key, nil)
 }
 
-func (db *LVLDB) NewIterator(prefix []byte) iterator.Iterator {
+func (db *LVLDB) NewIterator(prefix []byte) store.Iterator {
 	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
 }
 
+func (db *LVLDB) NewRangeIterator(start, limit []byte) store.Iterator {
+	return db.db.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+}
+
+// NewBatch returns a batch written with a leveldb.Batch
+func (db *LVLDB) NewBatch() store.Batch {
+	return &batch{db: db.db}
+}
+
 func (db *LVLDB) RemoveDbFile(filepath string) error {
 	return errors.Wrapf(os.Remove(filepath), "unable to remove db file")
 }
@@ -57,3 +72,30 @@ func (db *LVLDB) ClearStore() error {
 func (db *LVLDB) Close() error {
 	return db.db.Close()
 }
+
+type batch struct {
+	db    *leveldb.DB
+	batch leveldb.Batch
+}
+
+func (b *batch) SetByKey(key []byte, value []byte) error {
+	b.batch.Put(key, value)
+	return nil
+}
+
+func (b *batch) DeleteByKey(key []byte) error {
+	b.batch.Delete(key)
+	return nil
+}
+
+func (b *batch) Len() int {
+	return b.batch.Len()
+}
+
+func (b *batch) Write() error {
+	return b.db.Write(&b.batch, nil)
+}
+
+func (b *batch) Reset() {
+	b.batch.Reset()
+}
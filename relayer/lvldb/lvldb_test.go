@@ -0,0 +1,17 @@
+package lvldb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/store/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLvlDB(t *testing.T) {
+	db, err := NewLvlDB(filepath.Join(t.TempDir(), "data"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	storetest.Run(t, db)
+}
@@ -0,0 +1,67 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const metricsNamespace = "centralized_relay"
+
+var (
+	walletBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "wallet_balance",
+		Help:      "Balance of the relayer wallet in the smallest denomination of the chain.",
+	}, []string{"nid", "wallet", "denom"})
+
+	routingPaused = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "routing_paused",
+		Help:      "1 while the routing to the chain is paused because its relayer wallet cannot cover the delivery cost.",
+	}, []string{"nid"})
+)
+
+// MetricsConfig exposes the relayer metrics in the prometheus format
+type MetricsConfig struct {
+	// Listen is the address the metrics are served on, at /metrics
+	Listen string `yaml:"listen,omitempty" json:"listen,omitempty"`
+}
+
+// Validate checks the metrics values
+func (c *MetricsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Listen == "" {
+		return fmt.Errorf("metrics listen address cannot be empty")
+	}
+	return nil
+}
+
+// StartMetricsServer serves the metrics until ctx is done,
+// it returns right away when the metrics are not configured
+func (r *Relayer) StartMetricsServer(ctx context.Context) {
+	if r.cfg.Metrics == nil {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: r.cfg.Metrics.Listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	r.log.Info("serving metrics", zap.String("listen", r.cfg.Metrics.Listen))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		r.log.Error("metrics server failed", zap.Error(err))
+	}
+}
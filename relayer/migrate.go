@@ -0,0 +1,179 @@
+package relayer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// migrateBatchSize is the number of records moved to their new key in one batch
+const migrateBatchSize = 256
+
+// legacyRecord moves a record of the legacy schema to its key in the current schema,
+// stores stage their writes in the migration batch
+type legacyRecord func(db store.Store, key, value []byte) error
+
+// legacyMigrations are the stores of the legacy schema and how their records are moved
+var legacyMigrations = []struct {
+	prefix  string
+	migrate legacyRecord
+}{
+	{prefixMessageStore, func(db store.Store, _, value []byte) error {
+		m := new(types.RouteMessage)
+		if err := jsoniter.Unmarshal(value, m); err != nil {
+			return err
+		}
+		return store.NewMessageStore(db, prefixMessageStore).StoreMessage(m)
+	}},
+	{prefixBlockStore, func(db store.Store, key, value []byte) error {
+		nId := strings.TrimPrefix(string(key), prefixBlockStore+"-")
+		return db.SetByKey(store.NewBlockStore(db, prefixBlockStore).GetKey(nId), value)
+	}},
+	{prefixFinalityStore, func(db store.Store, _, value []byte) error {
+		tx := new(types.TransactionObject)
+		if err := jsoniter.Unmarshal(value, tx); err != nil {
+			return err
+		}
+		return store.NewFinalityStore(db, prefixFinalityStore).StoreTxObject(tx)
+	}},
+	{prefixPendingTxStore, func(db store.Store, _, value []byte) error {
+		tx := new(types.PendingTransaction)
+		if err := jsoniter.Unmarshal(value, tx); err != nil {
+			return err
+		}
+		return store.NewPendingTxStore(db, prefixPendingTxStore).StorePendingTx(tx)
+	}},
+	{prefixDeadLetterStore, func(db store.Store, _, value []byte) error {
+		d := new(types.DeadLetter)
+		if err := jsoniter.Unmarshal(value, d); err != nil {
+			return err
+		}
+		return store.NewDeadLetterStore(db, prefixDeadLetterStore).StoreDeadLetter(d)
+	}},
+	{prefixDeliveredStore, func(db store.Store, _, value []byte) error {
+		d := new(types.DeliveredMessage)
+		if err := jsoniter.Unmarshal(value, d); err != nil {
+			return err
+		}
+		return store.NewDeliveredStore(db, prefixDeliveredStore).StoreDelivered(d)
+	}},
+	{prefixBlockRecord, func(db store.Store, key, value []byte) error {
+		// the legacy key ends with the height, the nId may hold dashes
+		rest := strings.TrimPrefix(string(key), prefixBlockRecord+"-")
+		i := strings.LastIndex(rest, "-")
+		if i < 0 {
+			return fmt.Errorf("malformed block record key %q", key)
+		}
+		record := new(types.BlockRecord)
+		if err := jsoniter.Unmarshal(value, record); err != nil {
+			return err
+		}
+		return store.NewBlockRecordStore(db, prefixBlockRecord).StoreBlockRecord(rest[:i], record)
+	}},
+	{prefixRejectedStore, func(db store.Store, _, value []byte) error {
+		m := new(types.RejectedMessage)
+		if err := jsoniter.Unmarshal(value, m); err != nil {
+			return err
+		}
+		return store.NewRejectedStore(db, prefixRejectedStore).StoreRejected(m)
+	}},
+	{prefixFeeAuditStore, func(db store.Store, _, value []byte) error {
+		change := new(types.FeeChange)
+		if err := jsoniter.Unmarshal(value, change); err != nil {
+			return err
+		}
+		return store.NewFeeAuditStore(db, prefixFeeAuditStore).StoreFeeChange(change)
+	}},
+	{prefixFeeClaimStore, func(db store.Store, _, value []byte) error {
+		claim := new(types.FeeClaim)
+		if err := jsoniter.Unmarshal(value, claim); err != nil {
+			return err
+		}
+		return store.NewFeeClaimStore(db, prefixFeeClaimStore).StoreFeeClaim(claim)
+	}},
+}
+
+// MigrateStore upgrades a store written by an older relayer to the current schema in place
+// and returns the number of records migrated. The legacy keys are moved first, every batch
+// moves its records and deletes their legacy keys at once, then the message counters and
+// indexes are built. An interrupted migration resumes where it stopped when run again.
+func MigrateStore(db store.Store) (int, error) {
+	version, err := store.GetSchemaVersion(db)
+	if err != nil {
+		return 0, err
+	}
+	if version == store.SchemaVersion {
+		return 0, nil
+	}
+	if version < store.LegacySchemaVersion || version > store.SchemaVersion {
+		return 0, fmt.Errorf("store schema version %d is not supported by this relayer", version)
+	}
+
+	var total int
+	if version == store.LegacySchemaVersion {
+		for _, m := range legacyMigrations {
+			count, err := migrateLegacyPrefix(db, m.prefix, m.migrate)
+			total += count
+			if err != nil {
+				return total, fmt.Errorf("failed to migrate the %s store: %w", m.prefix, err)
+			}
+		}
+		if err := store.SetSchemaVersion(db, store.KeySchemaVersion); err != nil {
+			return total, err
+		}
+	}
+
+	count, err := store.NewMessageStore(db, prefixMessageStore).RebuildIndex()
+	total += count
+	if err != nil {
+		return total, fmt.Errorf("failed to index the messages: %w", err)
+	}
+	return total, store.SetSchemaVersion(db, store.SchemaVersion)
+}
+
+func migrateLegacyPrefix(db store.Store, prefix string, migrate legacyRecord) (int, error) {
+	batch := db.NewBatch()
+	staged := store.WithBatch(db, batch)
+
+	var moved, pending int
+	flush := func() error {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		moved += pending
+		pending = 0
+		return nil
+	}
+
+	// the legacy keys are text while the current ones start with the version byte,
+	// so the iterator never sees the moved records
+	iter := db.NewIterator(store.GetKey([]string{prefix, ""}))
+	defer iter.Release()
+	for iter.Next() {
+		key := bytes.Clone(iter.Key())
+		if err := migrate(staged, key, iter.Value()); err != nil {
+			return moved, fmt.Errorf("record %q: %w", key, err)
+		}
+		if err := batch.DeleteByKey(key); err != nil {
+			return moved, err
+		}
+		if pending++; pending == migrateBatchSize {
+			if err := flush(); err != nil {
+				return moved, err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return moved, err
+	}
+	if err := flush(); err != nil {
+		return moved, err
+	}
+	return moved, nil
+}
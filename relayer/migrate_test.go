@@ -0,0 +1,73 @@
+package relayer
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/icon-project/centralized-relay/relayer/lvldb"
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+func TestMigrateStore(t *testing.T) {
+	db, err := lvldb.NewLvlDB(t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	setLegacy := func(key []string, value any) {
+		data, err := jsoniter.Marshal(value)
+		require.NoError(t, err)
+		require.NoError(t, db.SetByKey(store.GetKey(key), data))
+	}
+
+	// more messages than a migration batch
+	for i := int64(1); i <= migrateBatchSize+1; i++ {
+		m := types.NewRouteMessage(&types.Message{Src: "icon", Dst: "archway", Sn: big.NewInt(i), EventType: "emitMessage"})
+		setLegacy([]string{prefixMessageStore, m.Src, m.Sn.String()}, m)
+	}
+	testnet := types.NewRouteMessage(&types.Message{Src: "icon-testnet", Dst: "archway", Sn: big.NewInt(1), EventType: "emitMessage"})
+	setLegacy([]string{prefixMessageStore, testnet.Src, testnet.Sn.String()}, testnet)
+	setLegacy([]string{prefixBlockStore, "icon-testnet"}, uint64(120))
+	setLegacy([]string{prefixBlockRecord, "icon-testnet", strconv.Itoa(100)}, &types.BlockRecord{Height: 100, Hash: "0xabc"})
+	dead := types.NewDeadLetter(testnet)
+	setLegacy([]string{prefixDeadLetterStore, dead.Src, dead.Sn.String()}, dead)
+
+	require.ErrorContains(t, store.CheckSchemaVersion(db), "db migrate")
+
+	count, err := MigrateStore(db)
+	require.NoError(t, err)
+	// the moved records and the indexed messages
+	assert.Equal(t, migrateBatchSize+5+migrateBatchSize+2, count)
+	require.NoError(t, store.CheckSchemaVersion(db))
+
+	messages := store.NewMessageStore(db, prefixMessageStore)
+	total, err := messages.TotalCountByChain("icon")
+	require.NoError(t, err)
+	assert.Equal(t, uint(migrateBatchSize+1), total)
+	m, err := messages.GetMessage(testnet.MessageKey())
+	require.NoError(t, err)
+	assert.Equal(t, "icon-testnet", m.Src)
+
+	height, err := store.NewBlockStore(db, prefixBlockStore).GetLastStoredBlock("icon-testnet")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(120), height)
+
+	record, err := store.NewBlockRecordStore(db, prefixBlockRecord).GetBlockRecord("icon-testnet", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "0xabc", record.Hash)
+
+	_, err = store.NewDeadLetterStore(db, prefixDeadLetterStore).GetDeadLetter(testnet.MessageKey())
+	require.NoError(t, err)
+
+	// no legacy key is left and a second run has nothing to do
+	_, err = db.GetByKey(store.GetKey([]string{prefixBlockStore, "icon-testnet"}))
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	count, err = MigrateStore(db)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
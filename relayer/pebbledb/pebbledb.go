@@ -0,0 +1,152 @@
+package pebbledb
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/pkg/errors"
+)
+
+var _ store.Store = (*PebbleDB)(nil)
+
+type PebbleDB struct {
+	db *pebble.DB
+}
+
+func NewPebbleDB(path string) (*PebbleDB, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, errors.Wrap(err, "pebble.Open fail")
+	}
+	return &PebbleDB{db: db}, nil
+}
+
+func (db *PebbleDB) GetByKey(key []byte) ([]byte, error) {
+	value, closer, err := db.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	// the value is only valid until the closer is closed
+	return bytes.Clone(value), nil
+}
+
+func (db *PebbleDB) SetByKey(key []byte, value []byte) error {
+	return db.db.Set(key, value, pebble.Sync)
+}
+
+func (db *PebbleDB) DeleteByKey(key []byte) error {
+	return db.db.Delete(key, pebble.Sync)
+}
+
+func (db *PebbleDB) NewIterator(prefix []byte) store.Iterator {
+	return db.NewRangeIterator(prefix, store.PrefixEnd(prefix))
+}
+
+func (db *PebbleDB) NewRangeIterator(start, limit []byte) store.Iterator {
+	iter, err := db.db.NewIter(&pebble.IterOptions{
+		LowerBound: start,
+		UpperBound: limit,
+	})
+	return &iterator{iter: iter, err: err}
+}
+
+// NewBatch returns a batch written with a pebble.Batch
+func (db *PebbleDB) NewBatch() store.Batch {
+	return &batch{batch: db.db.NewBatch()}
+}
+
+func (db *PebbleDB) ClearStore() error {
+	iter, err := db.db.NewIter(nil)
+	if err != nil {
+		return err
+	}
+	batch := db.db.NewBatch()
+	defer batch.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := batch.Delete(iter.Key(), nil); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (db *PebbleDB) Close() error {
+	return db.db.Close()
+}
+
+// iterator moves to the first key on the first call of Next, as the leveldb iterators do
+type iterator struct {
+	iter    *pebble.Iterator
+	started bool
+	err     error
+}
+
+func (i *iterator) Next() bool {
+	if i.err != nil || i.iter == nil {
+		return false
+	}
+	if !i.started {
+		i.started = true
+		return i.iter.First()
+	}
+	return i.iter.Next()
+}
+
+func (i *iterator) Key() []byte {
+	return i.iter.Key()
+}
+
+func (i *iterator) Value() []byte {
+	return i.iter.Value()
+}
+
+func (i *iterator) Release() {
+	if i.iter == nil {
+		return
+	}
+	if err := i.iter.Close(); err != nil && i.err == nil {
+		i.err = err
+	}
+	i.iter = nil
+}
+
+func (i *iterator) Error() error {
+	if i.err != nil || i.iter == nil {
+		return i.err
+	}
+	return i.iter.Error()
+}
+
+type batch struct {
+	batch *pebble.Batch
+}
+
+func (b *batch) SetByKey(key []byte, value []byte) error {
+	return b.batch.Set(key, value, nil)
+}
+
+func (b *batch) DeleteByKey(key []byte) error {
+	return b.batch.Delete(key, nil)
+}
+
+func (b *batch) Len() int {
+	return int(b.batch.Count())
+}
+
+func (b *batch) Write() error {
+	return b.batch.Commit(pebble.Sync)
+}
+
+func (b *batch) Reset() {
+	b.batch.Reset()
+}
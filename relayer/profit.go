@@ -0,0 +1,357 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/events"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultTokenDecimals is the decimals of the native tokens of the chains without configured decimals
+	DefaultTokenDecimals = 18
+	// DefaultProfitDelay is how long an unprofitable message is held before it is checked again
+	DefaultProfitDelay = 5 * time.Minute
+)
+
+// ProfitAction is what is done with a message whose relay cost exceeds its fee
+type ProfitAction string
+
+const (
+	// ProfitActionAlert relays the message and logs a warning
+	ProfitActionAlert ProfitAction = "alert"
+	// ProfitActionDelay holds the message and checks it again after the delay, the prices or the gas may have moved
+	ProfitActionDelay ProfitAction = "delay"
+	// ProfitActionSkip refuses the message, it is recorded as rejected
+	ProfitActionSkip ProfitAction = "skip"
+)
+
+// ProfitConfig compares the fee charged by the source connection for an emitted message with the
+// cost of delivering it on the destination. Both are converted to a common unit, such as USD, with
+// the price of the native token of each chain.
+type ProfitConfig struct {
+	// Action is taken when the cost exceeds the fee by more than the margin, alert by default
+	Action ProfitAction `yaml:"action,omitempty" json:"action,omitempty"`
+	// Margin is the fraction of the fee the cost may exceed it by, 0.1 tolerates a cost 10% over the fee
+	Margin float64 `yaml:"margin,omitempty" json:"margin,omitempty"`
+	// Delay is how long a message is held with the delay action
+	Delay time.Duration `yaml:"delay,omitempty" json:"delay,omitempty"`
+	// Decimals of the native token per nid, DefaultTokenDecimals when unset
+	Decimals map[string]uint8 `yaml:"decimals,omitempty" json:"decimals,omitempty"`
+	// Prices of the native token per nid, used when the price file or the plugged feed has none
+	Prices map[string]float64 `yaml:"prices,omitempty" json:"prices,omitempty"`
+	// PriceFile is a yaml or json file of prices per nid kept up to date by a local process
+	PriceFile string `yaml:"price-file,omitempty" json:"price-file,omitempty"`
+	// Adjust sets the fees of the source connections from the checked costs, disabled when nil
+	Adjust *FeeAdjustConfig `yaml:"fee-adjustment,omitempty" json:"fee-adjustment,omitempty"`
+}
+
+// Validate checks the profitability values
+func (c *ProfitConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Action {
+	case "", ProfitActionAlert, ProfitActionDelay, ProfitActionSkip:
+	default:
+		return fmt.Errorf("profitability action must be one of alert, delay or skip: %s", c.Action)
+	}
+	if c.Margin < 0 {
+		return fmt.Errorf("profitability margin cannot be negative: %f", c.Margin)
+	}
+	if c.Delay < 0 {
+		return fmt.Errorf("profitability delay cannot be negative: %s", c.Delay)
+	}
+	for nid, price := range c.Prices {
+		if price < 0 {
+			return fmt.Errorf("profitability price of %s cannot be negative: %f", nid, price)
+		}
+	}
+	return c.Adjust.Validate()
+}
+
+func (c *ProfitConfig) action() ProfitAction {
+	if c.Action == "" {
+		return ProfitActionAlert
+	}
+	return c.Action
+}
+
+func (c *ProfitConfig) delay() time.Duration {
+	if c.Delay == 0 {
+		return DefaultProfitDelay
+	}
+	return c.Delay
+}
+
+func (c *ProfitConfig) decimals(nid string) uint8 {
+	if decimals, ok := c.Decimals[nid]; ok {
+		return decimals
+	}
+	return DefaultTokenDecimals
+}
+
+// ErrNoPrice is returned by a price feed that has no price for the chain
+var ErrNoPrice = errors.New("no price")
+
+// PriceFeed provides the price of the native token of a chain in the common unit
+type PriceFeed interface {
+	Price(ctx context.Context, nid string) (float64, error)
+}
+
+// StaticPriceFeed prices the native tokens with fixed prices per nid
+type StaticPriceFeed map[string]float64
+
+func (f StaticPriceFeed) Price(_ context.Context, nid string) (float64, error) {
+	price, ok := f[nid]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", nid, ErrNoPrice)
+	}
+	return price, nil
+}
+
+// FilePriceFeed reads the prices per nid from a yaml or json file, the file is read again when
+// it changes so that the prices can be updated without touching the relayer
+type FilePriceFeed struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	prices  StaticPriceFeed
+}
+
+func NewFilePriceFeed(path string) *FilePriceFeed {
+	return &FilePriceFeed{path: path}
+}
+
+func (f *FilePriceFeed) Price(ctx context.Context, nid string) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.ModTime().Equal(f.modTime) {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return 0, err
+		}
+		prices := make(StaticPriceFeed)
+		if err := yaml.Unmarshal(data, &prices); err != nil {
+			return 0, fmt.Errorf("invalid price file %s: %w", f.path, err)
+		}
+		f.prices, f.modTime = prices, info.ModTime()
+	}
+	return f.prices.Price(ctx, nid)
+}
+
+// PriceFeeds asks the feeds in order and returns the first price found
+type PriceFeeds []PriceFeed
+
+func (f PriceFeeds) Price(ctx context.Context, nid string) (float64, error) {
+	errs := make([]error, 0, len(f))
+	for _, feed := range f {
+		price, err := feed.Price(ctx, nid)
+		if err == nil {
+			return price, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return 0, fmt.Errorf("%s: %w", nid, ErrNoPrice)
+	}
+	return 0, errors.Join(errs...)
+}
+
+// RouteCost is the last profitability check of a route
+type RouteCost struct {
+	Src string
+	Dst string
+	// Fee is charged by the source connection, in the source denomination
+	Fee uint64
+	// Cost is the estimated delivery fee on the destination, in the destination denomination
+	Cost uint64
+	// FeeValue and CostValue are the fee and the cost in the common unit
+	FeeValue  float64
+	CostValue float64
+	// SuggestedFee is the lowest fee passing the check, in the source denomination
+	SuggestedFee uint64
+	Profitable   bool
+	CheckedAt    time.Time
+}
+
+// profitChecker prices the routes and keeps their last check
+type profitChecker struct {
+	cfg   *ProfitConfig
+	feeds PriceFeeds
+	mu    sync.RWMutex
+	// plugged is the feed set with SetPriceFeed, it is asked first
+	plugged PriceFeed
+	routes  map[string]RouteCost
+}
+
+func newProfitChecker(cfg *ProfitConfig) *profitChecker {
+	if cfg == nil {
+		return nil
+	}
+	p := &profitChecker{
+		cfg:    cfg,
+		routes: make(map[string]RouteCost),
+	}
+	if cfg.PriceFile != "" {
+		p.feeds = append(p.feeds, NewFilePriceFeed(cfg.PriceFile))
+	}
+	if len(cfg.Prices) > 0 {
+		p.feeds = append(p.feeds, StaticPriceFeed(cfg.Prices))
+	}
+	return p
+}
+
+func (p *profitChecker) price(ctx context.Context, nid string) (float64, error) {
+	p.mu.RLock()
+	feeds := p.feeds
+	if p.plugged != nil {
+		feeds = append(PriceFeeds{p.plugged}, feeds...)
+	}
+	p.mu.RUnlock()
+	return feeds.Price(ctx, nid)
+}
+
+// value converts an amount in the smallest denomination of the chain to the common unit
+func (p *profitChecker) value(amount uint64, decimals uint8, price float64) float64 {
+	v := new(big.Float).SetUint64(amount)
+	v.Quo(v, new(big.Float).SetFloat64(math.Pow10(int(decimals))))
+	v.Mul(v, big.NewFloat(price))
+	f, _ := v.Float64()
+	return f
+}
+
+// check prices the delivery of an emitted message against the fee of its route,
+// the other messages are not charged by the connection and are not checked
+func (p *profitChecker) check(ctx context.Context, src, dst *ChainRuntime, m *types.Message) (*RouteCost, error) {
+	if p == nil || m.EventType != events.EmitMessage {
+		return nil, nil
+	}
+	srcPrice, err := p.price(ctx, m.Src)
+	if err != nil {
+		return nil, err
+	}
+	dstPrice, err := p.price(ctx, m.Dst)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := src.Provider.GetFee(ctx, m.Dst, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee: %w", err)
+	}
+	cost, err := dst.Provider.EstimateCost(ctx, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate cost: %w", err)
+	}
+
+	rc := RouteCost{
+		Src:       m.Src,
+		Dst:       m.Dst,
+		Fee:       fee,
+		Cost:      cost,
+		FeeValue:  p.value(fee, p.cfg.decimals(m.Src), srcPrice),
+		CostValue: p.value(cost, p.cfg.decimals(m.Dst), dstPrice),
+		CheckedAt: time.Now(),
+	}
+	rc.Profitable = rc.CostValue <= rc.FeeValue*(1+p.cfg.Margin)
+	if srcPrice > 0 {
+		suggested := rc.CostValue / (1 + p.cfg.Margin) / srcPrice * math.Pow10(int(p.cfg.decimals(m.Src)))
+		rc.SuggestedFee = uint64(math.Ceil(suggested))
+	}
+
+	route := m.Src + "/" + m.Dst
+	p.mu.Lock()
+	p.routes[route] = rc
+	p.mu.Unlock()
+	return &rc, nil
+}
+
+// routeCosts returns the last checks of the routes from src or of all the routes when src is empty
+func (p *profitChecker) routeCosts(src string) []RouteCost {
+	if p == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	costs := make([]RouteCost, 0, len(p.routes))
+	for _, rc := range p.routes {
+		if src == "" || rc.Src == src {
+			costs = append(costs, rc)
+		}
+	}
+	sort.Slice(costs, func(i, j int) bool {
+		if costs[i].Src != costs[j].Src {
+			return costs[i].Src < costs[j].Src
+		}
+		return costs[i].Dst < costs[j].Dst
+	})
+	return costs
+}
+
+// isProfitable applies the profitability action to an unprofitable message, it returns false
+// when the message is not to be routed now. The message is routed when it cannot be priced.
+func (r *Relayer) isProfitable(ctx context.Context, src, dst *ChainRuntime, m *types.RouteMessage) bool {
+	rc, err := r.profit.check(ctx, src, dst, m.Message)
+	if err != nil {
+		dst.log.Warn("failed to check relay profitability", zap.String("src", m.Src), zap.Uint64("sn", m.Sn.Uint64()), zap.Error(err))
+		return true
+	}
+	if rc == nil || rc.Profitable {
+		return true
+	}
+
+	fields := []zap.Field{
+		zap.String("src", m.Src),
+		zap.Uint64("sn", m.Sn.Uint64()),
+		zap.Uint64("fee", rc.Fee),
+		zap.Uint64("cost", rc.Cost),
+		zap.Float64("fee_value", rc.FeeValue),
+		zap.Float64("cost_value", rc.CostValue),
+		zap.Uint64("suggested_fee", rc.SuggestedFee),
+	}
+	switch r.profit.cfg.action() {
+	case ProfitActionSkip:
+		r.rejectMessage(src, m, fmt.Sprintf("relay cost %g exceeds fee %g", rc.CostValue, rc.FeeValue))
+		return false
+	case ProfitActionDelay:
+		dst.log.Info("holding unprofitable message", fields...)
+		m.SetNextTry(r.profit.cfg.delay())
+		if err := r.transition(m, types.MessageStatusDetected); err == nil {
+			r.schedule(src, m)
+		}
+		return false
+	}
+	dst.log.Warn("relaying unprofitable message", fields...)
+	return true
+}
+
+// SetPriceFeed plugs a price feed asked before the configured prices, it must be called
+// after SetConfig and has no effect when the profitability check is not configured
+func (r *Relayer) SetPriceFeed(feed PriceFeed) {
+	if r.profit == nil {
+		return
+	}
+	r.profit.mu.Lock()
+	defer r.profit.mu.Unlock()
+	r.profit.plugged = feed
+}
+
+// GetRouteCosts returns the last profitability checks of the routes from the chain,
+// or of all the routes when nId is empty
+func (r *Relayer) GetRouteCosts(nId string) []RouteCost {
+	return r.profit.routeCosts(nId)
+}
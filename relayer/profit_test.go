@@ -0,0 +1,101 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/chains/mockchain"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+func TestProfitCheck(t *testing.T) {
+	priceFile := filepath.Join(t.TempDir(), "prices.yaml")
+	assert.NoError(t, os.WriteFile(priceFile, []byte("mock-1: 2\n"), 0o644))
+
+	data := `
+profitability:
+  action: skip
+  margin: 0.2
+  decimals:
+    mock-2: 6
+  prices:
+    mock-1: 100
+    mock-2: 1
+  price-file: ` + priceFile + `
+`
+	cfg := new(Config)
+	assert.NoError(t, yaml.Unmarshal([]byte(data), cfg))
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, ProfitActionSkip, cfg.Profit.action())
+	assert.Equal(t, DefaultProfitDelay, cfg.Profit.delay())
+
+	invalid := &Config{Profit: &ProfitConfig{Action: "drop"}}
+	assert.Error(t, invalid.Validate())
+
+	newChain := func(nId, dstNId string, fee, cost uint64) *ChainRuntime {
+		provider, err := GetMockChainProvider(zap.NewNop(), time.Second, nId, dstNId, 10, 20)
+		assert.NoError(t, err)
+		provider.(*mockchain.MockProvider).SetFees(fee, cost)
+		chain, err := NewChainRuntime(zap.NewNop(), NewChain(zap.NewNop(), provider, false))
+		assert.NoError(t, err)
+		return chain
+	}
+	// a fee of 0.0001 at 2 against a cost of 0.00025 at 1
+	src := newChain("mock-1", "mock-2", 100_000_000_000_000, 0)
+	dst := newChain("mock-2", "mock-1", 0, 250)
+	msg := &types.Message{Src: "mock-1", Dst: "mock-2", Sn: big.NewInt(1), EventType: "emitMessage"}
+
+	checker := newProfitChecker(cfg.Profit)
+	ctx := context.Background()
+
+	t.Run("price file first", func(t *testing.T) {
+		rc, err := checker.check(ctx, src, dst, msg)
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.0002, rc.FeeValue, 1e-12)
+		assert.InDelta(t, 0.00025, rc.CostValue, 1e-12)
+		assert.False(t, rc.Profitable)
+		assert.InDelta(t, 104_166_666_666_667, float64(rc.SuggestedFee), 1e3)
+	})
+
+	t.Run("price file update", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(priceFile, []byte("mock-1: 3\n"), 0o644))
+		assert.NoError(t, os.Chtimes(priceFile, time.Now(), time.Now().Add(time.Minute)))
+		rc, err := checker.check(ctx, src, dst, msg)
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.0003, rc.FeeValue, 1e-12)
+		assert.True(t, rc.Profitable)
+	})
+
+	t.Run("plugged feed", func(t *testing.T) {
+		checker.plugged = StaticPriceFeed{"mock-2": 2}
+		rc, err := checker.check(ctx, src, dst, msg)
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.0005, rc.CostValue, 1e-12)
+		assert.False(t, rc.Profitable)
+		checker.plugged = nil
+	})
+
+	t.Run("no price", func(t *testing.T) {
+		_, err := newProfitChecker(&ProfitConfig{}).check(ctx, src, dst, msg)
+		assert.ErrorIs(t, err, ErrNoPrice)
+	})
+
+	t.Run("not charged", func(t *testing.T) {
+		call := &types.Message{Src: "mock-1", Dst: "mock-1", Sn: big.NewInt(1), EventType: "callMessage"}
+		rc, err := checker.check(ctx, src, dst, call)
+		assert.NoError(t, err)
+		assert.Nil(t, rc)
+	})
+
+	costs := checker.routeCosts("mock-1")
+	assert.Len(t, costs, 1)
+	assert.Equal(t, "mock-2", costs[0].Dst)
+	assert.Empty(t, checker.routeCosts("mock-2"))
+}
@@ -18,9 +18,20 @@ type Config interface {
 	Enabled() bool
 }
 
+// WalletPoolConfig is implemented by the configs of the chains signing with a pool of wallets
+type WalletPoolConfig interface {
+	// GetWallets returns the wallets signing along the configured one
+	GetWallets() []string
+	SetWallets([]string)
+}
+
 type ChainQuery interface {
 	QueryLatestHeight(ctx context.Context) (uint64, error)
 	QueryTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error)
+	// QueryBlockHash returns the hash of the block, empty when the chain blocks are final once committed
+	QueryBlockHash(ctx context.Context, height uint64) (string, error)
+	// QueryFinalizedHeight returns the height of the latest finalized block
+	QueryFinalizedHeight(ctx context.Context) (uint64, error)
 }
 
 type ChainProvider interface {
@@ -47,8 +58,30 @@ type ChainProvider interface {
 	ImportKeystore(context.Context, string, string) (string, error)
 	RevertMessage(context.Context, *big.Int) error
 	GetFee(context.Context, string, bool) (uint64, error)
+	// EstimateCost returns the fee paid to deliver the message, in the smallest denomination of the chain
+	EstimateCost(context.Context, *types.Message) (uint64, error)
 	SetFee(context.Context, string, *big.Int, *big.Int) error
 	ClaimFee(context.Context) error
+	// QueryClaimableFee returns the fees accrued on the connection contract, in the smallest denomination of the chain
+	QueryClaimableFee(context.Context) (*big.Int, error)
+	// Transfer sends native tokens from the relayer wallet
+	Transfer(ctx context.Context, to string, amount *big.Int) error
+}
+
+// Closer is implemented by the providers holding connections to the chain
+type Closer interface {
+	// Close releases the connections, the provider is not used afterwards
+	Close() error
+}
+
+// BatchRouter is implemented by the providers delivering several messages in one transaction
+type BatchRouter interface {
+	// SupportsBatch reports whether the chain config allows batching
+	SupportsBatch() bool
+	// RouteBatch delivers the messages in one transaction and reports it to the callback of every
+	// message with its share of the cost. A message failing on its own is reported to its callback
+	// with the error, the error returned fails the messages not reported failed.
+	RouteBatch(ctx context.Context, messages []*types.Message, callback types.TxResponseFunc) error
 }
 
 // CommonConfig is the common configuration for all chain providers
@@ -3,12 +3,14 @@ package relayer
 import (
 	"context"
 	"fmt"
+	"maps"
+	"math/big"
+	"sync"
 	"time"
 
 	"github.com/icon-project/centralized-relay/relayer/store"
 	"github.com/icon-project/centralized-relay/relayer/types"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -21,44 +23,118 @@ var (
 	DeleteExpiredInterval      = 6 * time.Hour
 	MessageExpiration          = 24 * time.Hour
 
-	prefixMessageStore  = "message"
-	prefixBlockStore    = "block"
-	prefixFinalityStore = "finality"
+	prefixMessageStore    = "message"
+	prefixBlockStore      = "block"
+	prefixFinalityStore   = "finality"
+	prefixPendingTxStore  = "pending"
+	prefixDeadLetterStore = "dlq"
+	prefixDeliveredStore  = "delivered"
+	prefixBlockRecord     = "blockrecord"
+	prefixRejectedStore   = "rejected"
+	prefixFeeAuditStore   = "feeaudit"
+	prefixFeeClaimStore   = "feeclaim"
+	prefixHistoryStore    = "history"
 )
 
 // main start loop
 func (r *Relayer) Start(ctx context.Context, flushInterval time.Duration, fresh bool) (chan error, error) {
 	errorChan := make(chan error, 1)
 
+	// Shutdown stops the relayer through this ctx
+	ctx, r.stop = context.WithCancel(ctx)
+
+	// settle the transactions broadcast before the last shutdown
+	r.reconcilePendingTxs(ctx)
+
 	// once flush completes then only start processing
 	if fresh {
 		// flush all the packet and then continue
 		r.flushMessages(ctx)
 	}
 
-	// // create ctx -> with cancel function and senc cancel function to all -> ctx.done():
-	// // start all the chain listeners
-	go r.StartChainListeners(ctx, errorChan)
-
-	// // start all the block processor
-	go r.StartBlockProcessors(ctx, errorChan)
+	// start the listener, the block processor and the route workers of every chain,
+	// the chains added by a config reload are started the same way
+	r.chainsMu.Lock()
+	r.ctx, r.errorChan = ctx, errorChan
+	for _, chain := range r.chains {
+		r.startChain(chain)
+	}
+	r.chainsMu.Unlock()
 
 	// responsible to relaying  messages
-	go r.StartRouter(ctx, flushInterval)
+	r.goService(func() { r.StartRouter(ctx, flushInterval) })
 
 	// responsible for checking finality
-	go r.StartFinalityProcessor(ctx)
+	r.goService(func() { r.StartFinalityProcessor(ctx) })
+
+	// responsible for detecting source chain reorgs
+	r.goService(func() { r.StartReorgDetector(ctx) })
+
+	// responsible for keeping the route fees in line with the delivery costs
+	r.goService(func() { r.StartFeeAdjuster(ctx) })
+
+	// responsible for claiming the fees accrued on the connections
+	r.goService(func() { r.StartFeeClaimer(ctx) })
+
+	// responsible for watching the relayer wallet balances
+	r.goService(func() { r.StartBalanceMonitor(ctx) })
+
+	// responsible for serving the metrics
+	r.goService(func() { r.StartMetricsServer(ctx) })
+
+	// responsible for applying the retention policy of the relay history
+	r.goService(func() { r.StartHistoryPruner(ctx) })
 
 	return errorChan, nil
 }
 
 type Relayer struct {
-	log           *zap.Logger
-	db            store.Store
-	chains        map[string]*ChainRuntime
-	messageStore  *store.MessageStore
-	blockStore    *store.BlockStore
-	finalityStore *store.FinalityStore
+	log              *zap.Logger
+	cfg              *Config
+	profit           *profitChecker
+	db               store.Store
+	messageStore     *store.MessageStore
+	blockStore       *store.BlockStore
+	finalityStore    *store.FinalityStore
+	pendingTxStore   *store.PendingTxStore
+	deadLetterStore  *store.DeadLetterStore
+	deliveredStore   *store.DeliveredStore
+	blockRecordStore *store.BlockRecordStore
+	rejectedStore    *store.RejectedStore
+	feeAuditStore    *store.FeeAuditStore
+	feeClaimStore    *store.FeeClaimStore
+	historyStore     *store.HistoryStore
+
+	// chainsMu guards the chain set, it changes on a config reload
+	chainsMu sync.RWMutex
+	chains   map[string]*ChainRuntime
+	queues   map[string]*workQueue
+	// detached keeps the runtimes removed by a reload, to restore their state if they come back
+	detached map[string]*ChainRuntime
+	reloadMu sync.Mutex
+	// loadChains reads the chain config for Reload
+	loadChains ChainLoader
+	// ctx and errorChan of Start, for the chains started later
+	ctx       context.Context
+	errorChan chan error
+
+	// stop cancels the ctx the relayer was started with
+	stop context.CancelFunc
+	// routeCtx outlives stop so the in-flight routes can wait for their receipts on shutdown
+	routeCtx    context.Context
+	cancelRoute context.CancelFunc
+	workers     sync.WaitGroup
+	// services tracks the background goroutines of the relayer, they use the db until they return
+	services sync.WaitGroup
+}
+
+// goService runs fn in a goroutine tracked by services
+func (r *Relayer) goService(fn func()) {
+	r.services.Add(1)
+	go func() {
+		defer r.services.Done()
+		fn()
+	}()
 }
 
 func NewRelayer(log *zap.Logger, db store.Store, chains map[string]*Chain, fresh bool) (*Relayer, error) {
@@ -69,6 +145,12 @@ func NewRelayer(log *zap.Logger, db store.Store, chains map[string]*Chain, fresh
 		}
 	}
 
+	// the keys of an older schema are not readable by the stores
+	if err := store.CheckSchemaVersion(db); err != nil {
+		return nil, err
+	}
+	db = store.WithIndexers(db, store.NewMessageIndexer(prefixMessageStore), store.NewHistoryIndexer(prefixHistoryStore))
+
 	// initializing message store
 	messageStore := store.NewMessageStore(db, prefixMessageStore)
 
@@ -78,32 +160,60 @@ func NewRelayer(log *zap.Logger, db store.Store, chains map[string]*Chain, fresh
 	// finality store
 	finalityStore := store.NewFinalityStore(db, prefixFinalityStore)
 
-	chainRuntimes := make(map[string]*ChainRuntime, len(chains))
+	// pending transaction store
+	pendingTxStore := store.NewPendingTxStore(db, prefixPendingTxStore)
+
+	// dead letter store
+	deadLetterStore := store.NewDeadLetterStore(db, prefixDeadLetterStore)
+
+	// delivered store
+	deliveredStore := store.NewDeliveredStore(db, prefixDeliveredStore)
+
+	// block record store
+	blockRecordStore := store.NewBlockRecordStore(db, prefixBlockRecord)
+
+	// rejected message store
+	rejectedStore := store.NewRejectedStore(db, prefixRejectedStore)
+
+	// fee audit store
+	feeAuditStore := store.NewFeeAuditStore(db, prefixFeeAuditStore)
+
+	// fee claim store
+	feeClaimStore := store.NewFeeClaimStore(db, prefixFeeClaimStore)
+
+	// relay history store
+	historyStore := store.NewHistoryStore(db, prefixHistoryStore)
+
+	routeCtx, cancelRoute := context.WithCancel(context.Background())
+
+	r := &Relayer{
+		log:              log,
+		cfg:              new(Config),
+		db:               db,
+		chains:           make(map[string]*ChainRuntime, len(chains)),
+		queues:           make(map[string]*workQueue, len(chains)),
+		detached:         make(map[string]*ChainRuntime),
+		messageStore:     messageStore,
+		blockStore:       blockStore,
+		finalityStore:    finalityStore,
+		pendingTxStore:   pendingTxStore,
+		deadLetterStore:  deadLetterStore,
+		deliveredStore:   deliveredStore,
+		blockRecordStore: blockRecordStore,
+		rejectedStore:    rejectedStore,
+		feeAuditStore:    feeAuditStore,
+		feeClaimStore:    feeClaimStore,
+		historyStore:     historyStore,
+		stop:             func() {},
+		routeCtx:         routeCtx,
+		cancelRoute:      cancelRoute,
+	}
 	for _, chain := range chains {
-		chainRuntime, err := NewChainRuntime(log, chain)
-		if err != nil {
+		if _, err := r.attachChain(chain); err != nil {
 			return nil, err
 		}
-
-		lastSavedHeight, err := blockStore.GetLastStoredBlock(chain.NID())
-		if err == nil {
-			// successfully fetched last savedBlock
-			chainRuntime.LastSavedHeight = lastSavedHeight
-		}
-		chainRuntimes[chain.NID()] = chainRuntime
-		chainRuntime.Provider.SetLastSavedHeightFunc(func() uint64 {
-			return chainRuntime.LastSavedHeight
-		})
 	}
-
-	return &Relayer{
-		log:           log,
-		db:            db,
-		chains:        chainRuntimes,
-		messageStore:  messageStore,
-		blockStore:    blockStore,
-		finalityStore: finalityStore,
-	}, nil
+	return r, nil
 }
 
 // GetBlockStore returns the block store
@@ -116,49 +226,42 @@ func (r *Relayer) GetMessageStore() *store.MessageStore {
 	return r.messageStore
 }
 
-func (r *Relayer) StartChainListeners(ctx context.Context, errCh chan error) {
-	var eg errgroup.Group
-
-	for _, chainRuntime := range r.chains {
-		eg.Go(func() error {
-			return chainRuntime.Provider.Listener(ctx, chainRuntime.LastSavedHeight, chainRuntime.listenerChan)
-		})
-	}
-	if err := eg.Wait(); err != nil {
-		errCh <- err
-	}
+// GetDeadLetterStore returns the dead letter store
+func (r *Relayer) GetDeadLetterStore() *store.DeadLetterStore {
+	return r.deadLetterStore
 }
 
-func (r *Relayer) StartBlockProcessors(ctx context.Context, errorChan chan error) {
-	var eg errgroup.Group
-
-	for _, chainRuntime := range r.chains {
-		eg.Go(func() error {
-			for {
+// processBlocks handles the blocks delivered by the chain listener until ctx is done
+func (r *Relayer) processBlocks(ctx context.Context, chain *ChainRuntime) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case blockInfo, ok := <-chain.listenerChan:
+			if !ok {
 				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case blockInfo, ok := <-chainRuntime.listenerChan:
-					if !ok {
-						return fmt.Errorf("listener channel closed")
-					}
-					r.processBlockInfo(ctx, chainRuntime, blockInfo)
+				case r.errorChan <- fmt.Errorf("listener channel closed"):
+				default:
 				}
+				return
 			}
-		})
-	}
-
-	if err := eg.Wait(); err != nil {
-		errorChan <- err // Report the error to the error channel.
+			chain.markHealthy()
+			r.processBlockInfo(ctx, chain, blockInfo)
+		}
 	}
 }
 
 func (r *Relayer) StartRouter(ctx context.Context, flushInterval time.Duration) {
-	routeTimer := time.NewTicker(types.RouteDuration)
 	flushTimer := time.NewTicker(1 * time.Second)
 	heightTimer := time.NewTicker(HeightSaveInterval)
 	cleanMessageTimer := time.NewTicker(1 * time.Second)
 	resetTimer := time.NewTicker(3 * time.Second)
+	defer func() {
+		flushTimer.Stop()
+		heightTimer.Stop()
+		cleanMessageTimer.Stop()
+		resetTimer.Stop()
+	}()
 
 	for {
 		select {
@@ -166,14 +269,14 @@ func (r *Relayer) StartRouter(ctx context.Context, flushInterval time.Duration)
 			return
 		case <-flushTimer.C:
 			// flushMessage gets all the message from DB
-			go r.flushMessages(ctx)
-		case <-routeTimer.C:
-			// processMessage starting working on all the runtime Messages
-			r.processMessages(ctx)
+			r.goService(func() {
+				r.flushMessages(ctx)
+				r.sweepMessages()
+			})
 		case <-heightTimer.C:
-			go r.SaveChainsBlockHeight(ctx)
+			r.goService(func() { r.SaveChainsBlockHeight(ctx) })
 		case <-cleanMessageTimer.C:
-			go r.cleanExpiredMessages(ctx)
+			r.goService(func() { r.cleanExpiredMessages(ctx) })
 		case <-resetTimer.C:
 			resetTimer.Stop()
 			flushTimer.Reset(flushInterval)
@@ -184,9 +287,8 @@ func (r *Relayer) StartRouter(ctx context.Context, flushInterval time.Duration)
 
 func (r *Relayer) flushMessages(ctx context.Context) {
 	r.log.Debug("flushing messages from db to cache")
-	for _, chain := range r.chains {
-		nId := chain.Provider.NID()
-		messages, err := r.getActiveMessagesFromStore(nId, maxFlushMessage)
+	for _, chain := range r.chainRuntimes() {
+		messages, err := r.getActiveMessagesFromStore(chain, maxFlushMessage)
 		if err != nil {
 			chain.log.Warn("error occured when query messagesFromStore", zap.Error(err))
 			continue
@@ -196,88 +298,325 @@ func (r *Relayer) flushMessages(ctx context.Context) {
 		// TODO: message with no txHash
 
 		for _, m := range messages {
-			chain.MessageCache.Add(m)
+			r.resumeMessage(ctx, chain, m)
 		}
 	}
 }
 
-// TODO: optimize the logic
-func (r *Relayer) getActiveMessagesFromStore(nId string, maxMessages uint) ([]*types.RouteMessage, error) {
-	var activeMessages []*types.RouteMessage
+// resumeMessage puts a message loaded from the store back into the pipeline
+// according to the last state persisted for it
+func (r *Relayer) resumeMessage(ctx context.Context, src *ChainRuntime, m *types.RouteMessage) {
+	switch m.GetStatus() {
+	case types.MessageStatusConfirmed:
+		// delivered, the finality processor owns it from here
+		return
+	case types.MessageStatusQueued:
+		// routing never started, safe to pick up again
+		if err := r.transition(m, types.MessageStatusDetected); err != nil {
+			return
+		}
+	case types.MessageStatusSubmitted:
+		if tx, err := r.pendingTxStore.GetPendingTx(m.MessageKey()); err == nil {
+			if dst, ok := r.chain(m.Dst); ok {
+				r.reconcilePendingTx(ctx, dst, tx)
+				return
+			}
+		}
+		// the destination transaction may already be in flight, hold the message
+		// for a retry interval so that the delivery check can observe it
+		// before anything is sent again
+		src.log.Info("resuming submitted message",
+			zap.String("src", m.Src),
+			zap.String("dst", m.Dst),
+			zap.Uint64("sn", m.Sn.Uint64()),
+			zap.String("event_type", m.EventType),
+		)
+		m.SetNextTry(r.retryPolicy(m).Delay(m.GetRetry()))
+		if err := r.transition(m, types.MessageStatusDetected); err != nil {
+			return
+		}
+	}
+	r.EnqueueMessage(src, m)
+}
 
-	p := store.NewPagination().WithLimit(maxMessages)
-	msgs, err := r.messageStore.GetMessages(nId, p)
+// transition moves the message to the next lifecycle state and persists it
+func (r *Relayer) transition(m *types.RouteMessage, status types.MessageStatus) error {
+	tx := r.newStoreTx()
+	if err := r.stageTransition(tx, m, status); err != nil {
+		return err
+	}
+	if err := tx.commit(); err != nil {
+		r.log.Error("failed to persist message state", zap.Any("message-key", m.MessageKey()), zap.String("status", string(status)), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// TODO: optimize the logic
+// getActiveMessagesFromStore returns up to maxMessages stored messages of the source chain to resume,
+// it pages past the ones that are not: failed, out of retries, waiting for the destination finality
+// or already in the cache, whose copy carries the live state
+func (r *Relayer) getActiveMessagesFromStore(src *ChainRuntime, maxMessages uint) ([]*types.RouteMessage, error) {
+	nId := src.Provider.NID()
+	// the messages are cached once stored, the store is only read when some are not
+	stored, err := r.messageStore.TotalCountByChain(nId)
 	if err != nil {
 		return nil, err
 	}
-	for _, m := range msgs {
-		if !m.IsStale() {
-			activeMessages = append(activeMessages, m)
-		}
+	if stored <= uint(src.MessageCache.Len()) {
+		return nil, nil
 	}
-	return activeMessages, nil
-}
 
-func (r *Relayer) processMessages(ctx context.Context) {
-	for _, src := range r.chains {
-		for key, message := range src.MessageCache.Messages {
-			dst, err := r.FindChainRuntime(message.Dst)
-			if err != nil {
-				r.log.Error("dst chain nid not found", zap.String("nid", message.Dst))
-				r.ClearMessages(ctx, []*types.MessageKey{&key}, src)
+	var activeMessages []*types.RouteMessage
+	filter := &store.MessageFilter{Src: nId}
+	p := store.NewPagination().WithLimit(store.DefaultPageSize)
+	for {
+		msgs, next, err := r.messageStore.ListMessages(filter, p)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			switch m.GetStatus() {
+			case types.MessageStatusFailed, types.MessageStatusConfirmed:
 				continue
 			}
-
-			if ok := dst.shouldSendMessage(ctx, message, src); !ok {
-				r.log.Debug("processing", zap.Any("message", message))
+			if r.retryPolicy(m).Exhausted(m.GetRetry()) {
 				continue
 			}
+			if _, ok := src.MessageCache.Get(m.MessageKey()); ok {
+				continue
+			}
+			activeMessages = append(activeMessages, m)
+			if uint(len(activeMessages)) == maxMessages {
+				return activeMessages, nil
+			}
+		}
+		if next == nil {
+			return activeMessages, nil
+		}
+		p = store.NewPagination().WithLimit(store.DefaultPageSize).WithCursor(next)
+	}
+}
+
+// routeWorker stops picking up messages once ctx is done, the message being
+// routed keeps the route ctx so that it can be drained on shutdown
+func (r *Relayer) routeWorker(ctx context.Context, dst *ChainRuntime, q *workQueue) {
+	for {
+		item, ok := q.pop(ctx)
+		if !ok {
+			return
+		}
+		if batch := r.batching(dst); batch != nil {
+			r.collectBatch(ctx, dst, q, item, batch)
+			continue
+		}
+		r.processMessage(r.routeCtx, item.src, dst, item.message)
+		q.done(item)
+	}
+}
 
-			message.ToggleProcessing()
+// schedule queues the message on its destination and wakes up an idle worker
+func (r *Relayer) schedule(src *ChainRuntime, m *types.RouteMessage) {
+	r.chainsMu.RLock()
+	_, running := r.chains[m.Dst]
+	_, detached := r.detached[m.Dst]
+	q := r.queues[m.Dst]
+	r.chainsMu.RUnlock()
+
+	switch {
+	case running:
+		q.push(newWorkItem(src, m))
+	case detached:
+		// the destination was removed by a config reload, hold the message until it is back
+		r.log.Debug("dst chain detached, holding message", zap.String("nid", m.Dst))
+	default:
+		r.log.Error("dst chain nid not found", zap.String("nid", m.Dst))
+		r.ClearMessages(context.Background(), []*types.MessageKey{m.MessageKey()}, src)
+	}
+}
 
-			// if message reached delete the message
-			messageReceived, err := dst.Provider.MessageReceived(ctx, &key)
-			if err != nil {
-				dst.log.Error("error occured when checking message received", zap.String("src", message.Src), zap.Uint64("sn", message.Sn.Uint64()), zap.Error(err))
-				message.ToggleProcessing()
-				continue
+// EnqueueMessage adds the message to the source cache and schedules it
+func (r *Relayer) EnqueueMessage(src *ChainRuntime, m *types.RouteMessage) {
+	src.MessageCache.Add(m)
+	r.schedule(src, m)
+}
+
+// sweepMessages schedules the cached messages waiting for a try,
+// a safety net for the messages that missed their wakeup
+func (r *Relayer) sweepMessages() {
+	for _, src := range r.chainRuntimes() {
+		src.MessageCache.Range(func(m *types.RouteMessage) bool {
+			if m.GetStatus() == types.MessageStatusDetected {
+				r.schedule(src, m)
 			}
+			return true
+		})
+	}
+}
 
-			// if message is received we can remove the message from db
-			if messageReceived {
-				dst.log.Info("message already received", zap.String("src", message.Src), zap.Uint64("sn", message.Sn.Uint64()))
-				r.ClearMessages(ctx, []*types.MessageKey{&key}, src)
-				continue
+// processMessage runs the delivery checks of a message picked from the destination queue and routes it
+func (r *Relayer) processMessage(ctx context.Context, src, dst *ChainRuntime, message *types.RouteMessage) {
+	if r.deliverable(ctx, src, dst, message) {
+		r.RouteMessage(ctx, message, dst, src)
+	}
+}
+
+// deliverable runs the delivery checks of a message picked from the destination queue and reports
+// whether it is to be routed now, a message that is not is rescheduled, rejected or cleared
+func (r *Relayer) deliverable(ctx context.Context, src, dst *ChainRuntime, message *types.RouteMessage) bool {
+	key := message.MessageKey()
+	// the message left the cache while it was queued
+	if _, ok := src.MessageCache.Get(key); !ok {
+		return false
+	}
+
+	ok, reason := dst.shouldSendMessage(ctx, message, src, r.cfg.Filter)
+	if reason != "" {
+		r.rejectMessage(src, message, reason)
+		return false
+	}
+	if !ok {
+		r.log.Debug("processing", zap.Any("message", message.Clone()))
+		if message.GetStatus() == types.MessageStatusDetected {
+			// not ready yet, look at it again on its next try
+			if !message.IsProcessing() {
+				message.SetNextTry(types.RetryInterval)
 			}
-			go r.RouteMessage(ctx, message, dst, src)
+			r.schedule(src, message)
 		}
+		return false
 	}
+
+	// hold the message until its block is final on the source
+	if final, err := src.isFinal(ctx, message, r.cfg.Scheduler.source(src.Provider.NID())); !final {
+		if err != nil {
+			src.log.Warn("failed to check source finality", zap.String("src", message.Src), zap.Uint64("sn", message.Sn.Uint64()), zap.Error(err))
+		}
+		message.SetNextTry(SourceFinalityInterval)
+		r.schedule(src, message)
+		return false
+	}
+
+	if err := r.transition(message, types.MessageStatusQueued); err != nil {
+		return false
+	}
+
+	// if message reached delete the message
+	messageReceived, err := dst.Provider.MessageReceived(ctx, key)
+	if err != nil {
+		dst.log.Error("error occured when checking message received", zap.String("src", message.Src), zap.Uint64("sn", message.Sn.Uint64()), zap.Error(err))
+		message.SetNextTry(types.RetryInterval)
+		if err := r.transition(message, types.MessageStatusDetected); err == nil {
+			r.schedule(src, message)
+		}
+		return false
+	}
+
+	// if message is received we can remove the message from db
+	if messageReceived {
+		dst.log.Info("message already received", zap.String("src", message.Src), zap.Uint64("sn", message.Sn.Uint64()))
+		r.finalizeMessage(r.newStoreTx(), message, src, nil)
+		return false
+	}
+	return r.canAfford(ctx, src, dst, message) && r.isProfitable(ctx, src, dst, message)
+}
+
+// undeliveredMessages drops the messages indexed as delivered
+func (r *Relayer) undeliveredMessages(messages []*types.Message) []*types.Message {
+	undelivered := make([]*types.Message, 0, len(messages))
+	for _, m := range messages {
+		if r.deliveredStore.IsDelivered(m.MessageKey()) {
+			r.log.Debug("skipping delivered message", zap.String("src", m.Src), zap.Uint64("sn", m.Sn.Uint64()), zap.String("event_type", m.EventType))
+			continue
+		}
+		undelivered = append(undelivered, m)
+	}
+	return undelivered
+}
+
+// newMessages drops the messages of a block that are delivered or already in the pipeline,
+// listeners emit them again after a reconnect
+func (r *Relayer) newMessages(src *ChainRuntime, messages []*types.Message) []*types.Message {
+	fresh := make([]*types.Message, 0, len(messages))
+	for _, m := range r.undeliveredMessages(messages) {
+		if _, ok := src.MessageCache.Get(m.MessageKey()); ok {
+			continue
+		}
+		if _, err := r.messageStore.GetMessage(m.MessageKey()); err == nil {
+			continue
+		}
+		fresh = append(fresh, m)
+	}
+	return fresh
 }
 
 // processBlockInfo->
 // save block height to database
 // & merge message to src cache
 func (r *Relayer) processBlockInfo(ctx context.Context, src *ChainRuntime, blockInfo *types.BlockInfo) {
-	src.LastBlockHeight = blockInfo.Height
+	src.lastBlockHeight.Store(blockInfo.Height)
+	r.addBlockMessages(ctx, src, blockInfo)
+}
+
+// addBlockMessages stores and schedules the new messages of a block, blocks with a hash are
+// recorded and the messages of the block they replace are retracted
+func (r *Relayer) addBlockMessages(ctx context.Context, src *ChainRuntime, blockInfo *types.BlockInfo) {
+	if blockInfo.Hash != "" {
+		if stale := r.recordBlock(src, blockInfo); stale != nil {
+			src.log.Warn("source chain reorg detected",
+				zap.Uint64("height", blockInfo.Height),
+				zap.String("recorded_hash", stale.Hash),
+				zap.String("hash", blockInfo.Hash),
+			)
+			r.retractBlock(ctx, src, stale)
+		}
+	}
 
-	for _, msg := range blockInfo.Messages {
+	// the messages of the block are stored at once
+	tx := r.newStoreTx()
+	var messages []*types.RouteMessage
+	for _, msg := range r.newMessages(src, blockInfo.Messages) {
 		msg := types.NewRouteMessage(msg)
-		src.MessageCache.Add(msg)
-		if err := r.messageStore.StoreMessage(msg); err != nil {
+		msg.DetectedAt = time.Now()
+		msg.UpdatedAt = msg.DetectedAt
+		if err := tx.messages.StoreMessage(msg); err != nil {
 			r.log.Error("failed to store a message in db", zap.Error(err))
 		}
+		messages = append(messages, msg)
+	}
+	if err := tx.commit(); err != nil {
+		r.log.Error("failed to store the messages of a block in db", zap.Uint64("height", blockInfo.Height), zap.Error(err))
+	}
+	for _, msg := range messages {
+		src.MessageCache.Add(msg)
+		r.schedule(src, msg)
 	}
 }
 
 func (r *Relayer) SaveBlockHeight(ctx context.Context, chainRuntime *ChainRuntime, height uint64) error {
 	r.log.Debug("saving height:", zap.String("srcChain", chainRuntime.Provider.NID()), zap.Uint64("height", height))
-	chainRuntime.LastSavedHeight = height
-	chainRuntime.LastBlockHeight = height
+	chainRuntime.lastSavedHeight.Store(height)
+	chainRuntime.lastBlockHeight.Store(height)
 	return r.blockStore.StoreBlock(height, chainRuntime.Provider.NID())
 }
 
+// chain returns the runtime of a running chain
+func (r *Relayer) chain(nId string) (*ChainRuntime, bool) {
+	r.chainsMu.RLock()
+	defer r.chainsMu.RUnlock()
+	chain, ok := r.chains[nId]
+	return chain, ok
+}
+
+// chainRuntimes returns a snapshot of the running chains
+func (r *Relayer) chainRuntimes() map[string]*ChainRuntime {
+	r.chainsMu.RLock()
+	defer r.chainsMu.RUnlock()
+	return maps.Clone(r.chains)
+}
+
 func (r *Relayer) FindChainRuntime(nId string) (*ChainRuntime, error) {
-	if chain, ok := r.chains[nId]; ok {
+	if chain, ok := r.chain(nId); ok {
 		return chain, nil
 	}
 	return nil, fmt.Errorf("chain runtime not found, nId:%s ", nId)
@@ -285,7 +624,7 @@ func (r *Relayer) FindChainRuntime(nId string) (*ChainRuntime, error) {
 
 func (r *Relayer) GetAllChainsRuntime() []*ChainRuntime {
 	var chains []*ChainRuntime
-	for _, chainRuntime := range r.chains {
+	for _, chainRuntime := range r.chainRuntimes() {
 		chains = append(chains, chainRuntime)
 	}
 	return chains
@@ -299,6 +638,14 @@ func (r *Relayer) callback(ctx context.Context, src, dst *ChainRuntime, key *typ
 			r.log.Error("key not found in messageCache", zap.Any("key", &key))
 			return
 		}
+		if response.Code == types.Pending {
+			// record the broadcast before the receipt is awaited
+			pendingTx := types.NewPendingTransaction(types.NewMessagekeyWithMessageHeight(key, routeMessage.MessageHeight), response.TxHash, response.Nonce)
+			if err := r.pendingTxStore.StorePendingTx(pendingTx); err != nil {
+				r.log.Error("error occured: while storing pending transaction in db", zap.String("tx_hash", response.TxHash), zap.Error(err))
+			}
+			return
+		}
 		if response.Code == types.Success {
 			dst.log.Info("message relayed successfully",
 				zap.String("src", src.Provider.NID()),
@@ -308,51 +655,369 @@ func (r *Relayer) callback(ctx context.Context, src, dst *ChainRuntime, key *typ
 				zap.String("tx_hash", response.TxHash),
 			)
 
+			// the pending transaction, the finality record and the message state are written at once
+			tx := r.newStoreTx()
+			if err := tx.pendingTxs.DeletePendingTx(key); err != nil {
+				r.log.Warn("failed to delete pending transaction", zap.Any("message-key", key), zap.Error(err))
+			}
+
 			// cannot clear incase of finality block
 			if dst.Provider.FinalityBlock(ctx) > 0 {
 				txObj := types.NewTransactionObject(types.NewMessagekeyWithMessageHeight(key, routeMessage.MessageHeight), response.TxHash, uint64(response.Height))
+				txObj.GasUsed, txObj.Fee = response.GasUsed, response.Fee
 				r.log.Info("storing txhash to check finality later", zap.Any("txObj", txObj))
-				if err := r.finalityStore.StoreTxObject(txObj); err != nil {
+				if err := tx.finality.StoreTxObject(txObj); err != nil {
 					r.log.Error("error occured: while storing transaction object in db", zap.Error(err))
 					return
 				}
+				// keep the confirmed message until the finality processor settles it
+				if err := r.stageTransition(tx, routeMessage, types.MessageStatusConfirmed); err != nil {
+					return
+				}
+				if err := tx.commit(); err != nil {
+					r.log.Error("error occured: while storing confirmed message in db", zap.Any("message-key", key), zap.Error(err))
+					return
+				}
+				src.MessageCache.Remove(key)
+				return
 			}
-			// if success remove message from everywhere
-			if err := r.ClearMessages(ctx, []*types.MessageKey{key}, src); err != nil {
-				r.log.Error("error occured when clearing successful message", zap.Error(err))
+			if err := r.stageTransition(tx, routeMessage, types.MessageStatusConfirmed); err != nil {
+				return
 			}
+			// if success remove message from everywhere
+			r.finalizeMessage(tx, routeMessage, src, response)
 		}
 	}
 }
 
+// finalizeMessage marks the message as finalized, indexes it as delivered by the transaction of res,
+// nil when the delivery was observed on the destination, records it in the relay history and removes
+// it from the cache and the store. The writes are committed with the ones already staged in tx.
+func (r *Relayer) finalizeMessage(tx *storeTx, m *types.RouteMessage, src *ChainRuntime, res *types.TxResponse) {
+	if err := m.SetStatus(types.MessageStatusFinalized); err != nil {
+		r.log.Warn("finalizing message from unexpected state", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+	}
+	var txHash string
+	if res != nil {
+		txHash = res.TxHash
+	}
+	if err := tx.delivered.StoreDelivered(types.NewDeliveredMessage(m.MessageKey(), txHash)); err != nil {
+		r.log.Error("error occured when indexing delivered message", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+	}
+	if err := tx.history.StoreRecord(types.NewRelayRecord(m, res)); err != nil {
+		r.log.Error("error occured when recording delivered message", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+	}
+	if err := tx.messages.DeleteMessage(m.MessageKey()); err != nil {
+		r.log.Error("error occured when deleting message from db ", zap.Error(err))
+	}
+	if err := tx.commit(); err != nil {
+		r.log.Error("error occured when clearing successful message", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+		return
+	}
+	src.clearMessageFromCache([]*types.MessageKey{m.MessageKey()})
+}
+
 func (r *Relayer) RouteMessage(ctx context.Context, m *types.RouteMessage, dst, src *ChainRuntime) {
 	m.IncrementRetry()
+	m.SetNextTry(r.retryPolicy(m).Delay(m.GetRetry()))
+	if err := r.transition(m, types.MessageStatusSubmitted); err != nil {
+		return
+	}
 	if err := dst.Provider.Route(ctx, m.Message, r.callback(ctx, src, dst, m.MessageKey())); err != nil {
 		dst.log.Error("message routing failed", zap.String("src", m.Src), zap.String("event_type", m.EventType), zap.Error(err))
-		r.HandleMessageFailed(m, dst, src)
+		r.HandleMessageFailed(m, dst, src, err)
 	}
 }
 
-func (r *Relayer) HandleMessageFailed(routeMessage *types.RouteMessage, dst, src *ChainRuntime) {
-	routeMessage.ToggleProcessing()
-	if routeMessage.Retry >= types.MaxTxRetry {
-		if err := r.messageStore.StoreMessage(routeMessage); err != nil {
+func (r *Relayer) HandleMessageFailed(routeMessage *types.RouteMessage, dst, src *ChainRuntime, err error) {
+	var txHash string
+	if tx, err := r.pendingTxStore.GetPendingTx(routeMessage.MessageKey()); err == nil {
+		txHash = tx.TxHash
+	}
+	routeMessage.AddAttempt(txHash, err)
+
+	// the pending transaction and the message state are written at once
+	tx := r.newStoreTx()
+	// the failure is known, nothing left to reconcile for this broadcast
+	if err := tx.pendingTxs.DeletePendingTx(routeMessage.MessageKey()); err != nil {
+		r.log.Warn("failed to delete pending transaction", zap.Any("message-key", routeMessage.MessageKey()), zap.Error(err))
+	}
+	if r.retryPolicy(routeMessage).Exhausted(routeMessage.GetRetry()) {
+		if err := r.stageTransition(tx, routeMessage, types.MessageStatusFailed); err != nil {
 			r.log.Error("error occured when storing the message after max retry", zap.Error(err))
 			return
 		}
 
-		// removed message from messageCache
-		src.MessageCache.Remove(routeMessage.MessageKey())
+		// move the message to the dead letter queue for investigation
+		if err := tx.deadLetters.StoreDeadLetter(types.NewDeadLetter(routeMessage)); err != nil {
+			r.log.Error("error occured when storing the dead letter", zap.Error(err))
+			return
+		}
+		if err := tx.messages.DeleteMessage(routeMessage.MessageKey()); err != nil {
+			r.log.Error("error occured when deleting message from db ", zap.Error(err))
+			return
+		}
+		if err := tx.commit(); err != nil {
+			r.log.Error("error occured when moving the message to the dead letter queue", zap.Error(err))
+			return
+		}
+		src.clearMessageFromCache([]*types.MessageKey{routeMessage.MessageKey()})
 
 		dst.log.Error("message relay failed",
 			zap.String("src", routeMessage.Src),
 			zap.String("dst", routeMessage.Dst),
 			zap.Uint64("sn", routeMessage.Sn.Uint64()),
 			zap.String("event_type", routeMessage.EventType),
-			zap.Uint8("count", routeMessage.Retry),
+			zap.Uint8("count", routeMessage.GetRetry()),
+			zap.String("last_error", routeMessage.LastError()),
 		)
+		return
+	}
+	// back to the queue, the next try is scheduled by the backoff
+	if err := r.stageTransition(tx, routeMessage, types.MessageStatusDetected); err != nil {
+		return
+	}
+	if err := tx.commit(); err != nil {
+		r.log.Error("failed to persist message state", zap.Any("message-key", routeMessage.MessageKey()), zap.Error(err))
+		return
+	}
+	r.schedule(src, routeMessage)
+}
+
+// rejectMessage records the message refused by the filter rules and drops it
+func (r *Relayer) rejectMessage(src *ChainRuntime, m *types.RouteMessage, reason string) {
+	tx := r.newStoreTx()
+	if err := tx.rejected.StoreRejected(types.NewRejectedMessage(m.Clone().Message, reason)); err != nil {
+		r.log.Error("error occured when storing the rejected message", zap.Error(err))
+		return
+	}
+	if err := tx.messages.DeleteMessage(m.MessageKey()); err != nil {
+		r.log.Error("error occured when deleting message from db ", zap.Error(err))
+		return
+	}
+	if err := tx.commit(); err != nil {
+		r.log.Error("error occured when clearing rejected message from messages", zap.Error(err))
+		return
+	}
+	src.clearMessageFromCache([]*types.MessageKey{m.MessageKey()})
+	src.log.Info("message rejected",
+		zap.String("src", m.Src),
+		zap.String("dst", m.Dst),
+		zap.Uint64("sn", m.Sn.Uint64()),
+		zap.String("event_type", m.EventType),
+		zap.String("reason", reason),
+	)
+}
+
+// GetRejectedStore returns the rejected message store
+func (r *Relayer) GetRejectedStore() *store.RejectedStore {
+	return r.rejectedStore
+}
+
+// RequeueDeadLetter moves a dead letter back to the message store for a fresh set of retries
+func (r *Relayer) RequeueDeadLetter(key *types.MessageKey) (*types.RouteMessage, error) {
+	src, err := r.FindChainRuntime(key.Src)
+	if err != nil {
+		return nil, err
+	}
+	deadLetter, err := r.deadLetterStore.GetDeadLetter(key)
+	if err != nil {
+		return nil, err
+	}
+	m := deadLetter.RouteMessage
+	if err := m.SetStatus(types.MessageStatusDetected); err != nil {
+		return nil, err
+	}
+	m.ResetRetry()
+	tx := r.newStoreTx()
+	if err := tx.messages.StoreMessage(m); err != nil {
+		return nil, err
+	}
+	if err := tx.deadLetters.DeleteDeadLetter(key); err != nil {
+		return nil, err
+	}
+	if err := tx.commit(); err != nil {
+		return nil, err
+	}
+	r.EnqueueMessage(src, m)
+	r.log.Info("dead letter requeued",
+		zap.String("src", m.Src),
+		zap.String("dst", m.Dst),
+		zap.Uint64("sn", m.Sn.Uint64()),
+		zap.String("event_type", m.EventType),
+	)
+	return m, nil
+}
+
+// RelayStoredMessage routes a stored message right away. Only a message out of the pipeline is
+// relayed again, one waiting for a try or failed or expired, so that a message being delivered is
+// never sent twice, its state is persisted before it is scheduled.
+func (r *Relayer) RelayStoredMessage(key *types.MessageKey) (*types.RouteMessage, error) {
+	src, err := r.FindChainRuntime(key.Src)
+	if err != nil {
+		return nil, err
+	}
+	m, err := r.messageStore.GetMessage(key)
+	if err != nil {
+		return nil, err
+	}
+	// the cached copy carries the live state
+	if cached, ok := src.MessageCache.Get(m.MessageKey()); ok {
+		m = cached
+	}
+	switch status := m.GetStatus(); status {
+	case types.MessageStatusDetected, types.MessageStatusFailed, types.MessageStatusExpired:
+	default:
+		return nil, fmt.Errorf("the message is %s, only a detected, failed or expired message is relayed manually", status)
+	}
+	m.ClearNextTry()
+	if err := r.transition(m, types.MessageStatusDetected); err != nil {
+		return nil, err
+	}
+	r.EnqueueMessage(src, m)
+	return m.Clone(), nil
+}
+
+// PurgeDeadLetters deletes the dead letters of the chain, the single one of the event type
+// when sn is given, the event type is only needed when several event types have the sn
+func (r *Relayer) PurgeDeadLetters(nId, eventType string, sn *big.Int) (int, error) {
+	if sn != nil {
+		key := &types.MessageKey{Src: nId, Sn: sn, EventType: eventType}
+		if _, err := r.deadLetterStore.GetDeadLetter(key); err != nil {
+			return 0, err
+		}
+		if err := r.deadLetterStore.DeleteDeadLetter(key); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+	deadLetters, err := r.deadLetterStore.GetDeadLetters(nId, store.NewPagination().GetAll())
+	if err != nil {
+		return 0, err
+	}
+	for i, m := range deadLetters {
+		if err := r.deadLetterStore.DeleteDeadLetter(m.MessageKey()); err != nil {
+			return i, err
+		}
+	}
+	return len(deadLetters), nil
+}
+
+// reconcilePendingTxs settles the transactions broadcast before the last shutdown
+// against the destination chains before any of their messages is routed again
+func (r *Relayer) reconcilePendingTxs(ctx context.Context) {
+	for _, dst := range r.chainRuntimes() {
+		pendingTxs, err := r.pendingTxStore.GetPendingTxs(dst.Provider.NID())
+		if err != nil {
+			dst.log.Warn("error occured when query pending transactions", zap.Error(err))
+			continue
+		}
+		if len(pendingTxs) > 0 {
+			dst.log.Info("reconciling pending transactions", zap.Int("count", len(pendingTxs)))
+		}
+		for _, tx := range pendingTxs {
+			r.reconcilePendingTx(ctx, dst, tx)
+		}
+	}
+}
+
+// reconcilePendingTx looks up the receipt of a pending transaction and either
+// confirms its message or puts it back to be routed again
+func (r *Relayer) reconcilePendingTx(ctx context.Context, dst *ChainRuntime, tx *types.PendingTransaction) {
+	src, ok := r.chain(tx.Src)
+	if !ok {
+		dst.log.Warn("source chain not found for pending transaction", zap.String("src", tx.Src), zap.String("tx_hash", tx.TxHash))
+		return
+	}
+	m, err := r.messageStore.GetMessage(tx.MessageKey)
+	if err != nil || m.GetStatus() != types.MessageStatusSubmitted {
+		// the message was settled already, the record is leftover
+		r.deletePendingTx(tx.MessageKey)
+		return
+	}
+
+	receipt, err := dst.Provider.QueryTransactionReceipt(ctx, tx.TxHash)
+	if err != nil {
+		dst.log.Warn("failed to query pending transaction receipt", zap.String("tx_hash", tx.TxHash), zap.Error(err))
+	}
+
+	if receipt == nil || !receipt.Status {
+		// the transaction failed or never landed, make sure the message was not
+		// delivered by other means before it is sent again
+		received, err := dst.Provider.MessageReceived(ctx, tx.MessageKey)
+		if err != nil {
+			dst.log.Warn("unable to reconcile pending transaction, holding the message",
+				zap.String("src", tx.Src),
+				zap.Uint64("sn", tx.Sn.Uint64()),
+				zap.String("tx_hash", tx.TxHash),
+				zap.Error(err),
+			)
+			return
+		}
+		stx := r.newStoreTx()
+		if err := stx.pendingTxs.DeletePendingTx(tx.MessageKey); err != nil {
+			r.log.Warn("failed to delete pending transaction", zap.Any("message-key", tx.MessageKey), zap.Error(err))
+		}
+		if received {
+			dst.log.Info("message already received", zap.String("src", tx.Src), zap.Uint64("sn", tx.Sn.Uint64()))
+			if err := r.stageTransition(stx, m, types.MessageStatusConfirmed); err != nil {
+				return
+			}
+			r.finalizeMessage(stx, m, src, nil)
+			return
+		}
+		dst.log.Info("pending transaction not confirmed, requeueing message",
+			zap.String("src", tx.Src),
+			zap.Uint64("sn", tx.Sn.Uint64()),
+			zap.String("tx_hash", tx.TxHash),
+			zap.Uint64("nonce", tx.Nonce),
+		)
+		if err := r.stageTransition(stx, m, types.MessageStatusDetected); err != nil {
+			return
+		}
+		if err := stx.commit(); err != nil {
+			r.log.Error("failed to persist message state", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+			return
+		}
+		r.EnqueueMessage(src, m)
+		return
+	}
+
+	dst.log.Info("pending transaction confirmed",
+		zap.String("src", tx.Src),
+		zap.Uint64("sn", tx.Sn.Uint64()),
+		zap.String("tx_hash", tx.TxHash),
+		zap.Uint64("height", receipt.Height),
+	)
+	// the pending transaction, the finality record and the message state are written at once
+	stx := r.newStoreTx()
+	finality := dst.Provider.FinalityBlock(ctx) > 0
+	if finality {
+		txObj := types.NewTransactionObject(tx.MessageKeyWithMessageHeight, tx.TxHash, receipt.Height)
+		if err := stx.finality.StoreTxObject(txObj); err != nil {
+			r.log.Error("error occured: while storing transaction object in db", zap.Error(err))
+			return
+		}
+	}
+	if err := stx.pendingTxs.DeletePendingTx(tx.MessageKey); err != nil {
+		r.log.Warn("failed to delete pending transaction", zap.Any("message-key", tx.MessageKey), zap.Error(err))
+	}
+	if err := r.stageTransition(stx, m, types.MessageStatusConfirmed); err != nil {
+		return
+	}
+	if !finality {
+		r.finalizeMessage(stx, m, src, &types.TxResponse{TxHash: tx.TxHash, Height: int64(receipt.Height)})
+		return
+	}
+	if err := stx.commit(); err != nil {
+		r.log.Error("error occured: while storing confirmed message in db", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+	}
+}
+
+func (r *Relayer) deletePendingTx(key *types.MessageKey) {
+	if err := r.pendingTxStore.DeletePendingTx(key); err != nil {
+		r.log.Warn("failed to delete pending transaction", zap.Any("message-key", key), zap.Error(err))
 	}
-	return
 }
 
 // PruneDB removes all the messages from db
@@ -364,20 +1029,29 @@ func (r *Relayer) ClearMessages(ctx context.Context, msgs []*types.MessageKey, s
 	// clear from cache
 	srcChain.clearMessageFromCache(msgs)
 
+	// the messages are deleted at once
+	tx := r.newStoreTx()
 	for _, m := range msgs {
-		if err := r.messageStore.DeleteMessage(m); err != nil {
+		if err := tx.messages.DeleteMessage(m); err != nil {
 			r.log.Error("error occured when deleting message from db ", zap.Error(err))
 			return err
 		}
 	}
+	if err := tx.commit(); err != nil {
+		r.log.Error("error occured when deleting message from db ", zap.Error(err))
+		return err
+	}
 	return nil
 }
 
 func (r *Relayer) StartFinalityProcessor(ctx context.Context) {
 	ticker := time.NewTicker(FinalityInterval)
+	defer ticker.Stop()
 
 	for {
 		select {
+		case <-ctx.Done():
+			return
 		case <-ticker.C:
 			r.CheckFinality(ctx)
 		}
@@ -385,10 +1059,10 @@ func (r *Relayer) StartFinalityProcessor(ctx context.Context) {
 }
 
 func (r *Relayer) CheckFinality(ctx context.Context) {
-	for nid, c := range r.chains {
+	for nid, c := range r.chainRuntimes() {
 		// check for the finality only if finalityblock is provided by the chain
 		finalityBlock := c.Provider.FinalityBlock(ctx)
-		latestHeight := c.LastBlockHeight
+		latestHeight := c.LastBlockHeight()
 		if finalityBlock > 0 {
 			pagination := store.NewPagination().WithLimit(10)
 			txObjects, err := r.finalityStore.GetTxObjects(nid, pagination)
@@ -428,12 +1102,24 @@ func (r *Relayer) CheckFinality(ctx context.Context) {
 
 				// Transaction Still exist so can be pruned
 				if receipt.Status {
-					if err := r.finalityStore.DeleteTxObject(txObject.MessageKey); err != nil {
+					// the finality record and the message are settled at once
+					tx := r.newStoreTx()
+					if err := tx.finality.DeleteTxObject(txObject.MessageKey); err != nil {
 						r.log.Error("finality processor: deleteTxObject ",
 							zap.Any("message key", txObject.MessageKey),
 							zap.Error(err))
 					}
 					r.log.Debug("finality processor: transaction still exist after finalized block, deleting txObject")
+					srcChainRuntime, ok := r.chain(txObject.Src)
+					if !ok {
+						if err := tx.commit(); err != nil {
+							r.log.Error("finality processor: deleteTxObject ",
+								zap.Any("message key", txObject.MessageKey),
+								zap.Error(err))
+						}
+						continue
+					}
+					r.finalizeConfirmedMessage(tx, txObject, srcChainRuntime)
 					continue
 				}
 
@@ -442,7 +1128,7 @@ func (r *Relayer) CheckFinality(ctx context.Context) {
 					zap.String("tx hash on destination chain", txObject.TxHash))
 
 				// if receipt donot exist generate message again and send to src chain
-				srcChainRuntime, ok := r.chains[txObject.Src]
+				srcChainRuntime, ok := r.chain(txObject.Src)
 				if !ok {
 					r.log.Error("finality processor:  ",
 						zap.Any("message key", txObject.MessageKey),
@@ -450,15 +1136,7 @@ func (r *Relayer) CheckFinality(ctx context.Context) {
 					continue
 				}
 
-				// removing tx object
-				if err := r.finalityStore.DeleteTxObject(txObject.MessageKey); err != nil {
-					r.log.Error("finality processor: deleteTxObject ",
-						zap.Any("message key", txObject.MessageKey),
-						zap.Error(err))
-					continue
-				}
-
-				// generateMessage
+				// generateMessage, the tx object is kept to retry when it fails
 				messages, err := srcChainRuntime.Provider.GenerateMessages(ctx, txObject.MessageKeyWithMessageHeight)
 				if err != nil {
 					r.log.Error("finality processor: generateMessage",
@@ -468,18 +1146,64 @@ func (r *Relayer) CheckFinality(ctx context.Context) {
 					continue
 				}
 
+				// the regenerated block may hold messages that are delivered already
+				messages = r.undeliveredMessages(messages)
+
+				// removing tx object, at once with the regenerated messages
+				tx := r.newStoreTx()
+				if err := tx.finality.DeleteTxObject(txObject.MessageKey); err != nil {
+					r.log.Error("finality processor: deleteTxObject ",
+						zap.Any("message key", txObject.MessageKey),
+						zap.Error(err))
+					continue
+				}
+				var regenerated []*types.RouteMessage
+				for _, m := range srcChainRuntime.mergeMessages(ctx, messages) {
+					if err := r.stageTransition(tx, m, types.MessageStatusDetected); err == nil {
+						regenerated = append(regenerated, m)
+					}
+				}
+				if err := tx.commit(); err != nil {
+					r.log.Error("finality processor: deleteTxObject ",
+						zap.Any("message key", txObject.MessageKey),
+						zap.Error(err))
+					continue
+				}
+
 				// merging message to srcChainRuntime
-				srcChainRuntime.mergeMessages(ctx, messages)
+				for _, m := range regenerated {
+					r.schedule(srcChainRuntime, m)
+				}
 			}
 		}
 	}
 }
 
+// finalizeConfirmedMessage settles a confirmed message whose destination transaction reached finality,
+// the writes are committed with the ones already staged in tx
+func (r *Relayer) finalizeConfirmedMessage(tx *storeTx, txObject *types.TransactionObject, src *ChainRuntime) {
+	key := txObject.MessageKey
+	m, err := r.messageStore.GetMessage(key)
+	if err != nil || m.GetStatus() != types.MessageStatusConfirmed {
+		// messages delivered before the lifecycle was persisted are already gone
+		if err := tx.commit(); err != nil {
+			r.log.Error("finality processor: deleteTxObject ", zap.Any("message key", key), zap.Error(err))
+		}
+		return
+	}
+	r.finalizeMessage(tx, m, src, &types.TxResponse{
+		TxHash:  txObject.TxHash,
+		Height:  int64(txObject.TxHeight),
+		GasUsed: txObject.GasUsed,
+		Fee:     txObject.Fee,
+	})
+}
+
 // SaveBlockHeight for all chains
 func (r *Relayer) SaveChainsBlockHeight(ctx context.Context) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	for nid, chain := range r.chains {
+	for nid, chain := range r.chainRuntimes() {
 		height, err := chain.Provider.QueryLatestHeight(ctx)
 		if err != nil {
 			r.log.Error("error occured when querying latest height", zap.String("nid", nid), zap.Error(err))
@@ -492,22 +1216,61 @@ func (r *Relayer) SaveChainsBlockHeight(ctx context.Context) {
 	}
 }
 
-// cleanExpiredMessages
+// cleanExpiredMessages walks every stored message and expires the ones outside the pipeline
+// that outlived the expiration window
 func (r *Relayer) cleanExpiredMessages(ctx context.Context) {
-	for nid, chain := range r.chains {
-		p := store.NewPagination().WithLimit(maxFlushMessage)
-		messages, err := r.messageStore.GetMessages(nid, p)
-		if err != nil {
-			r.log.Error("error occured when fetching messages from db", zap.Error(err))
-			continue
-		}
-
-		for _, m := range messages {
-			if m.IsElasped(MessageExpiration) {
-				if err := r.ClearMessages(ctx, []*types.MessageKey{m.MessageKey()}, chain); err != nil {
-					r.log.Error("error occured when clearing expired message", zap.Error(err))
+	for nid, chain := range r.chainRuntimes() {
+		filter := &store.MessageFilter{Src: nid}
+		p := store.NewPagination().WithLimit(store.DefaultPageSize)
+		for ctx.Err() == nil {
+			messages, next, err := r.messageStore.ListMessages(filter, p)
+			if err != nil {
+				r.log.Error("error occured when fetching messages from db", zap.Error(err))
+				break
+			}
+			for _, m := range messages {
+				if m.IsProcessing() || !m.IsElasped(MessageExpiration) {
+					continue
 				}
+				r.expireMessage(chain, m)
+			}
+			if next == nil {
+				break
 			}
+			p = store.NewPagination().WithLimit(store.DefaultPageSize).WithCursor(next)
 		}
 	}
 }
+
+// expireMessage moves the message to the dead letter queue in the expired state, it is
+// removed from the store at once and can be requeued from there
+func (r *Relayer) expireMessage(src *ChainRuntime, m *types.RouteMessage) {
+	if err := m.SetStatus(types.MessageStatusExpired); err != nil {
+		r.log.Warn("expiring message from unexpected state", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+		return
+	}
+	deadLetter := types.NewDeadLetter(m)
+	if deadLetter.LastError == "" {
+		deadLetter.LastError = "message expired"
+	}
+	tx := r.newStoreTx()
+	if err := tx.deadLetters.StoreDeadLetter(deadLetter); err != nil {
+		r.log.Error("error occured when storing the dead letter", zap.Error(err))
+		return
+	}
+	if err := tx.messages.DeleteMessage(m.MessageKey()); err != nil {
+		r.log.Error("error occured when deleting message from db ", zap.Error(err))
+		return
+	}
+	if err := tx.commit(); err != nil {
+		r.log.Error("error occured when clearing expired message", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+		return
+	}
+	src.clearMessageFromCache([]*types.MessageKey{m.MessageKey()})
+	src.log.Info("message expired",
+		zap.String("src", m.Src),
+		zap.String("dst", m.Dst),
+		zap.Uint64("sn", m.Sn.Uint64()),
+		zap.String("event_type", m.EventType),
+	)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/icon-project/centralized-relay/relayer/chains/mockchain"
 	"github.com/icon-project/centralized-relay/relayer/lvldb"
 	"github.com/icon-project/centralized-relay/relayer/provider"
+	"github.com/icon-project/centralized-relay/relayer/store"
 	"github.com/icon-project/centralized-relay/relayer/types"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
@@ -110,7 +111,8 @@ func (s *RelayTestSuite) TestRelay() {
 
 	chains[mock2Nid] = NewChain(logger, mock2Provider, true)
 
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	rly, err := NewRelayer(logger, s.db, chains, true)
 	if err != nil {
 		s.Fail("unable to start the relayer ", err)
@@ -130,7 +132,7 @@ loop:
 		case err := <-errorchan:
 			s.Fail("error occured when starting the relay", err)
 		case <-receivedTimer.C:
-			if len(provider1.PCfg.ReceiveMessages) == 0 && len(provider2.PCfg.ReceiveMessages) == 0 {
+			if provider1.PendingReceiveCount() == 0 && provider2.PendingReceiveCount() == 0 {
 				break loop
 			}
 		case <-failedReceived.C:
@@ -144,3 +146,1229 @@ loop:
 		s.db.RemoveDbFile(levelDbName)
 	})
 }
+
+func (s *RelayTestSuite) TestReconcilePendingTxs() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	chains := make(map[string]*Chain, 0)
+	mock1Nid := "mock-1"
+	mock2Nid := "mock-2"
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+
+	m := types.NewRouteMessage(&types.Message{
+		Src:       mock1Nid,
+		Dst:       mock2Nid,
+		Sn:        big.NewInt(1),
+		EventType: "emitMessage",
+	})
+	s.Require().NoError(m.SetStatus(types.MessageStatusQueued))
+	s.Require().NoError(m.SetStatus(types.MessageStatusSubmitted))
+	s.Require().NoError(rly.messageStore.StoreMessage(m))
+
+	tx := types.NewPendingTransaction(types.NewMessagekeyWithMessageHeight(m.MessageKey(), m.MessageHeight), "0x1", 1)
+	s.Require().NoError(rly.pendingTxStore.StorePendingTx(tx))
+
+	// mock chain has no receipt and the message is not received, so it is routed again
+	rly.reconcilePendingTxs(context.Background())
+
+	_, err = rly.pendingTxStore.GetPendingTx(m.MessageKey())
+	s.Error(err)
+
+	stored, err := rly.messageStore.GetMessage(m.MessageKey())
+	s.Require().NoError(err)
+	s.Equal(types.MessageStatusDetected, stored.GetStatus())
+
+	s.Equal(1, rly.chains[mock1Nid].MessageCache.Len())
+}
+
+func getStressMockMessages(srcNId, dstNId string, srcStartHeight uint64, count int) map[types.MessageKey]*types.Message {
+	messages := make(map[types.MessageKey]*types.Message, count)
+	for i := 1; i <= count; i++ {
+		m := &types.Message{
+			Src:           srcNId,
+			Dst:           dstNId,
+			Data:          []byte(fmt.Sprintf("from message %s", srcNId)),
+			MessageHeight: srcStartHeight + uint64(i%3) + 1,
+			Sn:            big.NewInt(int64(i)),
+			EventType:     "emitMessage",
+		}
+		messages[*m.MessageKey()] = m
+	}
+	return messages
+}
+
+// TestRelayStress routes many messages between the mock chains while the cache is
+// swept, flushed and ranged over from other goroutines, run it with -race
+func (s *RelayTestSuite) TestRelayStress() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	const count = 200
+	mock1Nid := "mock-1"
+	mock2Nid := "mock-2"
+
+	newProvider := func(nId, dstNId string, startHeight, dstStartHeight uint64) *mockchain.MockProvider {
+		cfg := mockchain.MockProviderConfig{
+			NId:             nId,
+			StartHeight:     startHeight,
+			SendMessages:    getStressMockMessages(nId, dstNId, startHeight, count),
+			ReceiveMessages: getStressMockMessages(dstNId, nId, dstStartHeight, count),
+		}
+		p, err := cfg.NewProvider(context.Background(), zap.NewNop(), "empty", false, nId)
+		s.Require().NoError(err)
+		return p.(*mockchain.MockProvider)
+	}
+	provider1 := newProvider(mock1Nid, mock2Nid, 10, 20)
+	provider2 := newProvider(mock2Nid, mock1Nid, 20, 10)
+
+	chains := map[string]*Chain{
+		mock1Nid: NewChain(zap.NewNop(), provider1, true),
+		mock2Nid: NewChain(zap.NewNop(), provider2, true),
+	}
+	rly, err := NewRelayer(zap.NewNop(), s.db, chains, true)
+	s.Require().NoError(err)
+	rly.SetConfig(&Config{Scheduler: &SchedulerConfig{
+		Workers: 8,
+		Destinations: map[string]*DestinationConfig{
+			mock1Nid: {StrictOrdering: []string{mock2Nid}},
+		},
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errorchan, err := rly.Start(ctx, 100*time.Millisecond, true)
+	s.Require().NoError(err)
+
+	// hammer the caches while the messages are routed
+	for i := 0; i < 4; i++ {
+		go func() {
+			ticker := time.NewTicker(10 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					rly.sweepMessages()
+					rly.flushMessages(ctx)
+					for _, c := range rly.chains {
+						c.MessageCache.Range(func(m *types.RouteMessage) bool {
+							m.IsProcessing()
+							m.Clone()
+							return true
+						})
+					}
+				}
+			}
+		}()
+	}
+
+	timeout := time.After(60 * time.Second)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-errorchan:
+			s.Fail("error occured when starting the relay", err)
+			return
+		case <-ticker.C:
+			if provider1.PendingReceiveCount() == 0 && provider2.PendingReceiveCount() == 0 {
+				return
+			}
+		case <-timeout:
+			s.Fail("failed to receive all the messages",
+				"pending %d %d", provider1.PendingReceiveCount(), provider2.PendingReceiveCount())
+			return
+		}
+	}
+}
+
+func (s *RelayTestSuite) TestProcessBlockInfoDedup() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	chains := make(map[string]*Chain, 0)
+	mock1Nid := "mock-1"
+	mock2Nid := "mock-2"
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+
+	delivered := &types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(1), EventType: "emitMessage", MessageHeight: 11}
+	fresh := &types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(2), EventType: "emitMessage", MessageHeight: 11}
+	s.Require().NoError(rly.deliveredStore.StoreDelivered(types.NewDeliveredMessage(delivered.MessageKey(), "0x1")))
+
+	src := rly.chains[mock1Nid]
+	blockInfo := &types.BlockInfo{Height: 11, Messages: []*types.Message{delivered, fresh}}
+	rly.processBlockInfo(context.Background(), src, blockInfo)
+
+	s.Equal(1, src.MessageCache.Len())
+	_, ok := src.MessageCache.Get(fresh.MessageKey())
+	s.True(ok)
+
+	// the listener emits the block again after a reconnect
+	m, _ := src.MessageCache.Get(fresh.MessageKey())
+	s.Require().NoError(m.SetStatus(types.MessageStatusQueued))
+	rly.processBlockInfo(context.Background(), src, blockInfo)
+
+	s.Equal(1, src.MessageCache.Len())
+	cached, _ := src.MessageCache.Get(fresh.MessageKey())
+	s.Equal(types.MessageStatusQueued, cached.GetStatus())
+	s.Equal(1, rly.queues[mock2Nid].Len())
+
+	// finalizing indexes the message as delivered
+	rly.finalizeMessage(rly.newStoreTx(), cached, src, &types.TxResponse{TxHash: "0x2"})
+	got, err := rly.deliveredStore.GetDelivered(fresh.MessageKey())
+	s.Require().NoError(err)
+	s.Equal("0x2", got.TxHash)
+}
+
+func (s *RelayTestSuite) TestFlushAndExpireMessages() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	chains := make(map[string]*Chain, 0)
+	mock1Nid := "mock-1"
+	mock2Nid := "mock-2"
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	src := rly.chains[mock1Nid]
+
+	// more messages waiting for the destination finality than a flush takes
+	for sn := int64(1); sn <= int64(maxFlushMessage)+2; sn++ {
+		m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(sn), EventType: "emitMessage"})
+		m.Status = types.MessageStatusConfirmed
+		s.Require().NoError(rly.messageStore.StoreMessage(m))
+	}
+	detected := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(100), EventType: "emitMessage"})
+	s.Require().NoError(rly.messageStore.StoreMessage(detected))
+
+	active, err := rly.getActiveMessagesFromStore(src, maxFlushMessage)
+	s.Require().NoError(err)
+	s.Require().Len(active, 1)
+	s.Equal(int64(100), active[0].Sn.Int64())
+
+	// the message outlived the expiration window
+	detected.LastTry = time.Now().Add(-2 * MessageExpiration)
+	s.Require().NoError(rly.messageStore.StoreMessage(detected))
+	rly.cleanExpiredMessages(context.Background())
+
+	_, err = rly.messageStore.GetMessage(detected.MessageKey())
+	s.ErrorIs(err, store.ErrNotFound)
+	deadLetter, err := rly.deadLetterStore.GetDeadLetter(detected.MessageKey())
+	s.Require().NoError(err)
+	s.Equal(types.MessageStatusExpired, deadLetter.GetStatus())
+	count, err := rly.messageStore.TotalCountByChain(mock1Nid)
+	s.Require().NoError(err)
+	s.Equal(maxFlushMessage+2, count)
+
+	requeued, err := rly.RequeueDeadLetter(detected.MessageKey())
+	s.Require().NoError(err)
+	s.Equal(types.MessageStatusDetected, requeued.GetStatus())
+
+	// a sn of two dead letters is only purged along with its event type
+	for _, eventType := range []string{"emitMessage", "callMessage"} {
+		m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(200), EventType: eventType})
+		s.Require().NoError(rly.deadLetterStore.StoreDeadLetter(types.NewDeadLetter(m)))
+	}
+	_, err = rly.PurgeDeadLetters(mock1Nid, "", big.NewInt(200))
+	s.ErrorIs(err, store.ErrAmbiguousKey)
+	_, err = rly.RequeueDeadLetter(&types.MessageKey{Src: mock1Nid, Sn: big.NewInt(200)})
+	s.ErrorIs(err, store.ErrAmbiguousKey)
+	purged, err := rly.PurgeDeadLetters(mock1Nid, "callMessage", big.NewInt(200))
+	s.Require().NoError(err)
+	s.Equal(1, purged)
+	deadLetter, err = rly.deadLetterStore.GetDeadLetter(types.NewMessageKey(big.NewInt(200), mock1Nid, mock2Nid, "emitMessage"))
+	s.Require().NoError(err)
+	s.Equal("emitMessage", deadLetter.EventType)
+}
+
+func (s *RelayTestSuite) TestRelayStoredMessage() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	chains := make(map[string]*Chain, 0)
+	mock1Nid := "mock-1"
+	mock2Nid := "mock-2"
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	src := rly.chains[mock1Nid]
+
+	// a message in flight is not sent again
+	inFlight := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(1), EventType: "emitMessage"})
+	inFlight.Status = types.MessageStatusSubmitted
+	s.Require().NoError(rly.messageStore.StoreMessage(inFlight))
+	src.MessageCache.Add(inFlight)
+	_, err = rly.RelayStoredMessage(inFlight.MessageKey())
+	s.ErrorContains(err, "submitted")
+	s.Equal(0, rly.queues[mock2Nid].Len())
+
+	// the cached copy is checked rather than the stored one
+	stale := inFlight.Clone()
+	stale.Status = types.MessageStatusFailed
+	s.Require().NoError(rly.messageStore.StoreMessage(stale))
+	_, err = rly.RelayStoredMessage(inFlight.MessageKey())
+	s.Error(err)
+
+	// a failed message restarts its lifecycle, persisted
+	failed := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(2), EventType: "emitMessage"})
+	failed.Status = types.MessageStatusFailed
+	failed.SetNextTry(time.Hour)
+	s.Require().NoError(rly.messageStore.StoreMessage(failed))
+	relayed, err := rly.RelayStoredMessage(&types.MessageKey{Src: mock1Nid, Sn: big.NewInt(2)})
+	s.Require().NoError(err)
+	s.Equal(types.MessageStatusDetected, relayed.GetStatus())
+	stored, err := rly.messageStore.GetMessage(failed.MessageKey())
+	s.Require().NoError(err)
+	s.Equal(types.MessageStatusDetected, stored.GetStatus())
+	s.False(stored.LastTry.After(time.Now()))
+	s.Equal(1, rly.queues[mock2Nid].Len())
+}
+
+func (s *RelayTestSuite) TestReorgDetection() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	ctx := context.Background()
+	chains := make(map[string]*Chain, 0)
+	mock1Nid := "mock-1"
+	mock2Nid := "mock-2"
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+
+	provider1 := mock1Provider.(*mockchain.MockProvider)
+	src := rly.chains[mock1Nid]
+
+	// sn 1 is emitted at height 13, the ghost message only exists in the reorged block
+	canonical, err := provider1.GenerateMessages(ctx, types.NewMessagekeyWithMessageHeight(&types.MessageKey{Src: mock1Nid}, 13))
+	s.Require().NoError(err)
+	s.Require().Len(canonical, 1)
+	ghost := &types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(100), EventType: "emitMessage", MessageHeight: 13}
+
+	hash, err := provider1.QueryBlockHash(ctx, 13)
+	s.Require().NoError(err)
+	rly.processBlockInfo(ctx, src, &types.BlockInfo{Height: 13, Hash: hash, Messages: append(canonical, ghost)})
+	s.Equal(2, src.MessageCache.Len())
+
+	// the block is still canonical
+	rly.checkReorgs(ctx, src)
+	s.Equal(2, src.MessageCache.Len())
+
+	provider1.SetBlockHash(13, "0xreorged")
+	rly.checkReorgs(ctx, src)
+
+	_, ok := src.MessageCache.Get(ghost.MessageKey())
+	s.False(ok)
+	_, err = rly.messageStore.GetMessage(ghost.MessageKey())
+	s.Error(err)
+	_, ok = src.MessageCache.Get(canonical[0].MessageKey())
+	s.True(ok)
+
+	record, err := rly.blockRecordStore.GetBlockRecord(mock1Nid, 13)
+	s.Require().NoError(err)
+	s.Equal("0xreorged", record.Hash)
+	s.Len(record.Messages, 1)
+
+	// the listener emits the block again with yet another hash
+	rly.processBlockInfo(ctx, src, &types.BlockInfo{Height: 13, Hash: "0xagain", Messages: []*types.Message{ghost}})
+	_, ok = src.MessageCache.Get(canonical[0].MessageKey())
+	s.False(ok)
+	_, ok = src.MessageCache.Get(ghost.MessageKey())
+	s.True(ok)
+}
+
+func (s *RelayTestSuite) TestSourceFinalityGating() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	ttl := SourceHeightTTL
+	SourceHeightTTL = 0
+	defer func() { SourceHeightTTL = ttl }()
+
+	ctx := context.Background()
+	chains := make(map[string]*Chain, 0)
+	mock1Nid := "mock-1"
+	mock2Nid := "mock-2"
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	rly.SetConfig(&Config{Scheduler: &SchedulerConfig{
+		Sources: map[string]*SourceConfig{mock1Nid: {Confirmations: 5}},
+	}})
+
+	src, dst := rly.chains[mock1Nid], rly.chains[mock2Nid]
+	m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(1), EventType: "emitMessage", MessageHeight: 13})
+	src.MessageCache.Add(m)
+	s.Require().NoError(rly.messageStore.StoreMessage(m))
+
+	// the mock chain is at height 10, the message needs height 18
+	rly.processMessage(ctx, src, dst, m)
+	s.Equal(types.MessageStatusDetected, m.GetStatus())
+	s.True(m.IsProcessing())
+	s.Equal(uint8(0), m.GetRetry())
+	s.Equal(1, rly.queues[mock2Nid].Len())
+
+	mock1Provider.(*mockchain.MockProvider).Height = 18
+	m.ClearNextTry()
+	rly.processMessage(ctx, src, dst, m)
+	s.True(rly.deliveredStore.IsDelivered(m.MessageKey()))
+
+	// the delivery is kept in the relay history
+	records, _, err := rly.historyStore.ListRecords(&store.HistoryFilter{Src: mock1Nid, Sn: big.NewInt(1)}, store.NewPagination().GetAll())
+	s.Require().NoError(err)
+	s.Require().Len(records, 1)
+	s.Equal(mock2Nid, records[0].Dst)
+	s.Equal("mock-1-1", records[0].DstTxHash)
+	s.Equal(uint64(13), records[0].SrcHeight)
+	s.Equal(uint8(1), records[0].Retry)
+}
+
+func (s *RelayTestSuite) TestShutdown() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 200*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 200*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+
+	_, err = rly.Start(context.Background(), time.Second, true)
+	s.Require().NoError(err)
+
+	provider2 := mock2Provider.(*mockchain.MockProvider)
+	s.Require().Eventually(func() bool {
+		return provider2.PendingReceiveCount() == 0
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// a transaction still waiting for its receipt is reported
+	m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(99), EventType: "emitMessage"})
+	tx := types.NewPendingTransaction(types.NewMessagekeyWithMessageHeight(m.MessageKey(), m.MessageHeight), "0x99", 1)
+	s.Require().NoError(rly.pendingTxStore.StorePendingTx(tx))
+
+	report := rly.Shutdown(5 * time.Second)
+	s.True(report.Drained)
+	s.Equal(map[string]uint64{mock2Nid: 1}, report.PendingTxs)
+	s.Equal(1, report.Pending())
+
+	// the height saved is the last one processed, not the latest of the chain
+	height, err := rly.blockStore.GetLastStoredBlock(mock1Nid)
+	s.Require().NoError(err)
+	s.GreaterOrEqual(height, uint64(10))
+	s.Equal(rly.chains[mock1Nid].LastBlockHeight(), height)
+}
+
+func (s *RelayTestSuite) TestListenerSupervisor() {
+	restartPolicy := ListenerRestartPolicy
+	ListenerRestartPolicy = &RetryPolicy{BaseDelay: time.Second, RetryForever: boolPtr(true)}
+	s.T().Cleanup(func() {
+		ListenerRestartPolicy = restartPolicy
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 200*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 200*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	// the first two runs of the mock-2 listener fail
+	provider1 := mock1Provider.(*mockchain.MockProvider)
+	provider2 := mock2Provider.(*mockchain.MockProvider)
+	provider2.FailListener(fmt.Errorf("rpc unavailable"), fmt.Errorf("rpc unavailable"))
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh, err := rly.Start(ctx, time.Second, true)
+	s.Require().NoError(err)
+
+	mock2, err := rly.FindChainRuntime(mock2Nid)
+	s.Require().NoError(err)
+	s.Require().Eventually(mock2.IsDegraded, 5*time.Second, 10*time.Millisecond)
+	s.Equal("rpc unavailable", mock2.Status().LastError)
+
+	// mock-1 keeps relaying to mock-2 while its listener is down
+	s.Require().Eventually(func() bool {
+		return provider2.PendingReceiveCount() == 0
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// mock-2 recovers after the restarts and its messages are relayed too
+	s.Require().Eventually(func() bool {
+		return provider1.PendingReceiveCount() == 0
+	}, 30*time.Second, 100*time.Millisecond)
+
+	statuses, err := rly.GetChainStatus("")
+	s.Require().NoError(err)
+	s.Require().Len(statuses, 2)
+	s.Equal(mock1Nid, statuses[0].Nid)
+	s.Equal(ChainStateHealthy, statuses[0].State)
+	s.Equal(0, statuses[0].Restarts)
+	s.Equal(mock2Nid, statuses[1].Nid)
+	s.Equal(ChainStateHealthy, statuses[1].State)
+	s.Equal(2, statuses[1].Restarts)
+	s.Empty(statuses[1].LastError)
+
+	// the goroutines of the failed runs exited with them, only the running listener has one
+	s.Eventually(func() bool { return provider2.Watchers() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	select {
+	case err := <-errCh:
+		s.Fail("the relayer stopped on a listener failure", err)
+	default:
+	}
+}
+
+func (s *RelayTestSuite) TestReloadChains() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 200*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	mock1 := NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 200*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	mock2 := NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, map[string]*Chain{mock1Nid: mock1, mock2Nid: mock2}, true)
+	s.Require().NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = rly.Start(ctx, time.Second, true)
+	s.Require().NoError(err)
+
+	// disabling mock-2 stops it
+	report, err := rly.ReloadChains(ctx, map[string]*Chain{mock1Nid: mock1})
+	s.Require().NoError(err)
+	s.Equal([]string{mock2Nid}, report.Removed)
+	_, err = rly.FindChainRuntime(mock2Nid)
+	s.Error(err)
+
+	// the messages to mock-2 are held meanwhile
+	src, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+	s.Require().Eventually(func() bool {
+		return src.MessageCache.Len() == 3
+	}, 15*time.Second, 100*time.Millisecond)
+	provider2 := mock2Provider.(*mockchain.MockProvider)
+	s.Equal(3, provider2.PendingReceiveCount())
+
+	// mock-2 comes back and mock-1 is re-parameterized
+	updatedProvider, err := GetMockChainProvider(s.logger, 100*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	updated := NewChain(s.logger, updatedProvider, true)
+	report, err = rly.ReloadChains(ctx, map[string]*Chain{mock1Nid: updated, mock2Nid: mock2})
+	s.Require().NoError(err)
+	s.Equal([]string{mock2Nid}, report.Added)
+	s.Equal([]string{mock1Nid}, report.Updated)
+	s.Empty(report.Removed)
+
+	reloaded, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+	s.NotSame(src, reloaded)
+	s.Same(src.MessageCache, reloaded.MessageCache)
+	s.Same(updatedProvider, reloaded.Provider)
+	// the replaced provider is released
+	s.True(mock1Provider.(*mockchain.MockProvider).Closed())
+	s.False(updatedProvider.(*mockchain.MockProvider).Closed())
+
+	s.Require().Eventually(func() bool {
+		return provider2.PendingReceiveCount() == 0 &&
+			updatedProvider.(*mockchain.MockProvider).PendingReceiveCount() == 0
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// nothing changes when the config is the same, the provider loaded again is released
+	loadedCfg := *updatedProvider.(*mockchain.MockProvider).PCfg
+	loadedProvider, err := loadedCfg.NewProvider(ctx, s.logger, "empty", false, mock1Nid)
+	s.Require().NoError(err)
+	report, err = rly.ReloadChains(ctx, map[string]*Chain{mock1Nid: NewChain(s.logger, loadedProvider, true), mock2Nid: mock2})
+	s.Require().NoError(err)
+	s.Empty(report.Added)
+	s.Empty(report.Removed)
+	s.Empty(report.Updated)
+	s.True(loadedProvider.(*mockchain.MockProvider).Closed())
+	s.False(updatedProvider.(*mockchain.MockProvider).Closed())
+}
+
+func (s *RelayTestSuite) TestFilterRejection() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	rly.SetConfig(&Config{Filter: &FilterConfig{Deny: &FilterRule{Dst: []string{mock2Nid}}}})
+
+	src, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+	dst, err := rly.FindChainRuntime(mock2Nid)
+	s.Require().NoError(err)
+
+	m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(1), EventType: "emitMessage", MessageHeight: 13})
+	src.MessageCache.Add(m)
+	s.Require().NoError(rly.messageStore.StoreMessage(m))
+
+	rly.processMessage(context.Background(), src, dst, m)
+
+	// the message is dropped and recorded with the reason
+	_, ok := src.MessageCache.Get(m.MessageKey())
+	s.False(ok)
+	_, err = rly.messageStore.GetMessage(m.MessageKey())
+	s.Error(err)
+	rejected, err := rly.rejectedStore.GetRejected(m.MessageKey())
+	s.Require().NoError(err)
+	s.Equal("dst mock-2 is denied", rejected.Reason)
+}
+
+func (s *RelayTestSuite) TestProfitability() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	// the route charges 10 for a delivery costing 20
+	mock1Provider.(*mockchain.MockProvider).SetFees(10, 0)
+	mock2Provider.(*mockchain.MockProvider).SetFees(0, 20)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	profit := &ProfitConfig{Action: ProfitActionDelay, Delay: time.Hour, Prices: map[string]float64{mock1Nid: 1, mock2Nid: 1}}
+	rly.SetConfig(&Config{Profit: profit})
+
+	src, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+	dst, err := rly.FindChainRuntime(mock2Nid)
+	s.Require().NoError(err)
+
+	m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(1), EventType: "emitMessage", MessageHeight: 13})
+	src.MessageCache.Add(m)
+	s.Require().NoError(rly.messageStore.StoreMessage(m))
+
+	// held until the delay elapses
+	rly.processMessage(context.Background(), src, dst, m)
+	s.Equal(types.MessageStatusDetected, m.GetStatus())
+	s.True(m.LastTry.After(time.Now().Add(50 * time.Minute)))
+	_, ok := src.MessageCache.Get(m.MessageKey())
+	s.True(ok)
+
+	costs := rly.GetRouteCosts(mock1Nid)
+	s.Require().Len(costs, 1)
+	s.False(costs[0].Profitable)
+	s.Equal(uint64(20), costs[0].SuggestedFee)
+
+	// refused once the action is skip
+	profit.Action = ProfitActionSkip
+	m.ClearNextTry()
+	rly.processMessage(context.Background(), src, dst, m)
+	_, ok = src.MessageCache.Get(m.MessageKey())
+	s.False(ok)
+	rejected, err := rly.rejectedStore.GetRejected(m.MessageKey())
+	s.Require().NoError(err)
+	s.Contains(rejected.Reason, "relay cost")
+}
+
+func (s *RelayTestSuite) TestFeeAdjuster() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	// mock-1 charges 10 for a delivery on mock-2 costing 20, a delivery back on mock-1 costs 5
+	provider1 := mock1Provider.(*mockchain.MockProvider)
+	provider1.SetFees(10, 5)
+	mock2Provider.(*mockchain.MockProvider).SetFees(0, 20)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	adjust := &FeeAdjustConfig{Margin: 0.5, DryRun: true}
+	rly.SetConfig(&Config{Profit: &ProfitConfig{Prices: map[string]float64{mock1Nid: 1, mock2Nid: 1}, Adjust: adjust}})
+
+	src, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+
+	ctx := context.Background()
+	// nothing delivered yet
+	change, err := rly.adjustFee(ctx, src, mock2Nid)
+	s.NoError(err)
+	s.Nil(change)
+
+	// the costs are the fees paid for the deliveries, the observed ones and the ones without a fee are left out
+	record := func(from, to string, sn int64, txHash string, fee uint64) {
+		s.Require().NoError(rly.historyStore.StoreRecord(&types.RelayRecord{
+			MessageKey:  types.NewMessageKey(big.NewInt(sn), from, to, "emitMessage"),
+			DstTxHash:   txHash,
+			Fee:         fee,
+			DeliveredAt: time.Now(),
+		}))
+	}
+	record(mock1Nid, mock2Nid, 1, "0x1", 16)
+	record(mock1Nid, mock2Nid, 2, "0x2", 24)
+	record(mock1Nid, mock2Nid, 3, "", 0)
+	record(mock1Nid, mock2Nid, 4, "0x4", 0)
+	record(mock2Nid, mock1Nid, 1, "0x5", 5)
+
+	// a destination priced at nothing does not set the fee to nothing
+	rly.profit.cfg.Prices[mock2Nid] = 0
+	_, err = rly.adjustFee(ctx, src, mock2Nid)
+	s.ErrorIs(err, ErrNoPrice)
+	rly.profit.cfg.Prices[mock2Nid] = 1
+
+	// the dry run only records the change
+	change, err = rly.adjustFee(ctx, src, mock2Nid)
+	s.Require().NoError(err)
+	s.Require().NotNil(change)
+	s.Equal(uint64(30), change.NewMsgFee)
+	s.Equal(uint64(8), change.NewResFee)
+	s.True(change.DryRun)
+	fee, _ := provider1.GetFee(ctx, mock2Nid, false)
+	s.Equal(uint64(10), fee)
+
+	adjust.DryRun = false
+	change, err = rly.adjustFee(ctx, src, mock2Nid)
+	s.Require().NoError(err)
+	s.Require().NotNil(change)
+	s.Empty(change.Error)
+	fee, _ = provider1.GetFee(ctx, mock2Nid, false)
+	s.Equal(uint64(30), fee)
+	fee, _ = provider1.GetFee(ctx, mock2Nid, true)
+	s.Equal(uint64(38), fee)
+
+	// within the threshold
+	change, err = rly.adjustFee(ctx, src, mock2Nid)
+	s.NoError(err)
+	s.Nil(change)
+
+	changes, err := rly.GetFeeAuditStore().GetFeeChanges(mock1Nid, store.NewPagination().GetAll())
+	s.NoError(err)
+	s.Len(changes, 2)
+}
+
+func (s *RelayTestSuite) TestFinalizedDeliveryFee() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	// the destination holds the deliveries until its finality, as the Cosmos chains do
+	provider2 := mock2Provider.(*mockchain.MockProvider)
+	provider2.SetFinalityBlock(10)
+	provider2.SetFees(0, 5000)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	src, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+	dst, err := rly.FindChainRuntime(mock2Nid)
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rly.routeWorker(ctx, dst, rly.queues[mock2Nid])
+
+	m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(1), EventType: "emitMessage", MessageHeight: 13})
+	s.Require().NoError(rly.messageStore.StoreMessage(m))
+	rly.EnqueueMessage(src, m)
+
+	// the fee is kept with the transaction until it is final
+	var txObject *types.TransactionObject
+	s.Eventually(func() bool {
+		txObject, err = rly.finalityStore.GetTxObject(m.MessageKey())
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	s.Equal(uint64(5000), txObject.Fee)
+
+	rly.finalizeConfirmedMessage(rly.newStoreTx(), txObject, src)
+	records, _, err := rly.historyStore.ListRecords(&store.HistoryFilter{Src: mock1Nid}, store.NewPagination().GetAll())
+	s.Require().NoError(err)
+	s.Require().Len(records, 1)
+	s.Equal(uint64(5000), records[0].Fee)
+}
+
+func (s *RelayTestSuite) TestFeeClaimer() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	rly.SetConfig(&Config{FeeClaim: &FeeClaimConfig{Chains: map[string]*ChainFeeClaim{
+		mock1Nid: {Threshold: 100, Treasury: "treasury"},
+		mock2Nid: {Every: time.Hour},
+	}}})
+
+	ctx := context.Background()
+	provider1 := mock1Provider.(*mockchain.MockProvider)
+	provider2 := mock2Provider.(*mockchain.MockProvider)
+	src, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+	dst, err := rly.FindChainRuntime(mock2Nid)
+	s.Require().NoError(err)
+
+	// below the threshold
+	provider1.SetClaimableFee(50)
+	claim, err := rly.claimFee(ctx, src)
+	s.NoError(err)
+	s.Nil(claim)
+
+	// claimed and swept to the treasury
+	provider1.SetClaimableFee(150)
+	claim, err = rly.claimFee(ctx, src)
+	s.Require().NoError(err)
+	s.Require().NotNil(claim)
+	s.Equal(big.NewInt(150), claim.Amount)
+	s.Equal(uint64(150), provider1.Claimed())
+	s.Equal(uint64(150), provider1.Transferred("treasury"))
+
+	// only what the claim credited is swept
+	provider1.SetClaimableFee(200)
+	provider1.SetClaimReserve(30)
+	claim, err = rly.claimFee(ctx, src)
+	s.Require().NoError(err)
+	s.Require().NotNil(claim)
+	s.Equal(big.NewInt(170), claim.Amount)
+	s.Equal(uint64(320), provider1.Claimed())
+	s.Equal(uint64(320), provider1.Transferred("treasury"))
+
+	// claimed on schedule, then not before the next one
+	provider2.SetClaimableFee(10)
+	claim, err = rly.claimFee(ctx, dst)
+	s.Require().NoError(err)
+	s.Require().NotNil(claim)
+	s.Empty(claim.Treasury)
+	provider2.SetClaimableFee(10)
+	claim, err = rly.claimFee(ctx, dst)
+	s.NoError(err)
+	s.Nil(claim)
+	s.Equal(uint64(10), provider2.Claimed())
+
+	claims, err := rly.GetFeeClaimStore().GetFeeClaims("", store.NewPagination().GetAll())
+	s.NoError(err)
+	s.Len(claims, 3)
+}
+
+func (s *RelayTestSuite) TestLowFundsCircuitBreaker() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	// a delivery on mock-2 costs 20 and its wallet holds 5
+	provider2 := mock2Provider.(*mockchain.MockProvider)
+	provider2.SetFees(0, 20)
+	provider2.SetBalance(big.NewInt(5))
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	rly.SetConfig(&Config{Balance: &BalanceConfig{Interval: time.Hour, Thresholds: map[string]*big.Int{mock2Nid: big.NewInt(100)}}})
+
+	ctx := context.Background()
+	src, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+	dst, err := rly.FindChainRuntime(mock2Nid)
+	s.Require().NoError(err)
+	s.Require().NoError(rly.checkBalance(ctx, dst))
+
+	m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(1), EventType: "emitMessage", MessageHeight: 13})
+	src.MessageCache.Add(m)
+	s.Require().NoError(rly.messageStore.StoreMessage(m))
+
+	// held without counting a retry while the wallet cannot cover the cost
+	rly.processMessage(ctx, src, dst, m)
+	s.Equal(types.MessageStatusDetected, m.GetStatus())
+	s.Equal(uint8(0), m.GetRetry())
+	s.True(m.LastTry.After(time.Now().Add(50 * time.Minute)))
+
+	wallets, err := rly.GetWalletStatus(mock2Nid)
+	s.Require().NoError(err)
+	s.Require().Len(wallets, 1)
+	s.True(wallets[0].Low())
+	s.True(wallets[0].Paused())
+	s.Equal(uint64(20), wallets[0].Needed)
+
+	// still paused while the balance stays short of the cost
+	provider2.SetBalance(big.NewInt(10))
+	s.Require().NoError(rly.checkBalance(ctx, dst))
+	s.True(dst.paused())
+
+	// resumed once the wallet is topped up, beyond the range of an uint64, the held message is routed right away
+	provider2.SetBalance(new(big.Int).Mul(big.NewInt(20), big.NewInt(1e18)))
+	s.Require().NoError(rly.checkBalance(ctx, dst))
+	s.False(dst.paused())
+	wallets, err = rly.GetWalletStatus(mock2Nid)
+	s.Require().NoError(err)
+	s.False(wallets[0].Low())
+	s.False(m.LastTry.After(time.Now()))
+
+	rly.processMessage(ctx, src, dst, m)
+	_, ok := src.MessageCache.Get(m.MessageKey())
+	s.False(ok)
+
+	// a drained pool wallet pauses the routing as well, any wallet of the pool may be picked
+	mock2Provider.Config().(*mockchain.MockProviderConfig).SetWallets([]string{"pool-1"})
+	provider2.SetWalletBalance("pool-1", big.NewInt(3))
+	s.Require().NoError(rly.checkBalance(ctx, dst))
+	wallets, err = rly.GetWalletStatus(mock2Nid)
+	s.Require().NoError(err)
+	s.Require().Len(wallets, 2)
+	s.Equal("pool-1", wallets[1].Address)
+	s.False(wallets[0].Low())
+	s.True(wallets[1].Low())
+
+	m = types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(2), EventType: "emitMessage", MessageHeight: 13})
+	src.MessageCache.Add(m)
+	s.Require().NoError(rly.messageStore.StoreMessage(m))
+	rly.processMessage(ctx, src, dst, m)
+	s.Equal(types.MessageStatusDetected, m.GetStatus())
+	s.True(dst.paused())
+
+	provider2.SetWalletBalance("pool-1", big.NewInt(1000))
+	s.Require().NoError(rly.checkBalance(ctx, dst))
+	s.False(dst.paused())
+}
+
+func (s *RelayTestSuite) TestBatchDelivery() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	mock1Nid, mock2Nid := "mock-1", "mock-2"
+	chains := make(map[string]*Chain, 2)
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+	provider2 := mock2Provider.(*mockchain.MockProvider)
+
+	rly, err := NewRelayer(s.logger, s.db, chains, true)
+	s.Require().NoError(err)
+	batch := &BatchConfig{Size: 3, Window: 200 * time.Millisecond}
+	rly.SetConfig(&Config{Scheduler: &SchedulerConfig{Destinations: map[string]*DestinationConfig{mock2Nid: {Batch: batch}}}})
+
+	src, err := rly.FindChainRuntime(mock1Nid)
+	s.Require().NoError(err)
+	dst, err := rly.FindChainRuntime(mock2Nid)
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rly.routeWorker(ctx, dst, rly.queues[mock2Nid])
+
+	enqueue := func(sns ...int64) []*types.RouteMessage {
+		messages := make([]*types.RouteMessage, 0, len(sns))
+		for _, sn := range sns {
+			m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(sn), EventType: "emitMessage", MessageHeight: 13})
+			s.Require().NoError(rly.messageStore.StoreMessage(m))
+			rly.EnqueueMessage(src, m)
+			messages = append(messages, m)
+		}
+		return messages
+	}
+
+	// a full batch is routed right away, the last message alone once the window elapses
+	provider2.SetFees(0, 31)
+	enqueue(1, 2, 3, 4)
+	s.Eventually(func() bool { return src.MessageCache.Len() == 0 }, 5*time.Second, 50*time.Millisecond)
+	s.Equal([]int{3}, provider2.Batches())
+	delivered, err := rly.deliveredStore.GetDelivered(types.NewMessageKey(big.NewInt(2), mock1Nid, mock2Nid, "emitMessage"))
+	s.Require().NoError(err)
+	s.Equal("batch-mock-1-1", delivered.TxHash)
+
+	// the messages of the batch record their share of its cost
+	records, _, err := rly.historyStore.ListRecords(&store.HistoryFilter{Src: mock1Nid}, store.NewPagination().GetAll())
+	s.Require().NoError(err)
+	fees := make(map[int64]uint64, len(records))
+	for _, record := range records {
+		fees[record.Sn.Int64()] = record.Fee
+	}
+	s.Equal(map[int64]uint64{1: 11, 2: 10, 3: 10, 4: 31}, fees)
+	provider2.SetFees(0, 0)
+
+	// the messages of a failed batch are retried on their own
+	provider2.FailBatch(fmt.Errorf("out of gas"))
+	messages := enqueue(5, 6)
+	s.Eventually(func() bool { return len(provider2.Batches()) == 2 }, 5*time.Second, 50*time.Millisecond)
+	s.Eventually(func() bool {
+		for _, m := range messages {
+			if m.GetRetry() != 1 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 50*time.Millisecond)
+	for _, m := range messages {
+		m.ClearNextTry()
+		rly.schedule(src, m)
+	}
+	s.Eventually(func() bool { return src.MessageCache.Len() == 0 }, 5*time.Second, 50*time.Millisecond)
+	s.Equal([]int{3, 2}, provider2.Batches())
+
+	// a message failing within the batch is retried on its own, the others are delivered
+	provider2.FailInBatch(big.NewInt(8), fmt.Errorf("execution reverted"))
+	messages = enqueue(7, 8, 9)
+	s.Eventually(func() bool { return src.MessageCache.Len() == 1 }, 5*time.Second, 50*time.Millisecond)
+	failed, ok := src.MessageCache.Get(messages[1].MessageKey())
+	s.Require().True(ok)
+	s.Equal(uint8(1), failed.GetRetry())
+	failed.ClearNextTry()
+	rly.schedule(src, failed)
+	s.Eventually(func() bool { return src.MessageCache.Len() == 0 }, 5*time.Second, 50*time.Millisecond)
+	s.Equal([]int{3, 2, 3}, provider2.Batches())
+}
+
+// failingStore fails the batch writes while fail is set
+type failingStore struct {
+	store.Store
+	fail bool
+}
+
+func (f *failingStore) NewBatch() store.Batch {
+	return &failingBatch{Batch: f.Store.NewBatch(), store: f}
+}
+
+type failingBatch struct {
+	store.Batch
+	store *failingStore
+}
+
+func (b *failingBatch) Write() error {
+	if b.store.fail {
+		return fmt.Errorf("disk failure")
+	}
+	return b.Batch.Write()
+}
+
+func (s *RelayTestSuite) TestAtomicStateChanges() {
+	s.T().Cleanup(func() {
+		s.db.Close()
+		s.db.RemoveDbFile(levelDbName)
+	})
+
+	chains := make(map[string]*Chain, 0)
+	mock1Nid := "mock-1"
+	mock2Nid := "mock-2"
+
+	mock1Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock1Nid, mock2Nid, 10, 20)
+	s.Require().NoError(err)
+	chains[mock1Nid] = NewChain(s.logger, mock1Provider, true)
+
+	mock2Provider, err := GetMockChainProvider(s.logger, 500*time.Millisecond, mock2Nid, mock1Nid, 20, 10)
+	s.Require().NoError(err)
+	chains[mock2Nid] = NewChain(s.logger, mock2Provider, true)
+
+	db := &failingStore{Store: s.db}
+	rly, err := NewRelayer(s.logger, db, chains, true)
+	s.Require().NoError(err)
+	src, dst := rly.chains[mock1Nid], rly.chains[mock2Nid]
+
+	submitted := func(sn int64) *types.RouteMessage {
+		m := types.NewRouteMessage(&types.Message{Src: mock1Nid, Dst: mock2Nid, Sn: big.NewInt(sn), EventType: "emitMessage"})
+		s.Require().NoError(m.SetStatus(types.MessageStatusQueued))
+		s.Require().NoError(m.SetStatus(types.MessageStatusSubmitted))
+		s.Require().NoError(rly.messageStore.StoreMessage(m))
+		pendingTx := types.NewPendingTransaction(types.NewMessagekeyWithMessageHeight(m.MessageKey(), m.MessageHeight), "0x1", 1)
+		s.Require().NoError(rly.pendingTxStore.StorePendingTx(pendingTx))
+		src.MessageCache.Add(m)
+		return m
+	}
+	success := &types.TxResponse{TxHash: "0x1", Height: 21, Code: types.Success}
+
+	// a failed write leaves the delivery as it was before it
+	m := submitted(1)
+	db.fail = true
+	rly.callback(context.Background(), src, dst, m.MessageKey())(m.MessageKey(), success, nil)
+
+	stored, err := rly.messageStore.GetMessage(m.MessageKey())
+	s.Require().NoError(err)
+	s.Equal(types.MessageStatusSubmitted, stored.GetStatus())
+	_, err = rly.pendingTxStore.GetPendingTx(m.MessageKey())
+	s.NoError(err)
+	s.False(rly.deliveredStore.IsDelivered(m.MessageKey()))
+
+	// the delivery is settled in all the stores at once
+	db.fail = false
+	m = submitted(2)
+	rly.callback(context.Background(), src, dst, m.MessageKey())(m.MessageKey(), success, nil)
+
+	_, err = rly.messageStore.GetMessage(m.MessageKey())
+	s.ErrorIs(err, store.ErrNotFound)
+	_, err = rly.pendingTxStore.GetPendingTx(m.MessageKey())
+	s.Error(err)
+	s.True(rly.deliveredStore.IsDelivered(m.MessageKey()))
+	_, ok := src.MessageCache.Get(m.MessageKey())
+	s.False(ok)
+
+	// the dead letter and the message removal are written at once
+	rly.SetConfig(&Config{Retry: &RetryConfig{Default: &RetryPolicy{MaxAttempts: 1, BaseDelay: time.Second}}})
+	m = submitted(3)
+	m.IncrementRetry()
+	db.fail = true
+	rly.HandleMessageFailed(m, dst, src, fmt.Errorf("reverted"))
+
+	_, err = rly.messageStore.GetMessage(m.MessageKey())
+	s.NoError(err)
+	_, err = rly.deadLetterStore.GetDeadLetter(m.MessageKey())
+	s.Error(err)
+}
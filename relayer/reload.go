@@ -0,0 +1,212 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// DefaultConfigWatchInterval is how frequently the config file is checked for chain changes
+var DefaultConfigWatchInterval = 10 * time.Second
+
+// ChainLoader returns the enabled chains of the config, mapped by nid
+type ChainLoader func(ctx context.Context) (map[string]*Chain, error)
+
+// ReloadReport lists the chains changed by a config reload
+type ReloadReport struct {
+	Added   []string
+	Removed []string
+	Updated []string
+}
+
+// SetChainLoader sets the loader Reload reads the chain config with
+func (r *Relayer) SetChainLoader(loader ChainLoader) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+	r.loadChains = loader
+}
+
+// Reload reads the chain config with the chain loader and applies it
+func (r *Relayer) Reload(ctx context.Context) (*ReloadReport, error) {
+	r.reloadMu.Lock()
+	loader := r.loadChains
+	r.reloadMu.Unlock()
+	if loader == nil {
+		return nil, fmt.Errorf("config reload is not supported")
+	}
+	chains, err := loader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chains: %w", err)
+	}
+	return r.ReloadChains(ctx, chains)
+}
+
+// ReloadChains makes the running chains match the given ones: new chains are started,
+// missing or disabled chains are stopped and chains with a changed provider config are
+// restarted with the new provider. The message caches and heights of the chains are kept.
+func (r *Relayer) ReloadChains(ctx context.Context, chains map[string]*Chain) (*ReloadReport, error) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	var (
+		report  = new(ReloadReport)
+		errs    []error
+		current = r.chainRuntimes()
+	)
+	for nId, chain := range current {
+		if _, ok := chains[nId]; !ok {
+			r.detachChain(ctx, chain)
+			report.Removed = append(report.Removed, nId)
+		}
+	}
+	for nId, chain := range chains {
+		running, ok := current[nId]
+		if ok {
+			if reflect.DeepEqual(running.Provider.Config(), chain.ChainProvider.Config()) {
+				// the loader built a provider the running chain keeps going without
+				if chain.ChainProvider != running.Provider {
+					r.closeProvider(chain.ChainProvider)
+				}
+				continue
+			}
+			r.detachChain(ctx, running)
+		}
+		if _, err := r.attachChain(chain); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", nId, err))
+			continue
+		}
+		if ok {
+			report.Updated = append(report.Updated, nId)
+		} else {
+			report.Added = append(report.Added, nId)
+		}
+	}
+	sort.Strings(report.Added)
+	sort.Strings(report.Removed)
+	sort.Strings(report.Updated)
+
+	// schedule the messages held for the chains that are back
+	if len(report.Added) > 0 || len(report.Updated) > 0 {
+		r.sweepMessages()
+	}
+
+	r.log.Info("chain config reloaded",
+		zap.Strings("added", report.Added),
+		zap.Strings("removed", report.Removed),
+		zap.Strings("updated", report.Updated),
+	)
+	return report, errors.Join(errs...)
+}
+
+// attachChain creates the runtime of the chain and starts it when the relayer is running,
+// a chain detached by an earlier reload gets its message cache, heights and queued messages
+// back and the provider of the detached runtime is closed
+func (r *Relayer) attachChain(chain *Chain) (*ChainRuntime, error) {
+	chainRuntime, err := NewChainRuntime(r.log, chain)
+	if err != nil {
+		return nil, err
+	}
+	nId := chain.NID()
+
+	r.chainsMu.Lock()
+	defer r.chainsMu.Unlock()
+	if _, ok := r.chains[nId]; ok {
+		return nil, fmt.Errorf("chain %s is already running", nId)
+	}
+	if prev, ok := r.detached[nId]; ok {
+		chainRuntime.inherit(prev)
+		delete(r.detached, nId)
+		for _, q := range r.queues {
+			q.rebind(prev, chainRuntime)
+		}
+		r.closeProvider(prev.Provider)
+	} else if lastSavedHeight, err := r.blockStore.GetLastStoredBlock(nId); err == nil {
+		// successfully fetched last savedBlock
+		chainRuntime.lastSavedHeight.Store(lastSavedHeight)
+	}
+	chainRuntime.Provider.SetLastSavedHeightFunc(chainRuntime.LastSavedHeight)
+	r.chains[nId] = chainRuntime
+	q, ok := r.queues[nId]
+	if !ok {
+		q = newWorkQueue()
+		r.queues[nId] = q
+	}
+	q.setStrictOrdering(r.cfg.Scheduler.strictOrdering(nId))
+	if r.ctx != nil {
+		r.startChain(chainRuntime)
+	}
+	return chainRuntime, nil
+}
+
+// detachChain stops the chain and waits for its in-flight routes, the runtime is kept
+// aside so that the messages to the chain are held until it is attached again
+func (r *Relayer) detachChain(ctx context.Context, chain *ChainRuntime) {
+	nId := chain.Provider.NID()
+	r.chainsMu.Lock()
+	delete(r.chains, nId)
+	r.detached[nId] = chain
+	r.chainsMu.Unlock()
+
+	r.stopChain(chain)
+	if height := chain.LastBlockHeight(); height > 0 {
+		if err := r.SaveBlockHeight(ctx, chain, height); err != nil {
+			chain.log.Error("error occured when saving block height", zap.Error(err))
+		}
+	}
+	chain.log.Info("chain detached")
+}
+
+// closeProvider releases the connections of a provider that is no longer used
+func (r *Relayer) closeProvider(p provider.ChainProvider) {
+	closer, ok := p.(provider.Closer)
+	if !ok {
+		return
+	}
+	if err := closer.Close(); err != nil {
+		r.log.Warn("failed to close provider", zap.String("nid", p.NID()), zap.Error(err))
+	}
+}
+
+// startChain runs the listener, the block processor and the route workers of the chain
+// until the chain is stopped, the caller holds chainsMu
+func (r *Relayer) startChain(chain *ChainRuntime) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	chain.cancel = cancel
+	nId := chain.Provider.NID()
+
+	chain.running.Add(2)
+	go func() {
+		defer chain.running.Done()
+		r.superviseListener(ctx, chain)
+	}()
+	go func() {
+		defer chain.running.Done()
+		r.processBlocks(ctx, chain)
+	}()
+
+	// the routing is synchronous so the pool size bounds the transactions in flight
+	q := r.queues[nId]
+	for i := 0; i < r.cfg.Scheduler.workers(nId); i++ {
+		chain.running.Add(1)
+		r.workers.Add(1)
+		go func() {
+			defer r.workers.Done()
+			defer chain.running.Done()
+			r.routeWorker(ctx, chain, q)
+		}()
+	}
+}
+
+// stopChain stops the goroutines of the chain and waits for them
+func (r *Relayer) stopChain(chain *ChainRuntime) {
+	if chain.cancel != nil {
+		chain.cancel()
+	}
+	chain.running.Wait()
+}
@@ -0,0 +1,164 @@
+package relayer
+
+import (
+	"context"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+)
+
+var (
+	// ReorgCheckInterval is how often the hash of the recorded source blocks is checked
+	ReorgCheckInterval = 10 * time.Second
+	// ReorgTrackDepth is the minimum number of blocks a block is tracked for
+	ReorgTrackDepth uint64 = 128
+)
+
+// StartReorgDetector checks that the source blocks messages were emitted from are still canonical
+func (r *Relayer) StartReorgDetector(ctx context.Context) {
+	ticker := time.NewTicker(ReorgCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, c := range r.chainRuntimes() {
+				r.checkReorgs(ctx, c)
+			}
+		}
+	}
+}
+
+// checkReorgs compares the recorded block hashes of the chain with the current ones
+// and forgets the blocks that are deep enough to be final
+func (r *Relayer) checkReorgs(ctx context.Context, src *ChainRuntime) {
+	nId := src.Provider.NID()
+	records, err := r.blockRecordStore.GetBlockRecords(nId)
+	if err != nil {
+		src.log.Warn("failed to get block records", zap.Error(err))
+		return
+	}
+	depth := max(src.Provider.FinalityBlock(ctx), ReorgTrackDepth)
+	for _, record := range records {
+		if record.Height+depth < src.LastBlockHeight() {
+			if err := r.blockRecordStore.DeleteBlockRecord(nId, record.Height); err != nil {
+				src.log.Warn("failed to delete block record", zap.Uint64("height", record.Height), zap.Error(err))
+			}
+			continue
+		}
+		hash, err := src.Provider.QueryBlockHash(ctx, record.Height)
+		if err != nil {
+			src.log.Warn("failed to query block hash", zap.Uint64("height", record.Height), zap.Error(err))
+			continue
+		}
+		if hash == "" || hash == record.Hash {
+			continue
+		}
+		r.handleReorg(ctx, src, record, hash)
+	}
+}
+
+// handleReorg replaces the messages of a reorged block with the ones of the canonical block at its height
+func (r *Relayer) handleReorg(ctx context.Context, src *ChainRuntime, record *types.BlockRecord, hash string) {
+	src.log.Warn("source chain reorg detected",
+		zap.Uint64("height", record.Height),
+		zap.String("recorded_hash", record.Hash),
+		zap.String("hash", hash),
+	)
+	// derive the canonical messages first, the record is kept to retry when it fails
+	messages, err := src.Provider.GenerateMessages(ctx, types.NewMessagekeyWithMessageHeight(&types.MessageKey{Src: src.Provider.NID()}, record.Height))
+	if err != nil {
+		src.log.Error("failed to generate messages of reorged block", zap.Uint64("height", record.Height), zap.Error(err))
+		return
+	}
+
+	src.blockMu.Lock()
+	current, err := r.blockRecordStore.GetBlockRecord(src.Provider.NID(), record.Height)
+	if err != nil || current.Hash != record.Hash {
+		// the listener recorded the block again meanwhile
+		src.blockMu.Unlock()
+		return
+	}
+	if err := r.blockRecordStore.DeleteBlockRecord(src.Provider.NID(), record.Height); err != nil {
+		src.blockMu.Unlock()
+		src.log.Error("failed to delete block record", zap.Uint64("height", record.Height), zap.Error(err))
+		return
+	}
+	src.blockMu.Unlock()
+
+	r.retractBlock(ctx, src, record)
+	r.addBlockMessages(ctx, src, &types.BlockInfo{
+		Height:   record.Height,
+		Hash:     hash,
+		Messages: messages,
+	})
+}
+
+// recordBlock records the messages of a block with its hash, the previous record of the height
+// is returned when the block was replaced
+func (r *Relayer) recordBlock(src *ChainRuntime, blockInfo *types.BlockInfo) *types.BlockRecord {
+	src.blockMu.Lock()
+	defer src.blockMu.Unlock()
+
+	nId := src.Provider.NID()
+	record, err := r.blockRecordStore.GetBlockRecord(nId, blockInfo.Height)
+	var stale *types.BlockRecord
+	if err != nil || record.Hash != blockInfo.Hash {
+		if err == nil {
+			stale = record
+		}
+		record = &types.BlockRecord{Height: blockInfo.Height, Hash: blockInfo.Hash}
+	}
+	record.AddMessages(blockInfo.Messages)
+	if err := r.blockRecordStore.StoreBlockRecord(nId, record); err != nil {
+		src.log.Error("failed to store block record", zap.Uint64("height", blockInfo.Height), zap.Error(err))
+	}
+	return stale
+}
+
+// retractBlock removes the messages of a reorged block from the pipeline,
+// messages already in flight or delivered cannot be taken back
+func (r *Relayer) retractBlock(ctx context.Context, src *ChainRuntime, record *types.BlockRecord) {
+	for _, key := range record.Messages {
+		if r.deliveredStore.IsDelivered(key) {
+			src.log.Error("message of a reorged block was already delivered",
+				zap.String("src", key.Src),
+				zap.String("dst", key.Dst),
+				zap.Uint64("sn", key.Sn.Uint64()),
+				zap.String("event_type", key.EventType),
+			)
+			continue
+		}
+		m, ok := src.MessageCache.Get(key)
+		if !ok {
+			stored, err := r.messageStore.GetMessage(key)
+			if err != nil {
+				continue
+			}
+			m = stored
+		}
+		switch m.GetStatus() {
+		case types.MessageStatusQueued, types.MessageStatusSubmitted, types.MessageStatusConfirmed:
+			src.log.Error("message of a reorged block is in flight",
+				zap.String("src", key.Src),
+				zap.String("dst", key.Dst),
+				zap.Uint64("sn", key.Sn.Uint64()),
+				zap.String("status", string(m.GetStatus())),
+			)
+			continue
+		}
+		if err := r.ClearMessages(ctx, []*types.MessageKey{key}, src); err != nil {
+			src.log.Error("failed to retract message of a reorged block", zap.Error(err))
+			continue
+		}
+		src.log.Info("retracted message of a reorged block",
+			zap.String("src", key.Src),
+			zap.String("dst", key.Dst),
+			zap.Uint64("sn", key.Sn.Uint64()),
+			zap.String("event_type", key.EventType),
+		)
+	}
+}
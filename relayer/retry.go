@@ -0,0 +1,171 @@
+package relayer
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// RetryPolicy describes how many times and how often a message is retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of tries before the message is moved to the dead letter queue
+	MaxAttempts uint8 `yaml:"max-attempts,omitempty" json:"max-attempts,omitempty"`
+	// BaseDelay is doubled on every try
+	BaseDelay time.Duration `yaml:"base-delay,omitempty" json:"base-delay,omitempty"`
+	// MaxDelay caps the delay between tries, no cap when zero
+	MaxDelay time.Duration `yaml:"max-delay,omitempty" json:"max-delay,omitempty"`
+	// Jitter randomizes the delay by the given fraction, between 0 and 1
+	Jitter float64 `yaml:"jitter,omitempty" json:"jitter,omitempty"`
+	// RetryForever ignores MaxAttempts and keeps retrying, it is inherited when unset
+	// so that a rule can turn it off with an explicit false
+	RetryForever *bool `yaml:"retry-forever,omitempty" json:"retry-forever,omitempty"`
+}
+
+// DefaultRetryPolicy returns the policy used when nothing is configured
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: types.MaxTxRetry,
+		BaseDelay:   types.RetryInterval,
+	}
+}
+
+// Delay returns the time to wait before the next try once the message was tried retry times
+func (p *RetryPolicy) Delay(retry uint8) time.Duration {
+	delay := p.BaseDelay
+	for i := uint8(0); i < retry; i++ {
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+		// stop doubling before the duration overflows
+		if delay > time.Duration(1<<62) {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// Exhausted reports whether the message used up all of its tries
+func (p *RetryPolicy) Exhausted(retry uint8) bool {
+	return !p.retryForever() && retry >= p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryForever() bool {
+	return p.RetryForever != nil && *p.RetryForever
+}
+
+// Validate checks the policy values
+func (p *RetryPolicy) Validate() error {
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1: %v", p.Jitter)
+	}
+	if p.MaxDelay > 0 && p.MaxDelay < p.BaseDelay {
+		return fmt.Errorf("max-delay %s is lower than base-delay %s", p.MaxDelay, p.BaseDelay)
+	}
+	return nil
+}
+
+// inherit fills the unset values from the parent policy
+func (p *RetryPolicy) inherit(parent *RetryPolicy) *RetryPolicy {
+	policy := *p
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = parent.MaxAttempts
+	}
+	if policy.BaseDelay == 0 {
+		policy.BaseDelay = parent.BaseDelay
+	}
+	if policy.MaxDelay == 0 {
+		policy.MaxDelay = parent.MaxDelay
+	}
+	if policy.Jitter == 0 {
+		policy.Jitter = parent.Jitter
+	}
+	if policy.RetryForever == nil {
+		policy.RetryForever = parent.RetryForever
+	}
+	return &policy
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+// RetryRule overrides the default policy for the matching messages,
+// empty src, dst or event-type match any value
+type RetryRule struct {
+	Src         string `yaml:"src,omitempty" json:"src,omitempty"`
+	Dst         string `yaml:"dst,omitempty" json:"dst,omitempty"`
+	EventType   string `yaml:"event-type,omitempty" json:"event-type,omitempty"`
+	RetryPolicy `yaml:",inline"`
+}
+
+func (r *RetryRule) matches(m *types.Message) bool {
+	return (r.Src == "" || r.Src == m.Src) &&
+		(r.Dst == "" || r.Dst == m.Dst) &&
+		(r.EventType == "" || r.EventType == m.EventType)
+}
+
+// specificity is the number of fields the rule matches on
+func (r *RetryRule) specificity() int {
+	count := 0
+	for _, v := range []string{r.Src, r.Dst, r.EventType} {
+		if v != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// RetryConfig is the retry section of the global config
+type RetryConfig struct {
+	Default *RetryPolicy `yaml:"default,omitempty" json:"default,omitempty"`
+	Rules   []*RetryRule `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+// Validate checks the default policy and all the rules
+func (c *RetryConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Default != nil {
+		if err := c.Default.Validate(); err != nil {
+			return fmt.Errorf("retry default: %w", err)
+		}
+	}
+	for i, rule := range c.Rules {
+		if err := rule.RetryPolicy.Validate(); err != nil {
+			return fmt.Errorf("retry rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Policy returns the policy of the most specific rule matching the message,
+// the first declared rule wins on a tie
+func (c *RetryConfig) Policy(m *types.Message) *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	if c == nil {
+		return policy
+	}
+	if c.Default != nil {
+		policy = c.Default.inherit(policy)
+	}
+	var match *RetryRule
+	for _, rule := range c.Rules {
+		if rule.matches(m) && (match == nil || rule.specificity() > match.specificity()) {
+			match = rule
+		}
+	}
+	if match == nil {
+		return policy
+	}
+	return match.RetryPolicy.inherit(policy)
+}
@@ -0,0 +1,95 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRetryConfig(t *testing.T) {
+	data := `
+retry:
+  default:
+    max-attempts: 5
+    base-delay: 6s
+    max-delay: 2m
+  rules:
+    - dst: 0xa4b1.arbitrum
+      max-attempts: 20
+      base-delay: 1s
+      max-delay: 10s
+    - event-type: emitMessage
+      retry-forever: true
+    - src: 0x1.icon
+      dst: archway-1
+      event-type: rollbackMessage
+      base-delay: 1m
+      max-delay: 1h
+`
+	cfg := new(Config)
+	assert.NoError(t, yaml.Unmarshal([]byte(data), cfg))
+	assert.NoError(t, cfg.Validate())
+
+	t.Run("default policy", func(t *testing.T) {
+		policy := cfg.Retry.Policy(&types.Message{Src: "0x1.icon", Dst: "0x2.bsc", EventType: "callMessage"})
+		assert.Equal(t, uint8(5), policy.MaxAttempts)
+		assert.Equal(t, 6*time.Second, policy.Delay(0))
+		assert.Equal(t, 24*time.Second, policy.Delay(2))
+		assert.Equal(t, 2*time.Minute, policy.Delay(10))
+		assert.True(t, policy.Exhausted(5))
+	})
+
+	t.Run("route override", func(t *testing.T) {
+		policy := cfg.Retry.Policy(&types.Message{Src: "0x1.icon", Dst: "0xa4b1.arbitrum", EventType: "callMessage"})
+		assert.Equal(t, uint8(20), policy.MaxAttempts)
+		assert.Equal(t, 10*time.Second, policy.Delay(8))
+		assert.False(t, policy.Exhausted(19))
+	})
+
+	t.Run("retry forever", func(t *testing.T) {
+		policy := cfg.Retry.Policy(&types.Message{Src: "0x1.icon", Dst: "0x2.bsc", EventType: "emitMessage"})
+		assert.False(t, policy.Exhausted(255))
+		assert.Equal(t, 2*time.Minute, policy.Delay(255))
+	})
+
+	t.Run("most specific rule wins", func(t *testing.T) {
+		policy := cfg.Retry.Policy(&types.Message{Src: "0x1.icon", Dst: "archway-1", EventType: "rollbackMessage"})
+		assert.Equal(t, uint8(5), policy.MaxAttempts)
+		assert.Equal(t, time.Minute, policy.Delay(0))
+		assert.Equal(t, time.Hour, policy.Delay(10))
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		policy := &RetryPolicy{BaseDelay: 10 * time.Second, Jitter: 0.2}
+		for i := 0; i < 10; i++ {
+			delay := policy.Delay(0)
+			assert.GreaterOrEqual(t, delay, 8*time.Second)
+			assert.LessOrEqual(t, delay, 12*time.Second)
+		}
+		assert.Error(t, (&RetryPolicy{Jitter: 2}).Validate())
+	})
+
+	t.Run("rule turns off retry forever", func(t *testing.T) {
+		cfg := &RetryConfig{
+			Default: &RetryPolicy{MaxAttempts: 3, RetryForever: boolPtr(true)},
+			Rules: []*RetryRule{
+				{Dst: "0x2.bsc", RetryPolicy: RetryPolicy{RetryForever: boolPtr(false)}},
+				{Dst: "archway-1", RetryPolicy: RetryPolicy{BaseDelay: time.Second}},
+			},
+		}
+		assert.True(t, cfg.Policy(&types.Message{Dst: "0x2.bsc"}).Exhausted(3))
+		// unset inherits the default
+		assert.False(t, cfg.Policy(&types.Message{Dst: "archway-1"}).Exhausted(255))
+		assert.False(t, cfg.Policy(&types.Message{Dst: "0x1.icon"}).Exhausted(255))
+	})
+
+	t.Run("no config", func(t *testing.T) {
+		var empty *RetryConfig
+		policy := empty.Policy(&types.Message{})
+		assert.Equal(t, types.MaxTxRetry, policy.MaxAttempts)
+		assert.Equal(t, types.RetryInterval, policy.BaseDelay)
+	})
+}
@@ -0,0 +1,477 @@
+package relayer
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// DefaultRouteWorkers is the number of messages routed at once to a destination
+var DefaultRouteWorkers = 10
+
+// DefaultBatchWindow is how long a batch waits for more messages after its first one
+var DefaultBatchWindow = 2 * time.Second
+
+// SchedulerConfig is the scheduler section of the global config
+type SchedulerConfig struct {
+	// Workers is the size of the worker pool of every destination
+	Workers int `yaml:"workers,omitempty" json:"workers,omitempty"`
+	// Destinations overrides the scheduling of the destination nId
+	Destinations map[string]*DestinationConfig `yaml:"destinations,omitempty" json:"destinations,omitempty"`
+	// Sources holds the messages of the source nId until they are final on it
+	Sources map[string]*SourceConfig `yaml:"sources,omitempty" json:"sources,omitempty"`
+}
+
+// SourceConfig is the finality a source message must reach before it is routed
+type SourceConfig struct {
+	// Confirmations is the number of blocks built on top of the message block
+	Confirmations uint64 `yaml:"confirmations,omitempty" json:"confirmations,omitempty"`
+	// Finalized waits for the message block to be finalized, the "finalized" block tag on EVM
+	Finalized bool `yaml:"finalized,omitempty" json:"finalized,omitempty"`
+}
+
+// SourceFinalityInterval is how long a message waits before its source finality is checked again
+var SourceFinalityInterval = 5 * time.Second
+
+// DestinationConfig is the scheduling of a single destination
+type DestinationConfig struct {
+	// MaxInFlight is the number of transactions sent at once to the destination
+	MaxInFlight int `yaml:"max-in-flight,omitempty" json:"max-in-flight,omitempty"`
+	// StrictOrdering lists the source nIds whose messages are delivered one at a time in sn order
+	StrictOrdering []string `yaml:"strict-ordering,omitempty" json:"strict-ordering,omitempty"`
+	// Batch delivers the ready messages together in one transaction, on the chains supporting it
+	Batch *BatchConfig `yaml:"batch,omitempty" json:"batch,omitempty"`
+}
+
+// BatchConfig groups the messages ready for a destination in one transaction
+type BatchConfig struct {
+	// Size is the maximum number of messages of a batch
+	Size int `yaml:"size,omitempty" json:"size,omitempty"`
+	// Window is how long a batch waits for more messages after its first one
+	Window time.Duration `yaml:"window,omitempty" json:"window,omitempty"`
+}
+
+func (c *BatchConfig) window() time.Duration {
+	if c.Window == 0 {
+		return DefaultBatchWindow
+	}
+	return c.Window
+}
+
+// Validate checks the scheduler values
+func (c *SchedulerConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Workers < 0 {
+		return fmt.Errorf("scheduler workers cannot be negative: %d", c.Workers)
+	}
+	for nId, d := range c.Destinations {
+		if d == nil {
+			continue
+		}
+		if d.MaxInFlight < 0 {
+			return fmt.Errorf("scheduler destination %s: max-in-flight cannot be negative: %d", nId, d.MaxInFlight)
+		}
+		for _, src := range d.StrictOrdering {
+			if src == "" {
+				return fmt.Errorf("scheduler destination %s: strict-ordering source cannot be empty", nId)
+			}
+		}
+		if b := d.Batch; b != nil {
+			if b.Size < 2 {
+				return fmt.Errorf("scheduler destination %s: batch size must be at least 2: %d", nId, b.Size)
+			}
+			if b.Window < 0 {
+				return fmt.Errorf("scheduler destination %s: batch window cannot be negative: %s", nId, b.Window)
+			}
+		}
+	}
+	return nil
+}
+
+// workers returns the worker pool size of the destination nId
+func (c *SchedulerConfig) workers(nId string) int {
+	if c == nil {
+		return DefaultRouteWorkers
+	}
+	if d := c.Destinations[nId]; d != nil && d.MaxInFlight > 0 {
+		return d.MaxInFlight
+	}
+	if c.Workers > 0 {
+		return c.Workers
+	}
+	return DefaultRouteWorkers
+}
+
+// source returns the finality required on the source nId, nil when messages are routed right away
+func (c *SchedulerConfig) source(nId string) *SourceConfig {
+	if c == nil {
+		return nil
+	}
+	if s := c.Sources[nId]; s != nil && (s.Confirmations > 0 || s.Finalized) {
+		return s
+	}
+	return nil
+}
+
+// strictOrdering returns the sources delivered in sn order to the destination nId
+func (c *SchedulerConfig) strictOrdering(nId string) []string {
+	if c == nil {
+		return nil
+	}
+	if d := c.Destinations[nId]; d != nil {
+		return d.StrictOrdering
+	}
+	return nil
+}
+
+// batch returns the batching of the destination nId, nil when its messages are routed one by one
+func (c *SchedulerConfig) batch(nId string) *BatchConfig {
+	if c == nil {
+		return nil
+	}
+	if d := c.Destinations[nId]; d != nil {
+		return d.Batch
+	}
+	return nil
+}
+
+// workItem is a message waiting in a destination queue
+type workItem struct {
+	src        *ChainRuntime
+	message    *types.RouteMessage
+	key        string
+	eligibleAt time.Time
+	detectedAt time.Time
+	ready      bool
+	scheduled  bool
+	index      int
+}
+
+func newWorkItem(src *ChainRuntime, m *types.RouteMessage) *workItem {
+	detectedAt := m.GetDetectedAt()
+	eligibleAt := m.GetLastTry()
+	if eligibleAt.IsZero() {
+		eligibleAt = time.Now()
+	}
+	return &workItem{
+		src:        src,
+		message:    m,
+		key:        workKey(m.MessageKey()),
+		eligibleAt: eligibleAt,
+		detectedAt: detectedAt,
+	}
+}
+
+func workKey(key *types.MessageKey) string {
+	return fmt.Sprintf("%s-%s-%s-%s", key.Src, key.Dst, key.Sn, key.EventType)
+}
+
+// workHeap is a heap of work items ordered by the less function
+type workHeap struct {
+	items []*workItem
+	less  func(a, b *workItem) bool
+}
+
+func (h *workHeap) Len() int           { return len(h.items) }
+func (h *workHeap) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *workHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *workHeap) Push(x any) {
+	item := x.(*workItem)
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *workHeap) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	item.index = -1
+	return item
+}
+
+func (h *workHeap) peek() *workItem {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return h.items[0]
+}
+
+// workQueue holds the messages of a destination, messages wait in the delayed heap
+// until they are eligible and are handed out oldest first from the ready heap.
+// Messages of a strictly ordered source wait in the lane of their source and event type
+// sorted by sn, the sn of the event types of a source are independent sequences. Only the
+// lowest sn of a lane is scheduled and only when no other message of the lane is in flight.
+type workQueue struct {
+	mu       sync.Mutex
+	delayed  *workHeap
+	ready    *workHeap
+	queued   map[string]*workItem
+	ordered  map[string]bool
+	lanes    map[laneKey][]*workItem
+	inFlight map[laneKey]bool
+	wake     chan struct{}
+}
+
+// laneKey identifies the sn sequence of the messages of a source
+type laneKey struct {
+	src       string
+	eventType string
+}
+
+func newLaneKey(m *types.RouteMessage) laneKey {
+	return laneKey{src: m.Src, eventType: m.EventType}
+}
+
+func newWorkQueue() *workQueue {
+	return &workQueue{
+		delayed: &workHeap{less: func(a, b *workItem) bool {
+			return a.eligibleAt.Before(b.eligibleAt)
+		}},
+		ready: &workHeap{less: func(a, b *workItem) bool {
+			if a.detectedAt.Equal(b.detectedAt) {
+				return a.message.Sn.Cmp(b.message.Sn) < 0
+			}
+			return a.detectedAt.Before(b.detectedAt)
+		}},
+		queued:   make(map[string]*workItem),
+		ordered:  make(map[string]bool),
+		lanes:    make(map[laneKey][]*workItem),
+		inFlight: make(map[laneKey]bool),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// setStrictOrdering delivers the messages of the sources in sn order, the queued messages
+// are filed again by the new ordering
+func (q *workQueue) setStrictOrdering(srcs []string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.ordered = make(map[string]bool, len(srcs))
+	for _, src := range srcs {
+		q.ordered[src] = true
+	}
+	for key := range q.inFlight {
+		if !q.ordered[key.src] {
+			delete(q.inFlight, key)
+		}
+	}
+
+	q.delayed.items, q.ready.items = nil, nil
+	q.lanes = make(map[laneKey][]*workItem)
+	for _, item := range q.queued {
+		item.ready, item.scheduled = false, false
+		if q.isOrdered(item.message.Src) {
+			q.addToLane(item)
+		} else {
+			q.schedule(item)
+		}
+	}
+	q.signal()
+}
+
+// rebind moves the queued messages of a source runtime to the runtime replacing it
+func (q *workQueue) rebind(prev, chain *ChainRuntime) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, item := range q.queued {
+		if item.src == prev {
+			item.src = chain
+		}
+	}
+}
+
+// isOrdered reports whether the messages of the source are delivered in sn order
+func (q *workQueue) isOrdered(src string) bool {
+	return q.ordered[src]
+}
+
+// schedule moves the item to the delayed heap
+func (q *workQueue) schedule(item *workItem) {
+	item.scheduled = true
+	heap.Push(q.delayed, item)
+}
+
+// unschedule takes the item back from the delayed or the ready heap
+func (q *workQueue) unschedule(item *workItem) {
+	if item.ready {
+		heap.Remove(q.ready, item.index)
+	} else {
+		heap.Remove(q.delayed, item.index)
+	}
+	item.ready = false
+	item.scheduled = false
+}
+
+// addToLane inserts the item in its lane and schedules the lane head
+func (q *workQueue) addToLane(item *workItem) {
+	key := newLaneKey(item.message)
+	lane := q.lanes[key]
+	i := sort.Search(len(lane), func(i int) bool {
+		return lane[i].message.Sn.Cmp(item.message.Sn) > 0
+	})
+	lane = append(lane, nil)
+	copy(lane[i+1:], lane[i:])
+	lane[i] = item
+	q.lanes[key] = lane
+
+	// a lower sn replaces the scheduled head
+	if i == 0 && len(lane) > 1 && lane[1].scheduled {
+		q.unschedule(lane[1])
+	}
+	q.advance(key)
+}
+
+// advance schedules the head of the lane when nothing of the lane is in flight
+func (q *workQueue) advance(key laneKey) {
+	lane := q.lanes[key]
+	if len(lane) == 0 {
+		delete(q.lanes, key)
+		return
+	}
+	if q.inFlight[key] || lane[0].scheduled {
+		return
+	}
+	q.schedule(lane[0])
+}
+
+// push adds the message to the queue, a message already queued only moves its eligible time
+func (q *workQueue) push(item *workItem) {
+	q.mu.Lock()
+	if queued, ok := q.queued[item.key]; ok {
+		if !queued.ready && item.eligibleAt.Before(queued.eligibleAt) {
+			queued.eligibleAt = item.eligibleAt
+			if queued.scheduled {
+				heap.Fix(q.delayed, queued.index)
+			}
+		}
+		q.mu.Unlock()
+		q.signal()
+		return
+	}
+	q.queued[item.key] = item
+	if q.isOrdered(item.message.Src) {
+		q.addToLane(item)
+	} else {
+		q.schedule(item)
+	}
+	q.mu.Unlock()
+	q.signal()
+}
+
+// done releases the lane of an item handed out by pop
+func (q *workQueue) done(item *workItem) {
+	q.mu.Lock()
+	if !q.isOrdered(item.message.Src) {
+		q.mu.Unlock()
+		return
+	}
+	key := newLaneKey(item.message)
+	delete(q.inFlight, key)
+	q.advance(key)
+	q.mu.Unlock()
+	q.signal()
+}
+
+// signal wakes up a waiting worker
+func (q *workQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// promote moves the eligible messages to the ready heap and returns the wait until the next one
+func (q *workQueue) promote(now time.Time) time.Duration {
+	for {
+		next := q.delayed.peek()
+		if next == nil {
+			return -1
+		}
+		if next.eligibleAt.After(now) {
+			return next.eligibleAt.Sub(now)
+		}
+		heap.Pop(q.delayed)
+		next.ready = true
+		heap.Push(q.ready, next)
+	}
+}
+
+// pop blocks until a message is eligible or the context is done
+func (q *workQueue) pop(ctx context.Context) (*workItem, bool) {
+	return q.popUntil(ctx, time.Time{})
+}
+
+// popUntil blocks until a message is eligible, the context is done or the deadline passes,
+// a zero deadline never passes
+func (q *workQueue) popUntil(ctx context.Context, deadline time.Time) (*workItem, bool) {
+	for {
+		q.mu.Lock()
+		now := time.Now()
+		wait := q.promote(now)
+		if q.ready.Len() > 0 {
+			item := heap.Pop(q.ready).(*workItem)
+			item.scheduled = false
+			delete(q.queued, item.key)
+			if q.isOrdered(item.message.Src) {
+				// only the lane head is ever scheduled
+				key := newLaneKey(item.message)
+				q.lanes[key] = q.lanes[key][1:]
+				q.inFlight[key] = true
+			}
+			more := q.ready.Len() > 0
+			q.mu.Unlock()
+			if more {
+				// pass the wakeup on to the next worker
+				q.signal()
+			}
+			return item, true
+		}
+		q.mu.Unlock()
+
+		if !deadline.IsZero() {
+			left := deadline.Sub(now)
+			if left <= 0 {
+				return nil, false
+			}
+			if wait < 0 || left < wait {
+				wait = left
+			}
+		}
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if wait >= 0 {
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-q.wake:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// Len returns the number of queued messages
+func (q *workQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queued)
+}
@@ -0,0 +1,244 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRouteMessage(sn int64, detectedAt time.Time) *types.RouteMessage {
+	m := types.NewRouteMessage(&types.Message{
+		Src:       "icon",
+		Dst:       "archway",
+		Sn:        big.NewInt(sn),
+		EventType: "emitMessage",
+	})
+	m.DetectedAt = detectedAt
+	return m
+}
+
+func TestWorkQueue(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	t.Run("oldest message first", func(t *testing.T) {
+		q := newWorkQueue()
+		q.push(newWorkItem(nil, newTestRouteMessage(3, now)))
+		q.push(newWorkItem(nil, newTestRouteMessage(2, now)))
+		q.push(newWorkItem(nil, newTestRouteMessage(1, now.Add(time.Second))))
+
+		for _, sn := range []int64{2, 3, 1} {
+			item, ok := q.pop(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, big.NewInt(sn), item.message.Sn)
+		}
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("duplicate push", func(t *testing.T) {
+		q := newWorkQueue()
+		m := newTestRouteMessage(1, now)
+		q.push(newWorkItem(nil, m))
+		q.push(newWorkItem(nil, m))
+		assert.Equal(t, 1, q.Len())
+	})
+
+	t.Run("delayed message", func(t *testing.T) {
+		q := newWorkQueue()
+		m := newTestRouteMessage(1, now)
+		m.SetNextTry(100 * time.Millisecond)
+		q.push(newWorkItem(nil, m))
+
+		start := time.Now()
+		item, ok := q.pop(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, m, item.message)
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("wakeup on push", func(t *testing.T) {
+		q := newWorkQueue()
+		popped := make(chan *workItem)
+		go func() {
+			item, _ := q.pop(ctx)
+			popped <- item
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		m := newTestRouteMessage(1, now)
+		q.push(newWorkItem(nil, m))
+		select {
+		case item := <-popped:
+			assert.Equal(t, m, item.message)
+		case <-time.After(time.Second):
+			assert.Fail(t, "worker was not woken up")
+		}
+	})
+
+	t.Run("strict ordering", func(t *testing.T) {
+		q := newWorkQueue()
+		q.setStrictOrdering([]string{"icon"})
+		q.push(newWorkItem(nil, newTestRouteMessage(2, now)))
+		q.push(newWorkItem(nil, newTestRouteMessage(3, now.Add(-time.Second))))
+		q.push(newWorkItem(nil, newTestRouteMessage(1, now.Add(time.Second))))
+
+		item, ok := q.pop(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, big.NewInt(1), item.message.Sn)
+
+		// nothing else is handed out while sn 1 is in flight
+		waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		_, ok = q.pop(waitCtx)
+		waitCancel()
+		assert.False(t, ok)
+
+		// a failed try goes back ahead of the higher sn
+		item.message.SetNextTry(50 * time.Millisecond)
+		q.push(newWorkItem(nil, item.message))
+		q.done(item)
+
+		for _, sn := range []int64{1, 2, 3} {
+			item, ok := q.pop(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, big.NewInt(sn), item.message.Sn)
+			q.done(item)
+		}
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("strict ordering per event type", func(t *testing.T) {
+		q := newWorkQueue()
+		q.setStrictOrdering([]string{"icon"})
+		rollback := newTestRouteMessage(1, now)
+		rollback.EventType = "rollbackMessage"
+		q.push(newWorkItem(nil, newTestRouteMessage(2, now.Add(-time.Second))))
+		q.push(newWorkItem(nil, rollback))
+
+		// the sn of the event types are independent, both lane heads are handed out
+		first, ok := q.pop(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, big.NewInt(2), first.message.Sn)
+		second, ok := q.pop(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, rollback, second.message)
+
+		// a lower sn of the first event type still waits for its lane
+		q.push(newWorkItem(nil, newTestRouteMessage(1, now)))
+		waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		_, ok = q.pop(waitCtx)
+		waitCancel()
+		assert.False(t, ok)
+
+		q.done(second)
+		q.done(first)
+		item, ok := q.pop(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, big.NewInt(1), item.message.Sn)
+		q.done(item)
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("ordering changed", func(t *testing.T) {
+		q := newWorkQueue()
+		q.push(newWorkItem(nil, newTestRouteMessage(2, now)))
+		q.push(newWorkItem(nil, newTestRouteMessage(1, now.Add(time.Second))))
+
+		// the queued messages are filed in the lane of their source
+		q.setStrictOrdering([]string{"icon"})
+		item, ok := q.pop(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, big.NewInt(1), item.message.Sn)
+		waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		_, ok = q.pop(waitCtx)
+		waitCancel()
+		assert.False(t, ok)
+
+		// and handed out right away once the source is no longer ordered
+		q.setStrictOrdering(nil)
+		item, ok = q.pop(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, big.NewInt(2), item.message.Sn)
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("rebind", func(t *testing.T) {
+		q := newWorkQueue()
+		prev, chain, other := new(ChainRuntime), new(ChainRuntime), new(ChainRuntime)
+		q.push(newWorkItem(prev, newTestRouteMessage(1, now)))
+		q.push(newWorkItem(other, newTestRouteMessage(2, now.Add(time.Second))))
+
+		q.rebind(prev, chain)
+		item, ok := q.pop(ctx)
+		assert.True(t, ok)
+		assert.Same(t, chain, item.src)
+		item, ok = q.pop(ctx)
+		assert.True(t, ok)
+		assert.Same(t, other, item.src)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		q := newWorkQueue()
+		m := newTestRouteMessage(1, now)
+		m.SetNextTry(time.Second)
+		q.push(newWorkItem(nil, m))
+
+		start := time.Now()
+		_, ok := q.popUntil(ctx, start.Add(50*time.Millisecond))
+		assert.False(t, ok)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, 1, q.Len())
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		q := newWorkQueue()
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, ok := q.pop(ctx)
+		assert.False(t, ok)
+	})
+}
+
+func TestSchedulerConfig(t *testing.T) {
+	cfg := &SchedulerConfig{
+		Workers: 4,
+		Destinations: map[string]*DestinationConfig{
+			"archway": {MaxInFlight: 1, StrictOrdering: []string{"icon"}},
+			"icon":    {StrictOrdering: []string{"archway"}},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, 1, cfg.workers("archway"))
+	assert.Equal(t, 4, cfg.workers("icon"))
+	assert.Equal(t, []string{"icon"}, cfg.strictOrdering("archway"))
+	assert.Empty(t, cfg.strictOrdering("avalanche"))
+
+	var empty *SchedulerConfig
+	assert.Equal(t, DefaultRouteWorkers, empty.workers("archway"))
+	assert.Nil(t, empty.source("archway"))
+
+	cfg.Sources = map[string]*SourceConfig{
+		"0xa4b1.arbitrum": {Confirmations: 20},
+		"0x2105.base":     {Finalized: true},
+		"icon":            {},
+	}
+	assert.Equal(t, uint64(20), cfg.source("0xa4b1.arbitrum").Confirmations)
+	assert.True(t, cfg.source("0x2105.base").Finalized)
+	assert.Nil(t, cfg.source("icon"))
+
+	assert.Nil(t, cfg.batch("archway"))
+	cfg.Destinations["icon"].Batch = &BatchConfig{Size: 5}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, DefaultBatchWindow, cfg.batch("icon").window())
+	cfg.Destinations["icon"].Batch.Size = 1
+	assert.Error(t, cfg.Validate())
+	cfg.Destinations["icon"].Batch = nil
+
+	cfg.Destinations["archway"].MaxInFlight = -1
+	assert.Error(t, cfg.Validate())
+}
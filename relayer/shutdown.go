@@ -0,0 +1,128 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+)
+
+// DefaultShutdownTimeout is how long the shutdown waits for the in-flight routes to receive their receipts
+var DefaultShutdownTimeout = 30 * time.Second
+
+// ShutdownReport is what was left pending when the relayer stopped
+type ShutdownReport struct {
+	// Drained is false when the deadline passed before every in-flight route completed
+	Drained bool
+	// Messages is the number of unsettled cached messages per source nid and status
+	Messages map[string]map[types.MessageStatus]int
+	// PendingTxs is the number of broadcast transactions without a receipt per destination nid
+	PendingTxs map[string]uint64
+}
+
+// Pending is the total number of messages and transactions left behind
+func (s *ShutdownReport) Pending() int {
+	total := 0
+	for _, statuses := range s.Messages {
+		for _, count := range statuses {
+			total += count
+		}
+	}
+	for _, count := range s.PendingTxs {
+		total += int(count)
+	}
+	return total
+}
+
+// Shutdown stops the relayer from taking new work and waits up to timeout for the
+// in-flight routes to complete. Routes still waiting for a receipt after the deadline
+// are aborted, their pending transactions are reconciled on the next start.
+// The background goroutines are waited for and the heights processed are persisted before
+// returning, the db is left open for the caller to close.
+func (r *Relayer) Shutdown(timeout time.Duration) *ShutdownReport {
+	r.log.Info("shutting down relayer", zap.Duration("timeout", timeout))
+	r.stop()
+
+	report := &ShutdownReport{Drained: wait(&r.workers, timeout)}
+	if !report.Drained {
+		r.log.Warn("shutdown deadline exceeded, aborting in-flight routes")
+	}
+	r.cancelRoute()
+	// the aborted routes return as soon as their receipt wait is canceled
+	wait(&r.workers, time.Second)
+
+	chains := r.chainRuntimes()
+	// the listeners, the block processors and the services return once their ctx is done
+	for _, chain := range chains {
+		chain.running.Wait()
+	}
+	if !wait(&r.services, timeout) {
+		r.log.Warn("shutdown deadline exceeded, background tasks still running")
+	}
+	r.saveProcessedHeights(context.Background())
+
+	report.Messages = make(map[string]map[types.MessageStatus]int, len(chains))
+	report.PendingTxs = make(map[string]uint64, len(chains))
+	for nid, chain := range chains {
+		statuses := make(map[types.MessageStatus]int)
+		chain.MessageCache.Range(func(m *types.RouteMessage) bool {
+			statuses[m.GetStatus()]++
+			return true
+		})
+		if len(statuses) > 0 {
+			report.Messages[nid] = statuses
+		}
+		count, err := r.pendingTxStore.TotalCountByChain(nid)
+		if err != nil {
+			r.log.Error("failed to count pending transactions", zap.String("nid", nid), zap.Error(err))
+			continue
+		}
+		if count > 0 {
+			report.PendingTxs[nid] = count
+		}
+	}
+
+	if pending := report.Pending(); pending > 0 {
+		r.log.Warn("relayer stopped with pending work",
+			zap.Bool("drained", report.Drained),
+			zap.Any("messages", report.Messages),
+			zap.Any("pending_txs", report.PendingTxs),
+		)
+	} else {
+		r.log.Info("relayer stopped, nothing left pending")
+	}
+	return report
+}
+
+// saveProcessedHeights persists the height of the last block processed of every chain,
+// the listeners resume from it on the next start
+func (r *Relayer) saveProcessedHeights(ctx context.Context) {
+	for nid, chain := range r.chainRuntimes() {
+		height := chain.LastBlockHeight()
+		if height == 0 {
+			continue
+		}
+		if err := r.SaveBlockHeight(ctx, chain, height); err != nil {
+			r.log.Error("error occured when saving block height", zap.String("nid", nid), zap.Error(err))
+		}
+	}
+}
+
+// wait waits for the goroutines of wg to exit, it returns false on timeout
+func wait(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -21,6 +21,18 @@ const (
 	EventGetFee         Event = "GetFee"
 	EventSetFee         Event = "SetFee"
 	EventClaimFee       Event = "ClaimFee"
+	EventDLQList        Event = "DLQList"
+	EventDLQShow        Event = "DLQShow"
+	EventDLQRequeue     Event = "DLQRequeue"
+	EventDLQPurge       Event = "DLQPurge"
+	EventRejectedList   Event = "RejectedList"
+	EventChainStatus    Event = "ChainStatus"
+	EventReloadConfig   Event = "ReloadConfig"
+	EventRouteCosts     Event = "RouteCosts"
+	EventFeeAudit       Event = "FeeAudit"
+	EventFeeClaims      Event = "FeeClaims"
+	EventWalletStatus   Event = "WalletStatus"
+	EventHistory        Event = "History"
 )
 
 var (
@@ -134,6 +146,78 @@ func (c *Client) parseEvent(msg *Message) (interface{}, error) {
 			return nil, err
 		}
 		return res, nil
+	case EventDLQList:
+		res := new(ResDLQList)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventDLQShow:
+		res := new(ResDLQShow)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventDLQRequeue:
+		res := new(ResDLQRequeue)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventDLQPurge:
+		res := new(ResDLQPurge)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventRejectedList:
+		res := new(ResRejectedList)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventChainStatus:
+		res := new(ResChainStatus)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventReloadConfig:
+		res := new(ResReloadConfig)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventRouteCosts:
+		res := new(ResRouteCosts)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventFeeAudit:
+		res := new(ResFeeAudit)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventFeeClaims:
+		res := new(ResFeeClaims)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventWalletStatus:
+		res := new(ResWalletStatus)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case EventHistory:
+		res := new(ResHistory)
+		if err := jsoniter.Unmarshal(msg.Data, res); err != nil {
+			return nil, err
+		}
+		return res, nil
 	default:
 		return nil, ErrUnknownEvent
 	}
@@ -160,9 +244,9 @@ func (c *Client) GetBlock(chain string) ([]*ResGetBlock, error) {
 	return res, nil
 }
 
-// GetMessageList sends GetMessageList event to socket
-func (c *Client) GetMessageList(chain string, pagination *store.Pagination) (*ResMessageList, error) {
-	req := &ReqMessageList{Chain: chain, Pagination: pagination}
+// GetMessageList sends GetMessageList event to socket, the filter selects the messages of its source chain
+func (c *Client) GetMessageList(filter *store.MessageFilter, pagination *store.Pagination) (*ResMessageList, error) {
+	req := &ReqMessageList{Chain: filter.Src, Filter: filter, Pagination: pagination}
 	if err := c.send(EventGetMessageList, req); err != nil {
 		return nil, err
 	}
@@ -178,8 +262,8 @@ func (c *Client) GetMessageList(chain string, pagination *store.Pagination) (*Re
 }
 
 // RelayMessage sends RelayMessage event to socket
-func (c *Client) RelayMessage(chain string, height uint64, sn *big.Int) (*ResRelayMessage, error) {
-	req := &ReqRelayMessage{Chain: chain, Sn: sn, Height: height}
+func (c *Client) RelayMessage(chain, eventType string, height uint64, sn *big.Int) (*ResRelayMessage, error) {
+	req := &ReqRelayMessage{Chain: chain, Sn: sn, Height: height, EventType: eventType}
 	if err := c.send(EventRelayMessage, req); err != nil {
 		return nil, err
 	}
@@ -295,3 +379,206 @@ func (c *Client) ClaimFee(chain string) (*ResClaimFee, error) {
 	}
 	return res, nil
 }
+
+// DLQList sends DLQList event to socket
+func (c *Client) DLQList(chain string, pagination *store.Pagination) (*ResDLQList, error) {
+	req := &ReqDLQList{Chain: chain, Pagination: pagination}
+	if err := c.send(EventDLQList, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResDLQList)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// DLQShow sends DLQShow event to socket
+func (c *Client) DLQShow(chain, eventType string, sn *big.Int) (*ResDLQShow, error) {
+	req := &ReqDLQShow{Chain: chain, Sn: sn, EventType: eventType}
+	if err := c.send(EventDLQShow, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResDLQShow)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// DLQRequeue sends DLQRequeue event to socket
+func (c *Client) DLQRequeue(chain, eventType string, sn *big.Int) (*ResDLQRequeue, error) {
+	req := &ReqDLQRequeue{Chain: chain, Sn: sn, EventType: eventType}
+	if err := c.send(EventDLQRequeue, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResDLQRequeue)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// DLQPurge sends DLQPurge event to socket
+func (c *Client) DLQPurge(chain, eventType string, sn *big.Int) (*ResDLQPurge, error) {
+	req := &ReqDLQPurge{Chain: chain, Sn: sn, EventType: eventType}
+	if err := c.send(EventDLQPurge, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResDLQPurge)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// RejectedList sends RejectedList event to socket
+func (c *Client) RejectedList(chain string, pagination *store.Pagination) (*ResRejectedList, error) {
+	req := &ReqRejectedList{Chain: chain, Pagination: pagination}
+	if err := c.send(EventRejectedList, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResRejectedList)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// ChainStatus sends ChainStatus event to socket
+func (c *Client) ChainStatus(chain string) (*ResChainStatus, error) {
+	req := &ReqChainStatus{Chain: chain}
+	if err := c.send(EventChainStatus, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResChainStatus)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// ReloadConfig sends ReloadConfig event to socket
+func (c *Client) ReloadConfig() (*ResReloadConfig, error) {
+	if err := c.send(EventReloadConfig, &ReqReloadConfig{}); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResReloadConfig)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// RouteCosts sends RouteCosts event to socket
+func (c *Client) RouteCosts(chain string) (*ResRouteCosts, error) {
+	req := &ReqRouteCosts{Chain: chain}
+	if err := c.send(EventRouteCosts, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResRouteCosts)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// FeeAudit sends FeeAudit event to socket
+func (c *Client) FeeAudit(chain string, pagination *store.Pagination) (*ResFeeAudit, error) {
+	req := &ReqFeeAudit{Chain: chain, Pagination: pagination}
+	if err := c.send(EventFeeAudit, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResFeeAudit)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// FeeClaims sends FeeClaims event to socket
+func (c *Client) FeeClaims(chain string, pagination *store.Pagination) (*ResFeeClaims, error) {
+	req := &ReqFeeClaims{Chain: chain, Pagination: pagination}
+	if err := c.send(EventFeeClaims, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResFeeClaims)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// WalletStatus sends WalletStatus event to socket
+func (c *Client) WalletStatus(chain string) (*ResWalletStatus, error) {
+	req := &ReqWalletStatus{Chain: chain}
+	if err := c.send(EventWalletStatus, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResWalletStatus)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
+
+// History sends History event to socket
+func (c *Client) History(filter *store.HistoryFilter, pagination *store.Pagination) (*ResHistory, error) {
+	req := &ReqHistory{Filter: filter, Pagination: pagination}
+	if err := c.send(EventHistory, req); err != nil {
+		return nil, err
+	}
+	data, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := data.(*ResHistory)
+	if !ok {
+		return nil, ErrInvalidResponse(err)
+	}
+	return res, nil
+}
@@ -11,6 +11,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 
 	"github.com/icon-project/centralized-relay/relayer"
+	"github.com/icon-project/centralized-relay/relayer/store"
 	"github.com/icon-project/centralized-relay/relayer/types"
 )
 
@@ -100,7 +101,7 @@ func (s *Server) parseEvent(msg *Message) (*Message, error) {
 
 		if req.All {
 			for _, chain := range s.rly.GetAllChainsRuntime() {
-				blocks = append(blocks, &ResGetBlock{chain.Provider.NID(), chain.LastSavedHeight})
+				blocks = append(blocks, &ResGetBlock{chain.Provider.NID(), chain.LastSavedHeight()})
 			}
 			data, err := jsoniter.Marshal(blocks)
 			if err != nil {
@@ -126,16 +127,20 @@ func (s *Server) parseEvent(msg *Message) (*Message, error) {
 		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
 			return nil, err
 		}
-		store := s.rly.GetMessageStore()
-		messages, err := store.GetMessages(req.Chain, req.Pagination)
+		filter := req.Filter
+		if filter == nil {
+			filter = &store.MessageFilter{Src: req.Chain}
+		}
+		messageStore := s.rly.GetMessageStore()
+		messages, next, err := messageStore.ListMessages(filter, req.Pagination)
 		if err != nil {
 			return nil, err
 		}
-		total, err := store.TotalCountByChain(req.Chain)
+		total, err := messageStore.TotalCountByChain(filter.Src)
 		if err != nil {
 			return nil, err
 		}
-		data, err := jsoniter.Marshal(&ResMessageList{messages, int(total)})
+		data, err := jsoniter.Marshal(&ResMessageList{messages, int(total), next})
 		if err != nil {
 			return nil, err
 		}
@@ -176,7 +181,7 @@ func (s *Server) parseEvent(msg *Message) (*Message, error) {
 				return nil, err
 			}
 			for _, msg := range msgs {
-				src.MessageCache.Add(types.NewRouteMessage(msg))
+				s.rly.EnqueueMessage(src, types.NewRouteMessage(msg))
 			}
 			data, err := jsoniter.Marshal(&ResRelayMessage{types.NewRouteMessage(msgs[0])})
 			if err != nil {
@@ -185,13 +190,10 @@ func (s *Server) parseEvent(msg *Message) (*Message, error) {
 			return &Message{EventRelayMessage, data}, nil
 		}
 
-		store := s.rly.GetMessageStore()
-		key := &types.MessageKey{Src: req.Chain, Sn: req.Sn}
-		message, err := store.GetMessage(key)
+		message, err := s.rly.RelayStoredMessage(&types.MessageKey{Src: req.Chain, Sn: req.Sn, EventType: req.EventType})
 		if err != nil {
 			return nil, err
 		}
-		src.MessageCache.Add(message)
 		data, err := jsoniter.Marshal(&ResRelayMessage{message})
 		if err != nil {
 			return nil, err
@@ -275,6 +277,220 @@ func (s *Server) parseEvent(msg *Message) (*Message, error) {
 			return nil, err
 		}
 		return &Message{EventClaimFee, data}, nil
+	case EventDLQList:
+		req := new(ReqDLQList)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		store := s.rly.GetDeadLetterStore()
+		messages, err := store.GetDeadLetters(req.Chain, req.Pagination)
+		if err != nil {
+			return nil, err
+		}
+		var total uint
+		if req.Chain != "" {
+			total, err = store.TotalCountByChain(req.Chain)
+		} else {
+			total, err = store.TotalCount()
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResDLQList{messages, int(total)})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventDLQList, data}, nil
+	case EventDLQShow:
+		req := new(ReqDLQShow)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		message, err := s.rly.GetDeadLetterStore().GetDeadLetter(&types.MessageKey{Src: req.Chain, Sn: req.Sn, EventType: req.EventType})
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResDLQShow{message})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventDLQShow, data}, nil
+	case EventDLQRequeue:
+		req := new(ReqDLQRequeue)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		message, err := s.rly.RequeueDeadLetter(&types.MessageKey{Src: req.Chain, Sn: req.Sn, EventType: req.EventType})
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResDLQRequeue{message})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventDLQRequeue, data}, nil
+	case EventDLQPurge:
+		req := new(ReqDLQPurge)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		count, err := s.rly.PurgeDeadLetters(req.Chain, req.EventType, req.Sn)
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResDLQPurge{count})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventDLQPurge, data}, nil
+	case EventRejectedList:
+		req := new(ReqRejectedList)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		store := s.rly.GetRejectedStore()
+		messages, err := store.GetRejectedMessages(req.Chain, req.Pagination)
+		if err != nil {
+			return nil, err
+		}
+		var total uint
+		if req.Chain != "" {
+			total, err = store.TotalCountByChain(req.Chain)
+		} else {
+			total, err = store.TotalCount()
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResRejectedList{messages, int(total)})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventRejectedList, data}, nil
+	case EventChainStatus:
+		req := new(ReqChainStatus)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		statuses, err := s.rly.GetChainStatus(req.Chain)
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResChainStatus{statuses})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventChainStatus, data}, nil
+	case EventReloadConfig:
+		report, err := s.rly.Reload(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResReloadConfig{report})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventReloadConfig, data}, nil
+	case EventRouteCosts:
+		req := new(ReqRouteCosts)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResRouteCosts{s.rly.GetRouteCosts(req.Chain)})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventRouteCosts, data}, nil
+	case EventFeeAudit:
+		req := new(ReqFeeAudit)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		store := s.rly.GetFeeAuditStore()
+		changes, err := store.GetFeeChanges(req.Chain, req.Pagination)
+		if err != nil {
+			return nil, err
+		}
+		var total uint
+		if req.Chain != "" {
+			total, err = store.TotalCountByChain(req.Chain)
+		} else {
+			total, err = store.TotalCount()
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResFeeAudit{changes, int(total)})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventFeeAudit, data}, nil
+	case EventFeeClaims:
+		req := new(ReqFeeClaims)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		store := s.rly.GetFeeClaimStore()
+		claims, err := store.GetFeeClaims(req.Chain, req.Pagination)
+		if err != nil {
+			return nil, err
+		}
+		var total uint
+		if req.Chain != "" {
+			total, err = store.TotalCountByChain(req.Chain)
+		} else {
+			total, err = store.TotalCount()
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResFeeClaims{claims, int(total)})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventFeeClaims, data}, nil
+	case EventWalletStatus:
+		req := new(ReqWalletStatus)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		wallets, err := s.rly.GetWalletStatus(req.Chain)
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResWalletStatus{wallets})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventWalletStatus, data}, nil
+	case EventHistory:
+		req := new(ReqHistory)
+		if err := jsoniter.Unmarshal(msg.Data, req); err != nil {
+			return nil, err
+		}
+		filter := req.Filter
+		if filter == nil {
+			filter = new(store.HistoryFilter)
+		}
+		historyStore := s.rly.GetHistoryStore()
+		records, next, err := historyStore.ListRecords(filter, req.Pagination)
+		if err != nil {
+			return nil, err
+		}
+		var total uint
+		if filter.Src != "" {
+			total, err = historyStore.TotalCountByChain(filter.Src)
+		} else {
+			total, err = historyStore.TotalCount()
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := jsoniter.Marshal(&ResHistory{records, int(total), next})
+		if err != nil {
+			return nil, err
+		}
+		return &Message{EventHistory, data}, nil
 	default:
 		return nil, fmt.Errorf("invalid request")
 	}
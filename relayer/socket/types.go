@@ -23,6 +23,7 @@ type Server struct {
 
 type ReqMessageList struct {
 	Chain      string
+	Filter     *store.MessageFilter `json:",omitempty"`
 	Pagination *store.Pagination
 }
 
@@ -32,9 +33,10 @@ type ReqGetBlock struct {
 }
 
 type ReqRelayMessage struct {
-	Chain  string
-	Sn     *big.Int
-	Height uint64
+	Chain     string
+	Sn        *big.Int
+	Height    uint64
+	EventType string
 }
 
 type ReqMessageRemove struct {
@@ -53,6 +55,8 @@ type ResMessageRemove struct {
 type ResMessageList struct {
 	Messages []*types.RouteMessage
 	Total    int
+	// Next is the cursor of the next page, empty after the last one
+	Next []byte `json:",omitempty"`
 }
 
 type ResGetBlock struct {
@@ -119,3 +123,140 @@ type ReqClaimFee struct {
 type ResClaimFee struct {
 	Status string
 }
+
+// ReqDLQList sends DLQList event to socket
+type ReqDLQList struct {
+	Chain      string
+	Pagination *store.Pagination
+}
+
+// ResDLQList sends DLQList event to socket
+type ResDLQList struct {
+	Messages []*types.DeadLetter
+	Total    int
+}
+
+// ReqDLQShow sends DLQShow event to socket
+type ReqDLQShow struct {
+	Chain     string
+	Sn        *big.Int
+	EventType string
+}
+
+// ResDLQShow sends DLQShow event to socket
+type ResDLQShow struct {
+	*types.DeadLetter
+}
+
+// ReqDLQRequeue sends DLQRequeue event to socket
+type ReqDLQRequeue struct {
+	Chain     string
+	Sn        *big.Int
+	EventType string
+}
+
+// ResDLQRequeue sends DLQRequeue event to socket
+type ResDLQRequeue struct {
+	*types.RouteMessage
+}
+
+// ReqDLQPurge sends DLQPurge event to socket
+type ReqDLQPurge struct {
+	Chain     string
+	Sn        *big.Int
+	EventType string
+}
+
+// ResDLQPurge sends DLQPurge event to socket
+type ResDLQPurge struct {
+	Count int
+}
+
+// ReqRejectedList sends RejectedList event to socket
+type ReqRejectedList struct {
+	Chain      string
+	Pagination *store.Pagination
+}
+
+// ResRejectedList sends RejectedList event to socket
+type ResRejectedList struct {
+	Messages []*types.RejectedMessage
+	Total    int
+}
+
+// ReqChainStatus sends ChainStatus event to socket
+type ReqChainStatus struct {
+	Chain string
+}
+
+// ResChainStatus sends ChainStatus event to socket
+type ResChainStatus struct {
+	Chains []relayer.ChainStatus
+}
+
+// ReqReloadConfig sends ReloadConfig event to socket
+type ReqReloadConfig struct{}
+
+// ResReloadConfig sends ReloadConfig event to socket
+type ResReloadConfig struct {
+	*relayer.ReloadReport
+}
+
+// ReqRouteCosts sends RouteCosts event to socket
+type ReqRouteCosts struct {
+	Chain string
+}
+
+// ResRouteCosts sends RouteCosts event to socket
+type ResRouteCosts struct {
+	Routes []relayer.RouteCost
+}
+
+// ReqFeeAudit sends FeeAudit event to socket
+type ReqFeeAudit struct {
+	Chain      string
+	Pagination *store.Pagination
+}
+
+// ResFeeAudit sends FeeAudit event to socket
+type ResFeeAudit struct {
+	Changes []*types.FeeChange
+	Total   int
+}
+
+// ReqFeeClaims sends FeeClaims event to socket
+type ReqFeeClaims struct {
+	Chain      string
+	Pagination *store.Pagination
+}
+
+// ResFeeClaims sends FeeClaims event to socket
+type ResFeeClaims struct {
+	Claims []*types.FeeClaim
+	Total  int
+}
+
+// ReqWalletStatus sends WalletStatus event to socket
+type ReqWalletStatus struct {
+	Chain string
+}
+
+// ResWalletStatus sends WalletStatus event to socket
+type ResWalletStatus struct {
+	Wallets []relayer.WalletStatus
+}
+
+// ReqHistory sends History event to socket
+type ReqHistory struct {
+	Filter     *store.HistoryFilter `json:",omitempty"`
+	Pagination *store.Pagination
+}
+
+// ResHistory sends History event to socket
+type ResHistory struct {
+	Records []*types.RelayRecord
+	// Total is the number of records of the source chain of the filter, of all the chains without one
+	Total int
+	// Next is the cursor of the next page, empty after the last one
+	Next []byte `json:",omitempty"`
+}
@@ -0,0 +1,138 @@
+//go:build postgres
+
+package sqldb
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/icon-project/centralized-relay/relayer/store/storetest"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The postgres tests run with -tags postgres against the database of RELAYER_TEST_POSTGRES_DSN,
+// its store table is cleared
+func openPostgres(t *testing.T) *SQLDB {
+	dsn := os.Getenv("RELAYER_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("RELAYER_TEST_POSTGRES_DSN is not set")
+	}
+	db, err := NewSQLDB(DriverPostgres, dsn)
+	require.NoError(t, err)
+	return db
+}
+
+func lockPostgres(t *testing.T) *SQLDB {
+	db := openPostgres(t)
+	require.NoError(t, db.TryLock())
+	return db
+}
+
+func TestPostgres(t *testing.T) {
+	db := lockPostgres(t)
+	defer db.Close()
+
+	storetest.Run(t, db)
+}
+
+func TestPostgresLock(t *testing.T) {
+	db := lockPostgres(t)
+
+	// a second relayer waits for the lock
+	standby := openPostgres(t)
+	defer standby.Close()
+	assert.ErrorIs(t, standby.TryLock(), store.ErrLocked)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	assert.Error(t, standby.Lock(ctx))
+
+	locked := make(chan error, 1)
+	go func() { locked <- standby.Lock(context.Background()) }()
+	select {
+	case err := <-locked:
+		t.Fatalf("locked while held: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// and takes it over once the first one stops
+	require.NoError(t, db.Close())
+	select {
+	case err := <-locked:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lock was not taken over")
+	}
+}
+
+func TestPostgresLockLost(t *testing.T) {
+	interval := lockCheckInterval
+	lockCheckInterval = 50 * time.Millisecond
+	defer func() { lockCheckInterval = interval }()
+
+	db := lockPostgres(t)
+	defer db.Close()
+
+	var pid int
+	require.NoError(t, db.lock.QueryRowContext(context.Background(), `SELECT pg_backend_pid()`).Scan(&pid))
+	other := openPostgres(t)
+	defer other.Close()
+	_, err := other.db.Exec(`SELECT pg_terminate_backend($1)`, pid)
+	require.NoError(t, err)
+
+	select {
+	case <-db.Lost():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lost lock was not detected")
+	}
+	assert.ErrorIs(t, db.SetByKey([]byte("key"), []byte("value")), store.ErrLockLost)
+	assert.ErrorIs(t, db.NewBatch().Write(), store.ErrLockLost)
+}
+
+func TestPostgresIndexedWrites(t *testing.T) {
+	testdb := lockPostgres(t)
+	defer testdb.Close()
+	require.NoError(t, testdb.ClearStore())
+
+	db := store.WithIndexers(testdb, store.NewHistoryIndexer("history"))
+	historyStore := store.NewHistoryStore(db, "history")
+	now := time.Now()
+
+	// the counters stay exact under concurrent writes
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				sn := int64(i*10 + j)
+				assert.NoError(t, historyStore.StoreRecord(&types.RelayRecord{
+					MessageKey:  types.NewMessageKey(big.NewInt(sn), fmt.Sprintf("src-%d", i%2), "dst", "emitMessage"),
+					DeliveredAt: now.Add(time.Duration(sn) * time.Second),
+				}))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	count, err := historyStore.TotalCount()
+	require.NoError(t, err)
+	assert.Equal(t, uint(80), count)
+	count, err = historyStore.TotalCountByChain("src-1")
+	require.NoError(t, err)
+	assert.Equal(t, uint(40), count)
+
+	pruned, err := historyStore.Prune(time.Time{}, 30)
+	require.NoError(t, err)
+	assert.Equal(t, 50, pruned)
+	count, err = historyStore.TotalCount()
+	require.NoError(t, err)
+	assert.Equal(t, uint(30), count)
+}
@@ -0,0 +1,317 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/store"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ store.Store    = (*SQLDB)(nil)
+	_ store.TxWriter = (*SQLDB)(nil)
+	_ store.Locker   = (*SQLDB)(nil)
+)
+
+const (
+	// DriverPostgres stores the keys in a PostgreSQL compatible database, several relayers can share it
+	// but only the one holding its lock writes to it
+	DriverPostgres = "postgres"
+	// DriverSQLite stores the keys in an embedded SQLite database file
+	DriverSQLite = "sqlite3"
+)
+
+// table holds all the keys of the relayer
+const table = "relayer_store"
+
+// schema creates the table, the keys are compared bytewise by both databases
+var schema = map[string]string{
+	DriverPostgres: `CREATE TABLE IF NOT EXISTS ` + table + ` (k BYTEA PRIMARY KEY, v BYTEA NOT NULL)`,
+	DriverSQLite:   `CREATE TABLE IF NOT EXISTS ` + table + ` (k BLOB PRIMARY KEY, v BLOB NOT NULL)`,
+}
+
+const (
+	queryGet      = `SELECT v FROM ` + table + ` WHERE k = $1`
+	queryPage     = `SELECT k, v FROM ` + table + ` WHERE k >= $1 ORDER BY k LIMIT $2`
+	queryPageEnd  = `SELECT k, v FROM ` + table + ` WHERE k >= $1 AND k < $2 ORDER BY k LIMIT $3`
+	querySet      = `INSERT INTO ` + table + ` (k, v) VALUES ($1, $2) ON CONFLICT (k) DO UPDATE SET v = excluded.v`
+	queryDelete   = `DELETE FROM ` + table + ` WHERE k = $1`
+	queryClearAll = `DELETE FROM ` + table
+	// queryLock waits for the advisory lock of the table, held by the session until it ends
+	queryLock = `SELECT pg_advisory_lock(hashtext('` + table + `'))`
+	// queryTryLock takes the advisory lock of the table without waiting for it
+	queryTryLock = `SELECT pg_try_advisory_lock(hashtext('` + table + `'))`
+	queryPing    = `SELECT 1`
+)
+
+// lockCheckInterval is how often the session holding the lock is checked
+var lockCheckInterval = 5 * time.Second
+
+// execer runs the writes on the database or in a transaction
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+type SQLDB struct {
+	db     *sql.DB
+	driver string
+	// lock is the session holding the advisory lock of a postgres database
+	lock *sql.Conn
+	// lost is closed when the session of the lock ends, done when the database is closed
+	lost chan struct{}
+	done chan struct{}
+}
+
+// NewSQLDB opens the database of driver at dsn and creates the table of the keys
+func NewSQLDB(driver, dsn string) (*SQLDB, error) {
+	create, ok := schema[driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sql driver: %s", driver)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "sql.Open fail")
+	}
+	if driver == DriverSQLite {
+		// sqlite allows a single writer, the writes wait for each other rather than fail as busy
+		db.SetMaxOpenConns(1)
+	}
+	if _, err := db.Exec(create); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to create the store table")
+	}
+	return &SQLDB{db: db, driver: driver, lost: make(chan struct{}), done: make(chan struct{})}, nil
+}
+
+// Lock waits until the advisory lock of a postgres database is free and takes it on a connection
+// held until the database is closed, the relayers sharing the database wait for each other
+func (db *SQLDB) Lock(ctx context.Context) error {
+	if db.driver != DriverPostgres {
+		return nil
+	}
+	conn, err := db.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect")
+	}
+	if _, err := conn.ExecContext(ctx, queryLock); err != nil {
+		conn.Close()
+		return errors.Wrap(err, "failed to lock the database")
+	}
+	db.hold(conn)
+	return nil
+}
+
+// TryLock takes the advisory lock of a postgres database or returns store.ErrLocked
+func (db *SQLDB) TryLock() error {
+	if db.driver != DriverPostgres {
+		return nil
+	}
+	conn, err := db.db.Conn(context.Background())
+	if err != nil {
+		return errors.Wrap(err, "failed to connect")
+	}
+	var locked bool
+	if err := conn.QueryRowContext(context.Background(), queryTryLock).Scan(&locked); err != nil {
+		conn.Close()
+		return errors.Wrap(err, "failed to lock the database")
+	}
+	if !locked {
+		conn.Close()
+		return store.ErrLocked
+	}
+	db.hold(conn)
+	return nil
+}
+
+// Lost is closed when the session holding the lock ends, another relayer may hold it from then on
+func (db *SQLDB) Lost() <-chan struct{} {
+	return db.lost
+}
+
+// hold keeps the session of the lock and checks it until the database is closed
+func (db *SQLDB) hold(conn *sql.Conn) {
+	db.lock = conn
+	go func() {
+		ticker := time.NewTicker(lockCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-db.done:
+				return
+			case <-ticker.C:
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), lockCheckInterval)
+			_, err := conn.ExecContext(ctx, queryPing)
+			cancel()
+			if err != nil {
+				select {
+				case <-db.done:
+				default:
+					close(db.lost)
+				}
+				return
+			}
+		}
+	}()
+}
+
+// checkLock fails the writes once the lock is lost
+func (db *SQLDB) checkLock() error {
+	select {
+	case <-db.lost:
+		return store.ErrLockLost
+	default:
+		return nil
+	}
+}
+
+func (db *SQLDB) GetByKey(key []byte) ([]byte, error) {
+	var value []byte
+	err := db.db.QueryRow(queryGet, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return value, err
+}
+
+func (db *SQLDB) SetByKey(key []byte, value []byte) error {
+	if err := db.checkLock(); err != nil {
+		return err
+	}
+	return set(db.db, key, value)
+}
+
+func (db *SQLDB) DeleteByKey(key []byte) error {
+	if err := db.checkLock(); err != nil {
+		return err
+	}
+	_, err := db.db.Exec(queryDelete, key)
+	return err
+}
+
+// NewIterator reads the keys one page at a time so that no connection is held by the iteration
+func (db *SQLDB) NewIterator(prefix []byte) store.Iterator {
+	return store.NewPageIterator(prefix, db.readPage)
+}
+
+func (db *SQLDB) NewRangeIterator(start, limit []byte) store.Iterator {
+	return store.NewRangePageIterator(start, limit, db.readPage)
+}
+
+func (db *SQLDB) readPage(start, end []byte, limit int) ([]store.KeyValue, error) {
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if start == nil {
+		start = []byte{}
+	}
+	if end == nil {
+		rows, err = db.db.Query(queryPage, start, limit)
+	} else {
+		rows, err = db.db.Query(queryPageEnd, start, end, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := make([]store.KeyValue, 0, limit)
+	for rows.Next() {
+		var kv store.KeyValue
+		if err := rows.Scan(&kv.Key, &kv.Value); err != nil {
+			return nil, err
+		}
+		page = append(page, kv)
+	}
+	return page, rows.Err()
+}
+
+// NewBatch returns a batch written in a single transaction
+func (db *SQLDB) NewBatch() store.Batch {
+	return store.NewOpBatch(func(ops []store.Op) error {
+		return db.WriteTx(func(w store.IndexWriter) error {
+			for _, op := range ops {
+				var err error
+				if op.Delete {
+					err = w.DeleteByKey(op.Key)
+				} else {
+					err = w.SetByKey(op.Key, op.Value)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// WriteTx runs fn in a transaction, the reads of the writer are done in the transaction
+func (db *SQLDB) WriteTx(fn func(w store.IndexWriter) error) error {
+	if err := db.checkLock(); err != nil {
+		return err
+	}
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(&txWriter{tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db *SQLDB) ClearStore() error {
+	if err := db.checkLock(); err != nil {
+		return err
+	}
+	_, err := db.db.Exec(queryClearAll)
+	return err
+}
+
+// Close closes the database, the session of the advisory lock ends with it
+func (db *SQLDB) Close() error {
+	if db.lock != nil {
+		close(db.done)
+		db.lock.Close()
+	}
+	return db.db.Close()
+}
+
+// txWriter reads and writes the keys in a transaction
+type txWriter struct {
+	tx *sql.Tx
+}
+
+func (w *txWriter) GetByKey(key []byte) ([]byte, error) {
+	var value []byte
+	err := w.tx.QueryRow(queryGet, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	return value, err
+}
+
+func (w *txWriter) SetByKey(key []byte, value []byte) error {
+	return set(w.tx, key, value)
+}
+
+func (w *txWriter) DeleteByKey(key []byte) error {
+	_, err := w.tx.Exec(queryDelete, key)
+	return err
+}
+
+func set(e execer, key []byte, value []byte) error {
+	if value == nil {
+		value = []byte{}
+	}
+	_, err := e.Exec(querySet, key, value)
+	return err
+}
@@ -0,0 +1,22 @@
+package sqldb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/store/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLite(t *testing.T) {
+	db, err := NewSQLDB(DriverSQLite, "file:"+filepath.Join(t.TempDir(), "relayer.sqlite"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	storetest.Run(t, db)
+}
+
+func TestUnsupportedDriver(t *testing.T) {
+	_, err := NewSQLDB("mysql", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package store
+
+import "bytes"
+
+// Op is a write held by a batch
+type Op struct {
+	Key    []byte
+	Value  []byte
+	Delete bool
+}
+
+// OpBatch holds the writes of a batch in memory for the backends applying them in a
+// single transaction on Write
+type OpBatch struct {
+	ops    []Op
+	commit func(ops []Op) error
+}
+
+// NewOpBatch returns a batch applying its writes with commit
+func NewOpBatch(commit func(ops []Op) error) *OpBatch {
+	return &OpBatch{commit: commit}
+}
+
+func (b *OpBatch) SetByKey(key []byte, value []byte) error {
+	b.ops = append(b.ops, Op{Key: bytes.Clone(key), Value: bytes.Clone(value)})
+	return nil
+}
+
+func (b *OpBatch) DeleteByKey(key []byte) error {
+	b.ops = append(b.ops, Op{Key: bytes.Clone(key), Delete: true})
+	return nil
+}
+
+func (b *OpBatch) Len() int {
+	return len(b.ops)
+}
+
+func (b *OpBatch) Write() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	return b.commit(b.ops)
+}
+
+func (b *OpBatch) Reset() {
+	b.ops = b.ops[:0]
+}
+
+// PrefixEnd returns the first key after all the keys starting with prefix,
+// nil when there is none
+func PrefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// batchStore reads from the store and holds its writes in a batch
+type batchStore struct {
+	Store
+	batch Batch
+}
+
+// WithBatch returns a store writing to the batch, the stores created on it stage their writes
+// in the batch while their reads go to db and do not see the staged writes
+func WithBatch(db Store, batch Batch) Store {
+	return &batchStore{Store: db, batch: batch}
+}
+
+func (s *batchStore) SetByKey(key []byte, value []byte) error {
+	return s.batch.SetByKey(key, value)
+}
+
+func (s *batchStore) DeleteByKey(key []byte) error {
+	return s.batch.DeleteByKey(key)
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBatch(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/batch")
+	require.NoError(t, err)
+	defer testdb.Close()
+	require.NoError(t, testdb.ClearStore())
+
+	messageStore := NewMessageStore(testdb, "message")
+	finalityStore := NewFinalityStore(testdb, "finality")
+	m := types.NewRouteMessage(&types.Message{Src: "icon", Dst: "archway", Sn: big.NewInt(1)})
+	require.NoError(t, messageStore.StoreMessage(m))
+
+	batch := testdb.NewBatch()
+	db := WithBatch(testdb, batch)
+	txObj := types.NewTransactionObject(types.NewMessagekeyWithMessageHeight(m.MessageKey(), 10), "0x1", 12)
+	require.NoError(t, NewFinalityStore(db, "finality").StoreTxObject(txObj))
+	require.NoError(t, NewMessageStore(db, "message").DeleteMessage(m.MessageKey()))
+	assert.Equal(t, 2, batch.Len())
+
+	// the staged writes are not applied before Write
+	_, err = messageStore.GetMessage(m.MessageKey())
+	assert.NoError(t, err)
+	_, err = finalityStore.GetTxObject(m.MessageKey())
+	assert.Error(t, err)
+
+	require.NoError(t, batch.Write())
+	_, err = messageStore.GetMessage(m.MessageKey())
+	assert.ErrorIs(t, err, ErrNotFound)
+	got, err := finalityStore.GetTxObject(m.MessageKey())
+	require.NoError(t, err)
+	assert.Equal(t, "0x1", got.TxHash)
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, []byte("message."), PrefixEnd([]byte("message-")))
+	assert.Equal(t, []byte{0x01}, PrefixEnd([]byte{0x00, 0xff}))
+	assert.Nil(t, PrefixEnd([]byte{0xff, 0xff}))
+	assert.Nil(t, PrefixEnd(nil))
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// BlockRecordStore keeps the hash of the source blocks messages were emitted from,
+// until the blocks are final
+type BlockRecordStore struct {
+	db     Store
+	prefix string
+}
+
+func NewBlockRecordStore(db Store, prefix string) *BlockRecordStore {
+	return &BlockRecordStore{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+// block records are stored based on source nId
+func (bs *BlockRecordStore) StoreBlockRecord(nId string, record *types.BlockRecord) error {
+	if record == nil {
+		return fmt.Errorf("error while storing block record: record cannot be nil")
+	}
+
+	recordByte, err := bs.Encode(record)
+	if err != nil {
+		return err
+	}
+	return bs.db.SetByKey(bs.getKey(nId, record.Height), recordByte)
+}
+
+func (bs *BlockRecordStore) GetBlockRecord(nId string, height uint64) (*types.BlockRecord, error) {
+	v, err := bs.db.GetByKey(bs.getKey(nId, height))
+	if err != nil {
+		return nil, err
+	}
+
+	record := new(types.BlockRecord)
+	if err := bs.Decode(v, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetBlockRecords returns all the block records of the source nId
+func (bs *BlockRecordStore) GetBlockRecords(nId string) ([]*types.BlockRecord, error) {
+	var records []*types.BlockRecord
+
+	iter := bs.db.NewIterator(NewKey(bs.prefix).AppendString(nId))
+	defer iter.Release()
+
+	for iter.Next() {
+		record := new(types.BlockRecord)
+		if err := bs.Decode(iter.Value(), record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, iter.Error()
+}
+
+func (bs *BlockRecordStore) DeleteBlockRecord(nId string, height uint64) error {
+	return bs.db.DeleteByKey(bs.getKey(nId, height))
+}
+
+func (bs *BlockRecordStore) getKey(nId string, height uint64) []byte {
+	return NewKey(bs.prefix).AppendString(nId).AppendUint64(height)
+}
+
+func (bs *BlockRecordStore) Encode(d interface{}) ([]byte, error) {
+	return jsoniter.Marshal(d)
+}
+
+func (bs *BlockRecordStore) Decode(data []byte, output interface{}) error {
+	return jsoniter.Unmarshal(data, output)
+}
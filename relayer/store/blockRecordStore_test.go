@@ -0,0 +1,52 @@
+package store
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockRecordStore(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/blockrecord")
+	if err != nil {
+		assert.Fail(t, "error while creating test db ", err)
+	}
+	defer testdb.Close()
+
+	if err := testdb.ClearStore(); err != nil {
+		assert.Fail(t, "failed to clear db ", err)
+	}
+
+	blockRecordStore := NewBlockRecordStore(testdb, "blockrecord")
+	record := &types.BlockRecord{Height: 100, Hash: "0xabc"}
+	record.AddMessages([]*types.Message{
+		{Src: "0xa4b1.arbitrum", Dst: "0x1.icon", Sn: big.NewInt(1), EventType: "emitMessage"},
+		{Src: "0xa4b1.arbitrum", Dst: "0x1.icon", Sn: big.NewInt(1), EventType: "emitMessage"},
+	})
+
+	t.Run("store block record", func(t *testing.T) {
+		assert.Len(t, record.Messages, 1)
+		assert.NoError(t, blockRecordStore.StoreBlockRecord("0xa4b1.arbitrum", record))
+		assert.NoError(t, blockRecordStore.StoreBlockRecord("0xa4b1.arbitrum", &types.BlockRecord{Height: 101, Hash: "0xdef"}))
+
+		records, err := blockRecordStore.GetBlockRecords("0xa4b1.arbitrum")
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+	})
+
+	t.Run("get block record", func(t *testing.T) {
+		got, err := blockRecordStore.GetBlockRecord("0xa4b1.arbitrum", 100)
+		assert.NoError(t, err)
+		assert.Equal(t, "0xabc", got.Hash)
+		assert.Equal(t, big.NewInt(1), got.Messages[0].Sn)
+	})
+
+	t.Run("delete block record", func(t *testing.T) {
+		assert.NoError(t, blockRecordStore.DeleteBlockRecord("0xa4b1.arbitrum", 100))
+		_, err := blockRecordStore.GetBlockRecord("0xa4b1.arbitrum", 100)
+		assert.Error(t, err)
+	})
+}
@@ -20,7 +20,7 @@ func NewBlockStore(db Store, prefix string) *BlockStore {
 }
 
 func (bs *BlockStore) GetKey(nId string) []byte {
-	return GetKey([]string{bs.prefix, nId})
+	return NewKey(bs.prefix).AppendString(nId)
 }
 
 // StoreBlock stores block number per domainID into blockstore
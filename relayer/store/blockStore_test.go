@@ -4,14 +4,13 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/icon-project/centralized-relay/relayer/lvldb"
 	"github.com/stretchr/testify/assert"
 )
 
 var testDBName = "/tmp/testdb3"
 
 func TestBlockStore(t *testing.T) {
-	testdb, err := lvldb.NewLvlDB(testDBName)
+	testdb, err := newTestDB(testDBName)
 	fmt.Println(testDBName)
 	if err != nil {
 		assert.Fail(t, "error while creating test db ", err)
@@ -26,7 +25,7 @@ func TestBlockStore(t *testing.T) {
 	blockStore := NewBlockStore(testdb, prefix)
 
 	key := blockStore.GetKey(nId)
-	assert.Equal(t, key, []byte("block-icon"), "key computation looks good")
+	assert.Equal(t, key, []byte("\x02\x05block\x04icon"), "key computation looks good")
 
 	saveHeight := uint64(2000)
 	if err := blockStore.StoreBlock(saveHeight, nId); err != nil {
@@ -2,6 +2,7 @@ package store
 
 import "strings"
 
+// GetKey builds the keys of the legacy schema, the components joined with "-"
 func GetKey(keys []string) []byte {
 	return []byte(strings.Join(keys, "-"))
 }
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// DeadLetterStore keeps the messages that exhausted their retries
+type DeadLetterStore struct {
+	db     Store
+	prefix string
+}
+
+func NewDeadLetterStore(db Store, prefix string) *DeadLetterStore {
+	return &DeadLetterStore{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+func (ds *DeadLetterStore) TotalCount() (uint, error) {
+	return ds.getCountByKey(NewKey(ds.prefix))
+}
+
+func (ds *DeadLetterStore) TotalCountByChain(nId string) (uint, error) {
+	return ds.getCountByKey(NewKey(ds.prefix).AppendString(nId))
+}
+
+func (ds *DeadLetterStore) getCountByKey(key []byte) (uint, error) {
+	iter := ds.db.NewIterator(key)
+	var count uint
+	for iter.Next() {
+		count++
+	}
+	iter.Release()
+	err := iter.Error()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// dead letters are stored based on source nId
+func (ds *DeadLetterStore) StoreDeadLetter(message *types.DeadLetter) error {
+	if message == nil {
+		return fmt.Errorf("error while storing dead letter: message cannot be nil")
+	}
+
+	key := ds.getKey(message.MessageKey())
+
+	msgByte, err := ds.Encode(message)
+	if err != nil {
+		return err
+	}
+	return ds.db.SetByKey(key, msgByte)
+}
+
+func (ds *DeadLetterStore) GetDeadLetter(messageKey *types.MessageKey) (*types.DeadLetter, error) {
+	key, err := ds.findKey(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	v, err := ds.db.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := new(types.DeadLetter)
+	if err := ds.Decode(v, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// GetDeadLetters returns the dead letters of the source nId, all of them when nId is empty
+func (ds *DeadLetterStore) GetDeadLetters(nId string, p *Pagination) ([]*types.DeadLetter, error) {
+	var messages []*types.DeadLetter
+
+	key := NewKey(ds.prefix)
+	if nId != "" {
+		key = key.AppendString(nId)
+	}
+	iter := ds.db.NewIterator(key)
+	defer iter.Release()
+
+	for i := uint(0); iter.Next(); i++ {
+		if !p.All && i < p.Offset {
+			continue
+		}
+		msg := new(types.DeadLetter)
+		if err := ds.Decode(iter.Value(), msg); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+		if !p.All && uint(len(messages)) == p.Limit {
+			break
+		}
+	}
+	return messages, iter.Error()
+}
+
+func (ds *DeadLetterStore) DeleteDeadLetter(messageKey *types.MessageKey) error {
+	key, err := ds.findKey(messageKey)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ds.db.DeleteByKey(key)
+}
+
+func (ds *DeadLetterStore) getKey(messageKey *types.MessageKey) []byte {
+	return NewKey(ds.prefix).AppendString(messageKey.Src).AppendString(messageKey.EventType).AppendBigInt(messageKey.Sn)
+}
+
+// findKey returns the key of the dead letter, it is looked up by sn when the event type is not known
+func (ds *DeadLetterStore) findKey(messageKey *types.MessageKey) ([]byte, error) {
+	if messageKey.EventType != "" {
+		return ds.getKey(messageKey), nil
+	}
+	return findBySn(ds.db, NewKey(ds.prefix).AppendString(messageKey.Src), messageKey.Sn)
+}
+
+func (ds *DeadLetterStore) Encode(d interface{}) ([]byte, error) {
+	return jsoniter.Marshal(d)
+}
+
+func (ds *DeadLetterStore) Decode(data []byte, output interface{}) error {
+	return jsoniter.Unmarshal(data, output)
+}
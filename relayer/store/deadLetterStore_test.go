@@ -0,0 +1,59 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterStore(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/deadletter")
+	if err != nil {
+		assert.Fail(t, "error while creating test db ", err)
+	}
+	defer testdb.Close()
+
+	if err := testdb.ClearStore(); err != nil {
+		assert.Fail(t, "failed to clear db ", err)
+	}
+
+	deadLetterStore := NewDeadLetterStore(testdb, "dlq")
+	for i := int64(1); i <= 3; i++ {
+		m := types.NewRouteMessage(&types.Message{Src: "icon", Dst: "archway", Sn: big.NewInt(i), EventType: "emitMessage"})
+		m.AddAttempt("0xabc", fmt.Errorf("out of gas"))
+		assert.NoError(t, deadLetterStore.StoreDeadLetter(types.NewDeadLetter(m)))
+	}
+	key := types.NewMessageKey(big.NewInt(2), "icon", "archway", "emitMessage")
+
+	t.Run("list dead letters", func(t *testing.T) {
+		count, err := deadLetterStore.TotalCountByChain("icon")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), count)
+
+		messages, err := deadLetterStore.GetDeadLetters("icon", NewPagination().WithLimit(2))
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+
+		messages, err = deadLetterStore.GetDeadLetters("", NewPagination().GetAll())
+		assert.NoError(t, err)
+		assert.Len(t, messages, 3)
+	})
+
+	t.Run("get dead letter", func(t *testing.T) {
+		m, err := deadLetterStore.GetDeadLetter(key)
+		assert.NoError(t, err)
+		assert.Equal(t, "out of gas", m.LastError)
+		assert.Len(t, m.Attempts, 1)
+		assert.Equal(t, "0xabc", m.Attempts[0].TxHash)
+	})
+
+	t.Run("delete dead letter", func(t *testing.T) {
+		assert.NoError(t, deadLetterStore.DeleteDeadLetter(key))
+		_, err := deadLetterStore.GetDeadLetter(key)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// DeliveredStore indexes the delivered messages so that a message emitted
+// again is dropped without querying the destination
+type DeliveredStore struct {
+	db     Store
+	prefix string
+}
+
+func NewDeliveredStore(db Store, prefix string) *DeliveredStore {
+	return &DeliveredStore{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+func (ds *DeliveredStore) TotalCount() (uint, error) {
+	return ds.getCountByKey(NewKey(ds.prefix))
+}
+
+func (ds *DeliveredStore) TotalCountByChain(nId string) (uint, error) {
+	return ds.getCountByKey(NewKey(ds.prefix).AppendString(nId))
+}
+
+func (ds *DeliveredStore) getCountByKey(key []byte) (uint, error) {
+	iter := ds.db.NewIterator(key)
+	var count uint
+	for iter.Next() {
+		count++
+	}
+	iter.Release()
+	err := iter.Error()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// delivered messages are stored based on source nId
+func (ds *DeliveredStore) StoreDelivered(message *types.DeliveredMessage) error {
+	if message == nil {
+		return fmt.Errorf("error while storing delivered message: message cannot be nil")
+	}
+
+	msgByte, err := ds.Encode(message)
+	if err != nil {
+		return err
+	}
+	return ds.db.SetByKey(ds.getKey(message.MessageKey), msgByte)
+}
+
+func (ds *DeliveredStore) GetDelivered(messageKey *types.MessageKey) (*types.DeliveredMessage, error) {
+	v, err := ds.db.GetByKey(ds.getKey(messageKey))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := new(types.DeliveredMessage)
+	if err := ds.Decode(v, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// IsDelivered reports whether the message is indexed as delivered
+func (ds *DeliveredStore) IsDelivered(messageKey *types.MessageKey) bool {
+	_, err := ds.db.GetByKey(ds.getKey(messageKey))
+	return err == nil
+}
+
+func (ds *DeliveredStore) DeleteDelivered(messageKey *types.MessageKey) error {
+	return ds.db.DeleteByKey(ds.getKey(messageKey))
+}
+
+func (ds *DeliveredStore) getKey(messageKey *types.MessageKey) []byte {
+	return NewKey(ds.prefix).AppendString(messageKey.Src).AppendString(messageKey.EventType).AppendBigInt(messageKey.Sn)
+}
+
+func (ds *DeliveredStore) Encode(d interface{}) ([]byte, error) {
+	return jsoniter.Marshal(d)
+}
+
+func (ds *DeliveredStore) Decode(data []byte, output interface{}) error {
+	return jsoniter.Unmarshal(data, output)
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveredStore(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/delivered")
+	if err != nil {
+		assert.Fail(t, "error while creating test db ", err)
+	}
+	defer testdb.Close()
+
+	if err := testdb.ClearStore(); err != nil {
+		assert.Fail(t, "failed to clear db ", err)
+	}
+
+	deliveredStore := NewDeliveredStore(testdb, "delivered")
+	key := types.NewMessageKey(big.NewInt(1), "icon", "archway", "emitMessage")
+
+	t.Run("store delivered message", func(t *testing.T) {
+		assert.False(t, deliveredStore.IsDelivered(key))
+		assert.NoError(t, deliveredStore.StoreDelivered(types.NewDeliveredMessage(key, "0xabc")))
+		assert.True(t, deliveredStore.IsDelivered(key))
+
+		// another event with the same sn is not delivered
+		assert.False(t, deliveredStore.IsDelivered(types.NewMessageKey(big.NewInt(1), "icon", "archway", "callMessage")))
+
+		count, err := deliveredStore.TotalCountByChain("icon")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), count)
+	})
+
+	t.Run("get delivered message", func(t *testing.T) {
+		got, err := deliveredStore.GetDelivered(key)
+		assert.NoError(t, err)
+		assert.Equal(t, "0xabc", got.TxHash)
+		assert.Equal(t, key.Dst, got.Dst)
+	})
+
+	t.Run("delete delivered message", func(t *testing.T) {
+		assert.NoError(t, deliveredStore.DeleteDelivered(key))
+		assert.False(t, deliveredStore.IsDelivered(key))
+	})
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// FeeAuditStore keeps the fee changes of the fee adjuster, in the order they were made per source nId
+type FeeAuditStore struct {
+	db     Store
+	prefix string
+}
+
+func NewFeeAuditStore(db Store, prefix string) *FeeAuditStore {
+	return &FeeAuditStore{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+func (fs *FeeAuditStore) TotalCount() (uint, error) {
+	return fs.getCountByKey(NewKey(fs.prefix))
+}
+
+func (fs *FeeAuditStore) TotalCountByChain(nId string) (uint, error) {
+	return fs.getCountByKey(NewKey(fs.prefix).AppendString(nId))
+}
+
+func (fs *FeeAuditStore) getCountByKey(key []byte) (uint, error) {
+	iter := fs.db.NewIterator(key)
+	var count uint
+	for iter.Next() {
+		count++
+	}
+	iter.Release()
+	err := iter.Error()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (fs *FeeAuditStore) StoreFeeChange(change *types.FeeChange) error {
+	if change == nil {
+		return fmt.Errorf("error while storing fee change: change cannot be nil")
+	}
+
+	data, err := fs.Encode(change)
+	if err != nil {
+		return err
+	}
+	// the big endian timestamp keeps the changes of a chain in time order
+	return fs.db.SetByKey(NewKey(fs.prefix).AppendString(change.Src).AppendUint64(uint64(change.At.UnixNano())).AppendString(change.Dst), data)
+}
+
+// GetFeeChanges returns the fee changes of the source nId, all of them when nId is empty
+func (fs *FeeAuditStore) GetFeeChanges(nId string, p *Pagination) ([]*types.FeeChange, error) {
+	var changes []*types.FeeChange
+
+	key := NewKey(fs.prefix)
+	if nId != "" {
+		key = key.AppendString(nId)
+	}
+	iter := fs.db.NewIterator(key)
+	defer iter.Release()
+
+	for i := uint(0); iter.Next(); i++ {
+		if !p.All && i < p.Offset {
+			continue
+		}
+		change := new(types.FeeChange)
+		if err := fs.Decode(iter.Value(), change); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+		if !p.All && uint(len(changes)) == p.Limit {
+			break
+		}
+	}
+	return changes, iter.Error()
+}
+
+func (fs *FeeAuditStore) Encode(d interface{}) ([]byte, error) {
+	return jsoniter.Marshal(d)
+}
+
+func (fs *FeeAuditStore) Decode(data []byte, output interface{}) error {
+	return jsoniter.Unmarshal(data, output)
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeAuditStore(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/feeaudit")
+	if err != nil {
+		assert.Fail(t, "error while creating test db ", err)
+	}
+	defer testdb.Close()
+
+	if err := testdb.ClearStore(); err != nil {
+		assert.Fail(t, "failed to clear db ", err)
+	}
+
+	feeAuditStore := NewFeeAuditStore(testdb, "feeaudit")
+	now := time.Now()
+
+	t.Run("store fee changes", func(t *testing.T) {
+		assert.NoError(t, feeAuditStore.StoreFeeChange(&types.FeeChange{Src: "icon", Dst: "archway", OldMsgFee: 10, NewMsgFee: 12, At: now.Add(time.Second)}))
+		assert.NoError(t, feeAuditStore.StoreFeeChange(&types.FeeChange{Src: "icon", Dst: "avalanche", OldMsgFee: 5, NewMsgFee: 4, DryRun: true, At: now}))
+		assert.NoError(t, feeAuditStore.StoreFeeChange(&types.FeeChange{Src: "avalanche", Dst: "icon", OldMsgFee: 1, NewMsgFee: 2, Error: "out of gas", At: now}))
+		assert.Error(t, feeAuditStore.StoreFeeChange(nil))
+
+		count, err := feeAuditStore.TotalCount()
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), count)
+	})
+
+	t.Run("list fee changes in order", func(t *testing.T) {
+		changes, err := feeAuditStore.GetFeeChanges("icon", NewPagination().GetAll())
+		assert.NoError(t, err)
+		assert.Len(t, changes, 2)
+		assert.Equal(t, "avalanche", changes[0].Dst)
+		assert.True(t, changes[0].DryRun)
+		assert.Equal(t, uint64(12), changes[1].NewMsgFee)
+
+		changes, err = feeAuditStore.GetFeeChanges("", NewPagination().WithLimit(2).WithOffset(2))
+		assert.NoError(t, err)
+		// the shorter nIds come first
+		assert.Len(t, changes, 1)
+		assert.Equal(t, "avalanche", changes[0].Src)
+	})
+}
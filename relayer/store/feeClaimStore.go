@@ -0,0 +1,110 @@
+package store
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// FeeClaimStore keeps the fee claims of the fee claimer, in the order they were made per nId
+type FeeClaimStore struct {
+	db     Store
+	prefix string
+}
+
+func NewFeeClaimStore(db Store, prefix string) *FeeClaimStore {
+	return &FeeClaimStore{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+func (cs *FeeClaimStore) TotalCount() (uint, error) {
+	return cs.getCountByKey(NewKey(cs.prefix))
+}
+
+func (cs *FeeClaimStore) TotalCountByChain(nId string) (uint, error) {
+	return cs.getCountByKey(NewKey(cs.prefix).AppendString(nId))
+}
+
+func (cs *FeeClaimStore) getCountByKey(key []byte) (uint, error) {
+	iter := cs.db.NewIterator(key)
+	var count uint
+	for iter.Next() {
+		count++
+	}
+	iter.Release()
+	err := iter.Error()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (cs *FeeClaimStore) StoreFeeClaim(claim *types.FeeClaim) error {
+	if claim == nil {
+		return fmt.Errorf("error while storing fee claim: claim cannot be nil")
+	}
+
+	data, err := cs.Encode(claim)
+	if err != nil {
+		return err
+	}
+	// the big endian timestamp keeps the claims of a chain in time order
+	return cs.db.SetByKey(NewKey(cs.prefix).AppendString(claim.Nid).AppendUint64(uint64(claim.At.UnixNano())), data)
+}
+
+// GetFeeClaims returns the fee claims of the nId, all of them when nId is empty
+func (cs *FeeClaimStore) GetFeeClaims(nId string, p *Pagination) ([]*types.FeeClaim, error) {
+	var claims []*types.FeeClaim
+
+	key := NewKey(cs.prefix)
+	if nId != "" {
+		key = key.AppendString(nId)
+	}
+	iter := cs.db.NewIterator(key)
+	defer iter.Release()
+
+	for i := uint(0); iter.Next(); i++ {
+		if !p.All && i < p.Offset {
+			continue
+		}
+		claim := new(types.FeeClaim)
+		if err := cs.Decode(iter.Value(), claim); err != nil {
+			return nil, err
+		}
+		claims = append(claims, claim)
+		if !p.All && uint(len(claims)) == p.Limit {
+			break
+		}
+	}
+	return claims, iter.Error()
+}
+
+// LastFeeClaim returns the latest successful claim of the nId, nil when there is none
+func (cs *FeeClaimStore) LastFeeClaim(nId string) (*types.FeeClaim, error) {
+	iter := cs.db.NewIterator(NewKey(cs.prefix).AppendString(nId))
+	defer iter.Release()
+
+	var last *types.FeeClaim
+	for iter.Next() {
+		claim := new(types.FeeClaim)
+		if err := cs.Decode(iter.Value(), claim); err != nil {
+			return nil, err
+		}
+		if claim.Error == "" {
+			last = claim
+		}
+	}
+	return last, iter.Error()
+}
+
+func (cs *FeeClaimStore) Encode(d interface{}) ([]byte, error) {
+	return jsoniter.Marshal(d)
+}
+
+func (cs *FeeClaimStore) Decode(data []byte, output interface{}) error {
+	return jsoniter.Unmarshal(data, output)
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeClaimStore(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/feeclaim")
+	if err != nil {
+		assert.Fail(t, "error while creating test db ", err)
+	}
+	defer testdb.Close()
+
+	if err := testdb.ClearStore(); err != nil {
+		assert.Fail(t, "failed to clear db ", err)
+	}
+
+	feeClaimStore := NewFeeClaimStore(testdb, "feeclaim")
+	now := time.Now()
+
+	t.Run("no claim", func(t *testing.T) {
+		last, err := feeClaimStore.LastFeeClaim("icon")
+		assert.NoError(t, err)
+		assert.Nil(t, last)
+	})
+
+	t.Run("store fee claims", func(t *testing.T) {
+		assert.NoError(t, feeClaimStore.StoreFeeClaim(&types.FeeClaim{Nid: "icon", Amount: big.NewInt(10), At: now}))
+		assert.NoError(t, feeClaimStore.StoreFeeClaim(&types.FeeClaim{Nid: "icon", Amount: big.NewInt(20), Treasury: "hx1", At: now.Add(time.Hour)}))
+		assert.NoError(t, feeClaimStore.StoreFeeClaim(&types.FeeClaim{Nid: "icon", Amount: big.NewInt(30), Error: "out of step", At: now.Add(2 * time.Hour)}))
+		// above the range of uint64
+		amount, _ := new(big.Int).SetString("25000000000000000000", 10)
+		assert.NoError(t, feeClaimStore.StoreFeeClaim(&types.FeeClaim{Nid: "archway", Amount: amount, At: now}))
+		assert.Error(t, feeClaimStore.StoreFeeClaim(nil))
+
+		count, err := feeClaimStore.TotalCountByChain("icon")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), count)
+	})
+
+	t.Run("last successful claim", func(t *testing.T) {
+		last, err := feeClaimStore.LastFeeClaim("icon")
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(20), last.Amount)
+		assert.Equal(t, "hx1", last.Treasury)
+	})
+
+	t.Run("list fee claims", func(t *testing.T) {
+		claims, err := feeClaimStore.GetFeeClaims("", NewPagination().GetAll())
+		assert.NoError(t, err)
+		// the shorter nIds come first
+		assert.Len(t, claims, 4)
+		assert.Equal(t, "archway", claims[3].Nid)
+		assert.Equal(t, "25000000000000000000", claims[3].Amount.String())
+	})
+}
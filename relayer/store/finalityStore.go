@@ -21,11 +21,11 @@ func NewFinalityStore(db Store, prefix string) *FinalityStore {
 }
 
 func (ms *FinalityStore) TotalCount() (uint64, error) {
-	return ms.getCountByKey(GetKey([]string{ms.prefix}))
+	return ms.getCountByKey(NewKey(ms.prefix))
 }
 
 func (ms *FinalityStore) TotalCountByChain(nId string) (uint64, error) {
-	return ms.getCountByKey(GetKey([]string{ms.prefix, nId}))
+	return ms.getCountByKey(NewKey(ms.prefix).AppendString(nId))
 }
 
 func (ms *FinalityStore) getCountByKey(key []byte) (uint64, error) {
@@ -48,7 +48,7 @@ func (ms *FinalityStore) StoreTxObject(message *types.TransactionObject) error {
 		return fmt.Errorf("error while storingMessage: message cannot be nil")
 	}
 
-	key := GetKey([]string{ms.prefix, message.Dst, message.Sn.String()})
+	key := ms.getKey(message.MessageKey)
 
 	msgByte, err := ms.Encode(message)
 	if err != nil {
@@ -58,7 +58,7 @@ func (ms *FinalityStore) StoreTxObject(message *types.TransactionObject) error {
 }
 
 func (ms *FinalityStore) GetTxObject(messageKey *types.MessageKey) (*types.TransactionObject, error) {
-	v, err := ms.db.GetByKey(GetKey([]string{ms.prefix, messageKey.Dst, messageKey.Sn.String()}))
+	v, err := ms.db.GetByKey(ms.getKey(messageKey))
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +73,7 @@ func (ms *FinalityStore) GetTxObject(messageKey *types.MessageKey) (*types.Trans
 func (ms *FinalityStore) GetTxObjects(nId string, p *Pagination) ([]*types.TransactionObject, error) {
 	var messages []*types.TransactionObject
 
-	iter := ms.db.NewIterator(GetKey([]string{ms.prefix, nId}))
+	iter := ms.db.NewIterator(NewKey(ms.prefix).AppendString(nId))
 	defer iter.Release()
 
 	if p.All {
@@ -97,7 +97,11 @@ func (ms *FinalityStore) GetTxObjects(nId string, p *Pagination) ([]*types.Trans
 }
 
 func (ms *FinalityStore) DeleteTxObject(messageKey *types.MessageKey) error {
-	return ms.db.DeleteByKey(GetKey([]string{ms.prefix, messageKey.Dst, messageKey.Sn.String()}))
+	return ms.db.DeleteByKey(ms.getKey(messageKey))
+}
+
+func (ms *FinalityStore) getKey(messageKey *types.MessageKey) []byte {
+	return NewKey(ms.prefix).AppendString(messageKey.Dst).AppendString(messageKey.Src).AppendString(messageKey.EventType).AppendBigInt(messageKey.Sn)
 }
 
 func (ms *FinalityStore) Encode(d interface{}) ([]byte, error) {
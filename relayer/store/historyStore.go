@@ -0,0 +1,307 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// HistoryStore archives a record of every delivered message in the order of delivery, the
+// records are only appended and removed by the retention policy. The records are keyed by
+// their delivery time followed by the src, event type and sn of the message.
+type HistoryStore struct {
+	db     Store
+	prefix string
+	index  *historyIndexer
+}
+
+func NewHistoryStore(db Store, prefix string) *HistoryStore {
+	return &HistoryStore{
+		db:     db,
+		prefix: prefix,
+		index:  newHistoryIndexer(prefix),
+	}
+}
+
+// NewHistoryIndexer returns the indexer maintaining the counters and the message lookup
+// of the history store of prefix, the store must be given a db kept by it with WithIndexers
+func NewHistoryIndexer(prefix string) Indexer {
+	return newHistoryIndexer(prefix)
+}
+
+// HistoryFilter selects the history records, its zero fields match every record
+type HistoryFilter struct {
+	Src       string `json:",omitempty"`
+	Dst       string `json:",omitempty"`
+	EventType string `json:",omitempty"`
+	// Sn selects the deliveries of a message, it is looked up directly along with Src
+	Sn *big.Int `json:",omitempty"`
+	// Since and Until select the records delivered from Since and before Until
+	Since time.Time `json:",omitempty"`
+	Until time.Time `json:",omitempty"`
+}
+
+func (f *HistoryFilter) match(r *types.RelayRecord) bool {
+	return (f.Src == "" || r.Src == f.Src) &&
+		(f.Dst == "" || r.Dst == f.Dst) &&
+		(f.EventType == "" || r.EventType == f.EventType) &&
+		(f.Sn == nil || (r.Sn != nil && r.Sn.Cmp(f.Sn) == 0)) &&
+		(f.Since.IsZero() || !r.DeliveredAt.Before(f.Since)) &&
+		(f.Until.IsZero() || r.DeliveredAt.Before(f.Until))
+}
+
+// TotalCount returns the number of records
+func (hs *HistoryStore) TotalCount() (uint, error) {
+	return hs.count(hs.index.countPrefix)
+}
+
+// TotalCountByChain returns the number of records of the messages of the source nId
+func (hs *HistoryStore) TotalCountByChain(nId string) (uint, error) {
+	return hs.count(hs.index.countKey(nId))
+}
+
+func (hs *HistoryStore) count(counter []byte) (uint, error) {
+	count, err := getCount(hs.db, counter)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	return count, err
+}
+
+// StoreRecord appends the record of a delivered message
+func (hs *HistoryStore) StoreRecord(record *types.RelayRecord) error {
+	if record == nil || record.MessageKey == nil {
+		return fmt.Errorf("error while storing history record: record cannot be nil")
+	}
+	data, err := hs.Encode(record)
+	if err != nil {
+		return err
+	}
+	return hs.db.SetByKey(hs.getKey(record), data)
+}
+
+// ListRecords returns the records of the filter in the order of delivery, or of sn when the
+// filter has a src and a sn, and the cursor of the next page, nil after the last page
+func (hs *HistoryStore) ListRecords(f *HistoryFilter, p *Pagination) ([]*types.RelayRecord, []byte, error) {
+	start, end, indexed := hs.queryRange(f)
+	skip := p.Offset
+	if p.Cursor != nil {
+		if bytes.Compare(p.Cursor, start) < 0 || (end != nil && bytes.Compare(p.Cursor, end) >= 0) {
+			return nil, nil, fmt.Errorf("the cursor does not belong to the query")
+		}
+		start, skip = append(bytes.Clone(p.Cursor), 0), 0
+	}
+
+	iter := hs.db.NewRangeIterator(start, end)
+	defer iter.Release()
+
+	var records []*types.RelayRecord
+	for iter.Next() {
+		value := iter.Value()
+		if indexed {
+			key, err := hs.index.recordKey(iter.Key())
+			if err != nil {
+				return nil, nil, err
+			}
+			value, err = hs.db.GetByKey(key)
+			// the record was pruned since the entry was read
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		record := new(types.RelayRecord)
+		if err := hs.Decode(value, record); err != nil {
+			return nil, nil, err
+		}
+		if !f.match(record) {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		records = append(records, record)
+		if !p.All && p.Limit > 0 && uint(len(records)) == p.Limit {
+			return records, bytes.Clone(iter.Key()), iter.Error()
+		}
+	}
+	return records, nil, iter.Error()
+}
+
+// queryRange returns the keys walked by the query of the filter and whether they are index entries
+func (hs *HistoryStore) queryRange(f *HistoryFilter) (start, end []byte, indexed bool) {
+	if f.Src != "" && f.Sn != nil {
+		entry := hs.index.indexPrefix.AppendString(f.Src).AppendBigInt(f.Sn)
+		if f.EventType != "" {
+			entry = entry.AppendString(f.EventType)
+		}
+		return entry, PrefixEnd(entry), true
+	}
+	prefix := NewKey(hs.prefix)
+	start, end = prefix, PrefixEnd(prefix)
+	if !f.Since.IsZero() {
+		start = prefix.AppendUint64(timeKey(f.Since))
+	}
+	if !f.Until.IsZero() {
+		end = prefix.AppendUint64(timeKey(f.Until))
+	}
+	return start, end, false
+}
+
+// Prune removes the records delivered before the time, when it is not zero, and the oldest
+// records beyond the keep most recent, when it is not zero, it returns the number of records removed
+func (hs *HistoryStore) Prune(before time.Time, keep uint) (int, error) {
+	var excess uint
+	if keep > 0 {
+		total, err := hs.TotalCount()
+		if err != nil {
+			return 0, err
+		}
+		if total > keep {
+			excess = total - keep
+		}
+	}
+	if before.IsZero() && excess == 0 {
+		return 0, nil
+	}
+
+	batch := hs.db.NewBatch()
+	iter := hs.db.NewIterator(NewKey(hs.prefix))
+	defer iter.Release()
+
+	// pruned counts the removals staged, written the ones written
+	var pruned, written int
+	for iter.Next() {
+		deliveredAt, err := hs.deliveredAt(iter.Key())
+		if err != nil {
+			return written, err
+		}
+		// the records are in the order of delivery, the ones left are all kept
+		if uint(pruned) >= excess && (before.IsZero() || deliveredAt >= timeKey(before)) {
+			break
+		}
+		if err := batch.DeleteByKey(bytes.Clone(iter.Key())); err != nil {
+			return written, err
+		}
+		if pruned++; pruned%DefaultPageSize == 0 {
+			if err := batch.Write(); err != nil {
+				return written, err
+			}
+			batch.Reset()
+			written = pruned
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return written, err
+	}
+	if err := batch.Write(); err != nil {
+		return written, err
+	}
+	return pruned, nil
+}
+
+// deliveredAt returns the delivery time component of a record key
+func (hs *HistoryStore) deliveredAt(key []byte) (uint64, error) {
+	components, err := SplitKey(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(components) != 5 || len(components[1]) != 8 {
+		return 0, fmt.Errorf("malformed history key %x", key)
+	}
+	return binary.BigEndian.Uint64(components[1]), nil
+}
+
+func (hs *HistoryStore) getKey(record *types.RelayRecord) []byte {
+	return NewKey(hs.prefix).AppendUint64(timeKey(record.DeliveredAt)).
+		AppendString(record.Src).AppendString(record.EventType).AppendBigInt(record.Sn)
+}
+
+func (hs *HistoryStore) Encode(d interface{}) ([]byte, error) {
+	return jsoniter.Marshal(d)
+}
+
+func (hs *HistoryStore) Decode(data []byte, output interface{}) error {
+	return jsoniter.Unmarshal(data, output)
+}
+
+// historyIndexer keeps the counters of the records, in total and per source chain, and the
+// lookup of the records of a message: the src and sn of the message followed by its event
+// type and delivery time, with an empty value. Every field they need is in the record key.
+type historyIndexer struct {
+	prefix      Key
+	countPrefix Key
+	indexPrefix Key
+}
+
+func newHistoryIndexer(prefix string) *historyIndexer {
+	return &historyIndexer{
+		prefix:      NewKey(prefix),
+		countPrefix: NewKey(prefix + ".count"),
+		indexPrefix: NewKey(prefix + ".index"),
+	}
+}
+
+func (ix *historyIndexer) Prefix() []byte {
+	return ix.prefix
+}
+
+func (ix *historyIndexer) Update(w IndexWriter, key, old, value []byte) error {
+	var delta int64
+	switch {
+	case old == nil && value != nil:
+		delta = 1
+	case old != nil && value == nil:
+		delta = -1
+	default:
+		return nil
+	}
+	components, err := SplitKey(key)
+	if err != nil {
+		return err
+	}
+	if len(components) != 5 {
+		return fmt.Errorf("malformed history key %x", key)
+	}
+	deliveredAt, src, eventType, sn := components[1], string(components[2]), components[3], components[4]
+	entry := ix.indexPrefix.AppendString(src).appendComponent(sn).appendComponent(eventType).appendComponent(deliveredAt)
+	if delta > 0 {
+		err = w.SetByKey(entry, nil)
+	} else {
+		err = w.DeleteByKey(entry)
+	}
+	if err != nil {
+		return err
+	}
+	if err := addCount(w, ix.countPrefix, delta); err != nil {
+		return err
+	}
+	return addCount(w, ix.countKey(src), delta)
+}
+
+func (ix *historyIndexer) countKey(src string) Key {
+	return ix.countPrefix.AppendString(src)
+}
+
+// recordKey returns the key of the record of an index entry
+func (ix *historyIndexer) recordKey(entry []byte) ([]byte, error) {
+	components, err := SplitKey(entry)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) != 5 {
+		return nil, fmt.Errorf("malformed history index entry %x", entry)
+	}
+	return ix.prefix.appendComponent(components[4]).AppendString(string(components[1])).
+		appendComponent(components[3]).appendComponent(components[2]), nil
+}
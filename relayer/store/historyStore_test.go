@@ -0,0 +1,132 @@
+package store
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryStore(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/history")
+	require.NoError(t, err)
+	defer testdb.Close()
+	require.NoError(t, testdb.ClearStore())
+
+	db := WithIndexers(testdb, NewHistoryIndexer("history"))
+	historyStore := NewHistoryStore(db, "history")
+	now := time.Now()
+
+	newRecord := func(src, dst string, sn int64, deliveredAt time.Time) *types.RelayRecord {
+		return &types.RelayRecord{
+			MessageKey:  types.NewMessageKey(big.NewInt(sn), src, dst, "emitMessage"),
+			DstTxHash:   "0x" + big.NewInt(sn).Text(16),
+			DeliveredAt: deliveredAt,
+		}
+	}
+	sns := func(records []*types.RelayRecord) []int64 {
+		var sns []int64
+		for _, r := range records {
+			sns = append(sns, r.Sn.Int64())
+		}
+		return sns
+	}
+	all := func() *Pagination { return NewPagination().GetAll() }
+
+	t.Run("store records", func(t *testing.T) {
+		for i := int64(1); i <= 6; i++ {
+			dst := "archway"
+			if i%2 == 0 {
+				dst = "avalanche"
+			}
+			require.NoError(t, historyStore.StoreRecord(newRecord("icon", dst, i, now.Add(time.Duration(i)*time.Minute))))
+		}
+		// delivered again after a reorg
+		require.NoError(t, historyStore.StoreRecord(newRecord("icon", "archway", 3, now.Add(7*time.Minute))))
+		require.NoError(t, historyStore.StoreRecord(newRecord("archway", "icon", 3, now.Add(8*time.Minute))))
+		assert.Error(t, historyStore.StoreRecord(nil))
+
+		count, err := historyStore.TotalCount()
+		require.NoError(t, err)
+		assert.Equal(t, uint(8), count)
+		count, err = historyStore.TotalCountByChain("icon")
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), count)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		// in the order of delivery
+		records, _, err := historyStore.ListRecords(&HistoryFilter{}, all())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 3, 3}, sns(records))
+
+		records, _, err = historyStore.ListRecords(&HistoryFilter{Src: "icon", Sn: big.NewInt(3)}, all())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 3}, sns(records))
+		assert.Equal(t, now.Add(3*time.Minute).UnixNano(), records[0].DeliveredAt.UnixNano())
+
+		records, _, err = historyStore.ListRecords(&HistoryFilter{Src: "icon", Dst: "avalanche"}, all())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 4, 6}, sns(records))
+
+		records, _, err = historyStore.ListRecords(&HistoryFilter{Since: now.Add(2 * time.Minute), Until: now.Add(5 * time.Minute)}, all())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 3, 4}, sns(records))
+
+		records, _, err = historyStore.ListRecords(&HistoryFilter{Src: "icon", Sn: big.NewInt(9)}, all())
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		filter := &HistoryFilter{Src: "icon"}
+		var got []int64
+		p := NewPagination().WithLimit(3)
+		for {
+			records, next, err := historyStore.ListRecords(filter, p)
+			require.NoError(t, err)
+			got = append(got, sns(records)...)
+			if next == nil {
+				break
+			}
+			p = NewPagination().WithLimit(3).WithCursor(next)
+		}
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 3}, got)
+
+		_, next, err := historyStore.ListRecords(filter, NewPagination().WithLimit(1))
+		require.NoError(t, err)
+		_, _, err = historyStore.ListRecords(&HistoryFilter{Src: "icon", Sn: big.NewInt(3)}, NewPagination().WithCursor(next))
+		assert.Error(t, err)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		// nothing out of the policy
+		pruned, err := historyStore.Prune(now, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 0, pruned)
+
+		pruned, err = historyStore.Prune(now.Add(3*time.Minute), 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, pruned)
+
+		pruned, err = historyStore.Prune(time.Time{}, 4)
+		require.NoError(t, err)
+		assert.Equal(t, 2, pruned)
+
+		records, _, err := historyStore.ListRecords(&HistoryFilter{}, all())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{5, 6, 3, 3}, sns(records))
+		count, err := historyStore.TotalCountByChain("icon")
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), count)
+
+		// the lookup of a pruned delivery is gone with it
+		records, _, err = historyStore.ListRecords(&HistoryFilter{Src: "icon", Sn: big.NewInt(3)}, all())
+		require.NoError(t, err)
+		assert.Len(t, records, 1)
+	})
+}
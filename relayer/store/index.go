@@ -0,0 +1,191 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Indexer keeps the records derived from the records of a prefix, such as counters and
+// secondary indexes, in step with them
+type Indexer interface {
+	// Prefix is the prefix of the indexed records
+	Prefix() []byte
+	// Update stages in w the changes of the derived records when the record of key goes from
+	// old to value, old is nil for a new record and value is nil for a deleted one
+	Update(w IndexWriter, key, old, value []byte) error
+}
+
+// IndexWriter stages the writes of an index update, its reads see the writes staged before
+type IndexWriter interface {
+	KeyValueReader
+	KeyValueWriter
+	DeleteByKey(key []byte) error
+}
+
+// indexedStore updates the indexers on the writes of their records. The updates are computed
+// when a batch is written, one batch at a time, so they read the records as they are stored.
+// On a TxWriter backend the reads and the writes of a batch are done in its transaction.
+type indexedStore struct {
+	Store
+	indexers []Indexer
+	mu       *sync.Mutex
+}
+
+// WithIndexers returns a store keeping the records of the indexers up to date, every write
+// of the indexed records must go through it
+func WithIndexers(db Store, indexers ...Indexer) Store {
+	return &indexedStore{Store: db, indexers: indexers, mu: new(sync.Mutex)}
+}
+
+func (s *indexedStore) indexer(key []byte) Indexer {
+	for _, indexer := range s.indexers {
+		if bytes.HasPrefix(key, indexer.Prefix()) {
+			return indexer
+		}
+	}
+	return nil
+}
+
+func (s *indexedStore) SetByKey(key []byte, value []byte) error {
+	if s.indexer(key) == nil {
+		return s.Store.SetByKey(key, value)
+	}
+	batch := s.NewBatch()
+	if err := batch.SetByKey(key, value); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func (s *indexedStore) DeleteByKey(key []byte) error {
+	if s.indexer(key) == nil {
+		return s.Store.DeleteByKey(key)
+	}
+	batch := s.NewBatch()
+	if err := batch.DeleteByKey(key); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// NewBatch returns a batch writing the index updates along with its writes
+func (s *indexedStore) NewBatch() Batch {
+	return NewOpBatch(s.write)
+}
+
+func (s *indexedStore) write(ops []Op) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tx, ok := s.Store.(TxWriter); ok {
+		return tx.WriteTx(func(w IndexWriter) error {
+			return s.apply(w, ops)
+		})
+	}
+	w := newStagedWriter(s.Store, s.Store.NewBatch())
+	if err := s.apply(w, ops); err != nil {
+		return err
+	}
+	return w.batch.Write()
+}
+
+// apply writes the ops to w along with the index updates, the reads of w see its writes
+func (s *indexedStore) apply(w IndexWriter, ops []Op) error {
+	for _, op := range ops {
+		if indexer := s.indexer(op.Key); indexer != nil {
+			old, err := w.GetByKey(op.Key)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+			value := op.Value
+			if op.Delete {
+				value = nil
+			} else if value == nil {
+				value = []byte{}
+			}
+			if old != nil || value != nil {
+				if err := indexer.Update(w, op.Key, old, value); err != nil {
+					return err
+				}
+			}
+		}
+		var err error
+		if op.Delete {
+			err = w.DeleteByKey(op.Key)
+		} else {
+			err = w.SetByKey(op.Key, op.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stagedWriter stages writes in a batch, its reads see the staged writes
+type stagedWriter struct {
+	db     KeyValueReader
+	batch  Batch
+	staged map[string][]byte
+}
+
+func newStagedWriter(db KeyValueReader, batch Batch) *stagedWriter {
+	return &stagedWriter{db: db, batch: batch, staged: make(map[string][]byte)}
+}
+
+func (w *stagedWriter) GetByKey(key []byte) ([]byte, error) {
+	if value, ok := w.staged[string(key)]; ok {
+		if value == nil {
+			return nil, ErrNotFound
+		}
+		return value, nil
+	}
+	return w.db.GetByKey(key)
+}
+
+func (w *stagedWriter) SetByKey(key []byte, value []byte) error {
+	if value == nil {
+		value = []byte{}
+	}
+	w.staged[string(key)] = bytes.Clone(value)
+	return w.batch.SetByKey(key, value)
+}
+
+func (w *stagedWriter) DeleteByKey(key []byte) error {
+	w.staged[string(key)] = nil
+	return w.batch.DeleteByKey(key)
+}
+
+// flush writes the staged writes, the reads go to the store again
+func (w *stagedWriter) flush() error {
+	if err := w.batch.Write(); err != nil {
+		return err
+	}
+	w.batch.Reset()
+	clear(w.staged)
+	return nil
+}
+
+// addCount adds delta to the counter of key, a counter dropping to zero is deleted
+func addCount(w IndexWriter, key []byte, delta int64) error {
+	count, err := getCount(w, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if next := int64(count) + delta; next > 0 {
+		return w.SetByKey(key, []byte(strconv.FormatInt(next, 10)))
+	}
+	return w.DeleteByKey(key)
+}
+
+// getCount returns the counter of key, ErrNotFound when there is none
+func getCount(db KeyValueReader, key []byte) (uint, error) {
+	v, err := db.GetByKey(key)
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.ParseUint(string(v), 10, 64)
+	return uint(count), err
+}
@@ -0,0 +1,84 @@
+package store
+
+// DefaultPageSize is the number of keys read at once by a page iterator
+const DefaultPageSize = 256
+
+// KeyValue is a key and its value
+type KeyValue struct {
+	Key   []byte
+	Value []byte
+}
+
+// PageReader reads in order at most limit keys from start, included, to end, excluded,
+// there is no upper bound when end is nil
+type PageReader func(start, end []byte, limit int) ([]KeyValue, error)
+
+// pageIterator iterates the keys of a prefix one page at a time, every page is read in its
+// own transaction so no read is held open while the caller writes to the store
+type pageIterator struct {
+	read  PageReader
+	start []byte
+	end   []byte
+	page  []KeyValue
+	index int
+	done  bool
+	err   error
+}
+
+// NewPageIterator returns an iterator over the keys of prefix reading them with read
+func NewPageIterator(prefix []byte, read PageReader) Iterator {
+	return NewRangePageIterator(prefix, PrefixEnd(prefix), read)
+}
+
+// NewRangePageIterator returns an iterator over the keys from start to end reading them with read
+func NewRangePageIterator(start, end []byte, read PageReader) Iterator {
+	return &pageIterator{
+		read:  read,
+		start: start,
+		end:   end,
+		index: -1,
+	}
+}
+
+func (i *pageIterator) Next() bool {
+	if i.err != nil {
+		return false
+	}
+	if i.index+1 < len(i.page) {
+		i.index++
+		return true
+	}
+	if i.done {
+		return false
+	}
+
+	i.page, i.err = i.read(i.start, i.end, DefaultPageSize)
+	if i.err != nil || len(i.page) == 0 {
+		i.done = true
+		return false
+	}
+	i.done = len(i.page) < DefaultPageSize
+	// the next page starts right after the last key of this one
+	last := i.page[len(i.page)-1].Key
+	i.start = append(append(make([]byte, 0, len(last)+1), last...), 0)
+	i.index = 0
+	return true
+}
+
+func (i *pageIterator) Key() []byte {
+	return i.page[i.index].Key
+}
+
+func (i *pageIterator) Value() []byte {
+	return i.page[i.index].Value
+}
+
+func (i *pageIterator) Release() {
+	i.page = nil
+	i.index = -1
+	i.done = true
+}
+
+func (i *pageIterator) Error() error {
+	return i.err
+}
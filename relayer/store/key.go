@@ -0,0 +1,99 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// keyFormat is the first byte of the keys, the schema version the encoding came with
+const keyFormat = byte(KeySchemaVersion)
+
+// Key is a store key of the current schema: the key format followed by length prefixed
+// components, so the keys under a component value never match the prefix of a longer value
+// and the integers keep their numeric order
+type Key []byte
+
+// NewKey starts the key of a store prefix
+func NewKey(prefix string) Key {
+	return Key{keyFormat}.AppendString(prefix)
+}
+
+// AppendString appends a string component
+func (k Key) AppendString(s string) Key {
+	return k.appendComponent([]byte(s))
+}
+
+// AppendUint64 appends an integer component in big endian
+func (k Key) AppendUint64(n uint64) Key {
+	return k.appendComponent(binary.BigEndian.AppendUint64(nil, n))
+}
+
+// AppendBigInt appends a non-negative integer component in big endian,
+// its length prefix keeps the shorter integers first
+func (k Key) AppendBigInt(n *big.Int) Key {
+	return k.appendComponent(bigIntBytes(n))
+}
+
+// appendComponent returns a new key, a key shared by several keys is never written to
+func (k Key) appendComponent(b []byte) Key {
+	out := make(Key, len(k), len(k)+binary.MaxVarintLen64+len(b))
+	copy(out, k)
+	out = binary.AppendUvarint(out, uint64(len(b)))
+	return append(out, b...)
+}
+
+// SplitKey returns the components of a key of the current schema, the prefix first
+func SplitKey(key []byte) ([][]byte, error) {
+	if len(key) == 0 || key[0] != keyFormat {
+		return nil, fmt.Errorf("key is not of schema version %d", KeySchemaVersion)
+	}
+	var components [][]byte
+	for rest := key[1:]; len(rest) > 0; {
+		n, size := binary.Uvarint(rest)
+		if size <= 0 || uint64(len(rest)-size) < n {
+			return nil, fmt.Errorf("malformed key component")
+		}
+		rest = rest[size:]
+		components = append(components, rest[:n])
+		rest = rest[n:]
+	}
+	return components, nil
+}
+
+// findBySn looks up under prefix the key whose last component is sn, for the lookups of a
+// message key without its event type, it fails with ErrAmbiguousKey when several keys have the sn
+func findBySn(db Store, prefix Key, sn *big.Int) ([]byte, error) {
+	want := bigIntBytes(sn)
+	iter := db.NewIterator(prefix)
+	defer iter.Release()
+	var found []byte
+	for iter.Next() {
+		components, err := SplitKey(iter.Key())
+		if err != nil {
+			continue
+		}
+		if !bytes.Equal(components[len(components)-1], want) {
+			continue
+		}
+		if found != nil {
+			return nil, ErrAmbiguousKey
+		}
+		found = bytes.Clone(iter.Key())
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+func bigIntBytes(n *big.Int) []byte {
+	if n == nil {
+		return nil
+	}
+	return n.Bytes()
+}
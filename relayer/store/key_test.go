@@ -0,0 +1,114 @@
+package store
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	t.Run("nId prefix does not match a longer nId", func(t *testing.T) {
+		prefix := NewKey("message").AppendString("icon")
+		key := NewKey("message").AppendString("icon-testnet").AppendBigInt(big.NewInt(1))
+		assert.False(t, bytes.HasPrefix(key, prefix))
+	})
+
+	t.Run("integers keep their order", func(t *testing.T) {
+		base := NewKey("message").AppendString("icon")
+		assert.Equal(t, -1, bytes.Compare(base.AppendBigInt(big.NewInt(9)), base.AppendBigInt(big.NewInt(10))))
+		assert.Equal(t, -1, bytes.Compare(base.AppendBigInt(big.NewInt(255)), base.AppendBigInt(big.NewInt(256))))
+		assert.Equal(t, -1, bytes.Compare(base.AppendUint64(9), base.AppendUint64(10)))
+	})
+
+	t.Run("shared key is not written to", func(t *testing.T) {
+		base := NewKey("message")
+		first := base.AppendString("icon")
+		second := base.AppendString("archway")
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, NewKey("message"), base)
+	})
+
+	t.Run("split", func(t *testing.T) {
+		components, err := SplitKey(NewKey("message").AppendString("icon").AppendString("").AppendBigInt(big.NewInt(300)))
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("message"), []byte("icon"), {}, {0x01, 0x2c}}, components)
+
+		_, err = SplitKey([]byte("message-icon-1"))
+		assert.Error(t, err)
+		_, err = SplitKey(NewKey("message")[:3])
+		assert.Error(t, err)
+	})
+}
+
+func TestMessageEventTypes(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/keys")
+	require.NoError(t, err)
+	defer testdb.Close()
+	require.NoError(t, testdb.ClearStore())
+
+	messageStore := NewMessageStore(testdb, "message")
+	sn := big.NewInt(7)
+	emit := &types.Message{Src: "icon", Dst: "archway", Sn: sn, EventType: "emitMessage"}
+	call := &types.Message{Src: "icon", Dst: "archway", Sn: sn, EventType: "callMessage"}
+	other := &types.Message{Src: "icon-testnet", Dst: "archway", Sn: sn, EventType: "emitMessage"}
+	for _, m := range []*types.Message{emit, call, other} {
+		require.NoError(t, messageStore.StoreMessage(types.NewRouteMessage(m)))
+	}
+
+	count, err := messageStore.TotalCountByChain("icon")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), count)
+
+	got, err := messageStore.GetMessage(emit.MessageKey())
+	require.NoError(t, err)
+	assert.Equal(t, "emitMessage", got.EventType)
+
+	// a key without event type is looked up by sn
+	got, err = messageStore.GetMessage(&types.MessageKey{Src: "icon-testnet", Sn: sn})
+	require.NoError(t, err)
+	assert.Equal(t, "icon-testnet", got.Src)
+	require.NoError(t, messageStore.DeleteMessage(&types.MessageKey{Src: "icon-testnet", Sn: sn}))
+	_, err = messageStore.GetMessage(&types.MessageKey{Src: "icon-testnet", Sn: sn})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// the sn is stored under two event types of icon
+	_, err = messageStore.GetMessage(&types.MessageKey{Src: "icon", Sn: sn})
+	assert.ErrorIs(t, err, ErrAmbiguousKey)
+	assert.ErrorIs(t, messageStore.DeleteMessage(&types.MessageKey{Src: "icon", Sn: sn}), ErrAmbiguousKey)
+	count, err = messageStore.TotalCountByChain("icon")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), count)
+}
+
+func TestSchemaVersion(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/schema")
+	require.NoError(t, err)
+	defer testdb.Close()
+	require.NoError(t, testdb.ClearStore())
+
+	t.Run("empty store", func(t *testing.T) {
+		require.NoError(t, CheckSchemaVersion(testdb))
+		version, err := GetSchemaVersion(testdb)
+		require.NoError(t, err)
+		assert.Equal(t, SchemaVersion, version)
+	})
+
+	t.Run("legacy store", func(t *testing.T) {
+		require.NoError(t, testdb.ClearStore())
+		require.NoError(t, testdb.SetByKey(GetKey([]string{"block", "icon"}), []byte("10")))
+		version, err := GetSchemaVersion(testdb)
+		require.NoError(t, err)
+		assert.Equal(t, LegacySchemaVersion, version)
+		assert.ErrorContains(t, CheckSchemaVersion(testdb), "db migrate")
+	})
+
+	t.Run("newer store", func(t *testing.T) {
+		require.NoError(t, SetSchemaVersion(testdb, SchemaVersion+1))
+		assert.Error(t, CheckSchemaVersion(testdb))
+	})
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// testDB is a leveldb store for the tests of the package,
+// the lvldb package cannot be used as it imports this one
+type testDB struct {
+	db *leveldb.DB
+}
+
+func newTestDB(path string) (*testDB, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	return &testDB{db: db}, err
+}
+
+func (db *testDB) GetByKey(key []byte) ([]byte, error) {
+	value, err := db.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+func (db *testDB) SetByKey(key []byte, value []byte) error {
+	return db.db.Put(key, value, nil)
+}
+
+func (db *testDB) DeleteByKey(key []byte) error {
+	return db.db.Delete(key, nil)
+}
+
+func (db *testDB) NewIterator(prefix []byte) Iterator {
+	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
+}
+
+func (db *testDB) NewRangeIterator(start, limit []byte) Iterator {
+	return db.db.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+}
+
+func (db *testDB) NewBatch() Batch {
+	return NewOpBatch(func(ops []Op) error {
+		batch := new(leveldb.Batch)
+		for _, op := range ops {
+			if op.Delete {
+				batch.Delete(op.Key)
+			} else {
+				batch.Put(op.Key, op.Value)
+			}
+		}
+		return db.db.Write(batch, nil)
+	})
+}
+
+func (db *testDB) ClearStore() error {
+	iter := db.db.NewIterator(nil, nil)
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Delete(iter.Key())
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return db.db.Write(batch, nil)
+}
+
+func (db *testDB) Close() error {
+	return db.db.Close()
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// the secondary indexes of the messages of a source chain
+const (
+	indexDst      = "dst"
+	indexRetry    = "retry"
+	indexDetected = "detected"
+)
+
+// messageIndexer keeps the counters of the messages, in total and per source chain, and their
+// secondary indexes by destination, retry count and detection time. An index entry is the key
+// of the index value followed by the event type and sn of the message, with an empty value.
+// The event type is part of the message key so it needs no index.
+type messageIndexer struct {
+	prefix      Key
+	countPrefix Key
+	indexPrefix Key
+}
+
+func newMessageIndexer(prefix string) *messageIndexer {
+	return &messageIndexer{
+		prefix:      NewKey(prefix),
+		countPrefix: NewKey(prefix + ".count"),
+		indexPrefix: NewKey(prefix + ".index"),
+	}
+}
+
+func (ix *messageIndexer) Prefix() []byte {
+	return ix.prefix
+}
+
+// indexedFields are the fields of a stored message the indexes are built from
+type indexedFields struct {
+	Dst        string `json:"dst"`
+	Retry      uint8
+	DetectedAt time.Time
+}
+
+func (ix *messageIndexer) Update(w IndexWriter, key, old, value []byte) error {
+	components, err := SplitKey(key)
+	if err != nil {
+		return err
+	}
+	if len(components) != 4 {
+		return fmt.Errorf("malformed message key %x", key)
+	}
+	src := string(components[1])
+
+	oldEntries, err := ix.entries(components, old)
+	if err != nil {
+		return err
+	}
+	entries, err := ix.entries(components, value)
+	if err != nil {
+		return err
+	}
+	for k := range oldEntries {
+		if _, ok := entries[k]; !ok {
+			if err := w.DeleteByKey([]byte(k)); err != nil {
+				return err
+			}
+		}
+	}
+	for k := range entries {
+		if _, ok := oldEntries[k]; !ok {
+			if err := w.SetByKey([]byte(k), nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	var delta int64
+	switch {
+	case old == nil && value != nil:
+		delta = 1
+	case old != nil && value == nil:
+		delta = -1
+	default:
+		return nil
+	}
+	if err := addCount(w, ix.countPrefix, delta); err != nil {
+		return err
+	}
+	return addCount(w, ix.countKey(src), delta)
+}
+
+// entries returns the index entries of the message of the key components, none when value is nil
+func (ix *messageIndexer) entries(components [][]byte, value []byte) (map[string]struct{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var fields indexedFields
+	if err := jsoniter.Unmarshal(value, &fields); err != nil {
+		return nil, err
+	}
+	src, eventType, sn := string(components[1]), components[2], components[3]
+	entry := func(index Key) string {
+		return string(index.appendComponent(eventType).appendComponent(sn))
+	}
+	return map[string]struct{}{
+		entry(ix.indexKey(src, indexDst).AppendString(fields.Dst)):                      {},
+		entry(ix.indexKey(src, indexRetry).AppendUint64(uint64(fields.Retry))):          {},
+		entry(ix.indexKey(src, indexDetected).AppendUint64(timeKey(fields.DetectedAt))): {},
+	}, nil
+}
+
+func (ix *messageIndexer) countKey(src string) Key {
+	return ix.countPrefix.AppendString(src)
+}
+
+func (ix *messageIndexer) indexKey(src, index string) Key {
+	return ix.indexPrefix.AppendString(src).AppendString(index)
+}
+
+// messageKey returns the key of the message of an index entry
+func (ix *messageIndexer) messageKey(entry []byte) ([]byte, error) {
+	components, err := SplitKey(entry)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) != 6 {
+		return nil, fmt.Errorf("malformed message index entry %x", entry)
+	}
+	return ix.prefix.AppendString(string(components[1])).appendComponent(components[4]).appendComponent(components[5]), nil
+}
+
+// rebuild drops the counters and the index entries and derives them again from the messages,
+// it returns the number of messages indexed
+func (ix *messageIndexer) rebuild(db Store) (int, error) {
+	w := newStagedWriter(db, db.NewBatch())
+	for _, prefix := range []Key{ix.countPrefix, ix.indexPrefix} {
+		if err := ix.each(db, prefix, w, func(key, _ []byte) error {
+			return w.DeleteByKey(bytes.Clone(key))
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	var count int
+	err := ix.each(db, ix.prefix, w, func(key, value []byte) error {
+		count++
+		return ix.Update(w, key, nil, value)
+	})
+	return count, err
+}
+
+// each calls fn on the records of prefix and flushes w every page of records
+func (ix *messageIndexer) each(db Store, prefix Key, w *stagedWriter, fn func(key, value []byte) error) error {
+	iter := db.NewIterator(prefix)
+	defer iter.Release()
+	for i := 1; iter.Next(); i++ {
+		if err := fn(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+		if i%DefaultPageSize == 0 {
+			if err := w.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+// timeKey is the index value of a time, the zero time first
+func timeKey(t time.Time) uint64 {
+	if t.IsZero() || t.Before(time.Unix(0, 0)) {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageIndex(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/messageindex")
+	require.NoError(t, err)
+	defer testdb.Close()
+	require.NoError(t, testdb.ClearStore())
+
+	db := WithIndexers(testdb, NewMessageIndexer("message"))
+	messageStore := NewMessageStore(db, "message")
+	now := time.Now()
+
+	newMessage := func(sn int64, dst, eventType string, retry uint8, detectedAt time.Time) *types.RouteMessage {
+		m := types.NewRouteMessage(&types.Message{Src: "icon", Dst: dst, Sn: big.NewInt(sn), EventType: eventType})
+		m.Retry = retry
+		m.DetectedAt = detectedAt
+		return m
+	}
+	sns := func(messages []*types.RouteMessage) []int64 {
+		var sns []int64
+		for _, m := range messages {
+			sns = append(sns, m.Sn.Int64())
+		}
+		return sns
+	}
+
+	t.Run("counters", func(t *testing.T) {
+		batch := db.NewBatch()
+		staged := NewMessageStore(WithBatch(db, batch), "message")
+		for i := int64(1); i <= 6; i++ {
+			dst := "archway"
+			if i%2 == 0 {
+				dst = "avalanche"
+			}
+			require.NoError(t, staged.StoreMessage(newMessage(i, dst, "emitMessage", 0, now.Add(time.Duration(i)*time.Minute))))
+		}
+		// stored and removed in the same batch
+		require.NoError(t, staged.StoreMessage(newMessage(7, "archway", "emitMessage", 0, now)))
+		require.NoError(t, staged.DeleteMessage(newMessage(7, "archway", "emitMessage", 0, now).MessageKey()))
+		require.NoError(t, batch.Write())
+
+		require.NoError(t, messageStore.StoreMessage(newMessage(8, "archway", "callMessage", 0, now)))
+		require.NoError(t, NewMessageStore(db, "message").StoreMessage(newMessage(1, "archway", "emitMessage", 0, now)))
+		require.NoError(t, NewMessageStore(db, "message").StoreMessage(types.NewRouteMessage(&types.Message{Src: "archway", Dst: "icon", Sn: big.NewInt(1), EventType: "emitMessage"})))
+
+		count, err := messageStore.TotalCountByChain("icon")
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), count)
+		count, err = messageStore.TotalCount()
+		require.NoError(t, err)
+		assert.Equal(t, uint(8), count)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		all := NewPagination().GetAll()
+		messages, _, err := messageStore.ListMessages(&MessageFilter{Src: "icon", Dst: "avalanche"}, all)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 4, 6}, sns(messages))
+
+		messages, _, err = messageStore.ListMessages(&MessageFilter{Src: "icon", EventType: "callMessage"}, all)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{8}, sns(messages))
+
+		// in order of detection time
+		messages, _, err = messageStore.ListMessages(&MessageFilter{Src: "icon", Since: now.Add(3 * time.Minute)}, all)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 4, 5, 6}, sns(messages))
+
+		messages, _, err = messageStore.ListMessages(&MessageFilter{Src: "icon", Dst: "archway", Since: now.Add(3 * time.Minute)}, all)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 5}, sns(messages))
+	})
+
+	t.Run("retry updates the index", func(t *testing.T) {
+		require.NoError(t, messageStore.StoreMessage(newMessage(5, "archway", "emitMessage", 2, now.Add(5*time.Minute))))
+		require.NoError(t, messageStore.StoreMessage(newMessage(3, "archway", "emitMessage", 1, now.Add(3*time.Minute))))
+		require.NoError(t, messageStore.StoreMessage(newMessage(3, "archway", "emitMessage", 3, now.Add(3*time.Minute))))
+
+		messages, _, err := messageStore.ListMessages(&MessageFilter{Src: "icon", Failed: true}, NewPagination().GetAll())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{5, 3}, sns(messages))
+
+		require.NoError(t, messageStore.DeleteMessage(newMessage(5, "archway", "emitMessage", 0, now).MessageKey()))
+		messages, _, err = messageStore.ListMessages(&MessageFilter{Src: "icon", Failed: true}, NewPagination().GetAll())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3}, sns(messages))
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		filter := &MessageFilter{Src: "icon", EventType: "emitMessage"}
+		var got []int64
+		p := NewPagination().WithLimit(2)
+		for {
+			messages, next, err := messageStore.ListMessages(filter, p)
+			require.NoError(t, err)
+			got = append(got, sns(messages)...)
+			if next == nil {
+				break
+			}
+			p = NewPagination().WithLimit(2).WithCursor(next)
+		}
+		assert.Equal(t, []int64{1, 2, 3, 4, 6}, got)
+
+		_, next, err := messageStore.ListMessages(filter, NewPagination().WithLimit(1))
+		require.NoError(t, err)
+		_, _, err = messageStore.ListMessages(&MessageFilter{Src: "icon", Dst: "archway"}, NewPagination().WithCursor(next))
+		assert.Error(t, err)
+	})
+
+	t.Run("rebuild", func(t *testing.T) {
+		want, err := messageStore.TotalCountByChain("icon")
+		require.NoError(t, err)
+		require.NoError(t, testdb.DeleteByKey(newMessageIndexer("message").countKey("icon")))
+
+		count, err := NewMessageStore(testdb, "message").RebuildIndex()
+		require.NoError(t, err)
+		assert.Equal(t, 7, count)
+		got, err := messageStore.TotalCountByChain("icon")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		messages, _, err := messageStore.ListMessages(&MessageFilter{Src: "icon", Dst: "avalanche"}, NewPagination().GetAll())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 4, 6}, sns(messages))
+	})
+}
+
+func TestPaginationOffset(t *testing.T) {
+	p := NewPagination().WithPage(1, 10)
+	assert.Equal(t, uint(0), p.Offset)
+	p = NewPagination().WithPage(3, 10)
+	assert.Equal(t, uint(20), p.Offset)
+}
@@ -1,7 +1,10 @@
 package store
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/icon-project/centralized-relay/relayer/types"
 	jsoniter "github.com/json-iterator/go"
@@ -10,12 +13,16 @@ import (
 type MessageStore struct {
 	db     Store
 	prefix string
+	index  *messageIndexer
 }
 
 type Pagination struct {
 	Limit  uint
 	Offset uint
 	All    bool
+	// Cursor is the position the page starts after, the Next of the previous page,
+	// the offset is ignored when it is set
+	Cursor []byte `json:",omitempty"`
 }
 
 func NewPagination() *Pagination {
@@ -53,7 +60,13 @@ func (p *Pagination) CalculateOffset(page uint) uint {
 	if page <= 1 {
 		return 0
 	}
-	return page * p.Limit
+	return (page - 1) * p.Limit
+}
+
+// WithCursor sets the position the page starts after
+func (p *Pagination) WithCursor(cursor []byte) *Pagination {
+	p.Cursor = cursor
+	return p
 }
 
 func (p *Pagination) WithOffset(o uint) *Pagination {
@@ -61,19 +74,60 @@ func (p *Pagination) WithOffset(o uint) *Pagination {
 	return p
 }
 
+// MessageFilter selects the messages of a source chain, its zero fields match every message
+type MessageFilter struct {
+	Src       string
+	Dst       string `json:",omitempty"`
+	EventType string `json:",omitempty"`
+	// Failed selects the messages whose delivery failed at least once
+	Failed bool `json:",omitempty"`
+	// Since selects the messages detected from then on
+	Since time.Time `json:",omitempty"`
+}
+
+func (f *MessageFilter) match(m *types.RouteMessage) bool {
+	return (f.Dst == "" || m.Dst == f.Dst) &&
+		(f.EventType == "" || m.EventType == f.EventType) &&
+		(!f.Failed || m.Retry > 0) &&
+		(f.Since.IsZero() || !m.DetectedAt.Before(f.Since))
+}
+
 func NewMessageStore(db Store, prefix string) *MessageStore {
 	return &MessageStore{
 		db:     db,
 		prefix: prefix,
+		index:  newMessageIndexer(prefix),
 	}
 }
 
+// NewMessageIndexer returns the indexer maintaining the counters and the secondary indexes
+// of the message store of prefix, the store must be given a db kept by it with WithIndexers
+func NewMessageIndexer(prefix string) Indexer {
+	return newMessageIndexer(prefix)
+}
+
+// RebuildIndex derives the counters and the secondary indexes again from the stored messages,
+// it returns the number of messages indexed
+func (ms *MessageStore) RebuildIndex() (int, error) {
+	return ms.index.rebuild(ms.db)
+}
+
+// TotalCount returns the maintained count of the messages, they are counted one by one
+// when there is no counter as the store is empty or not indexed
 func (ms *MessageStore) TotalCount() (uint, error) {
-	return ms.getCountByKey(GetKey([]string{ms.prefix}))
+	return ms.count(ms.index.countPrefix, NewKey(ms.prefix))
 }
 
 func (ms *MessageStore) TotalCountByChain(nId string) (uint, error) {
-	return ms.getCountByKey(GetKey([]string{ms.prefix, nId}))
+	return ms.count(ms.index.countKey(nId), NewKey(ms.prefix).AppendString(nId))
+}
+
+func (ms *MessageStore) count(counter, prefix []byte) (uint, error) {
+	count, err := getCount(ms.db, counter)
+	if errors.Is(err, ErrNotFound) {
+		return ms.getCountByKey(prefix)
+	}
+	return count, err
 }
 
 func (ms *MessageStore) getCountByKey(key []byte) (uint, error) {
@@ -95,9 +149,10 @@ func (ms *MessageStore) StoreMessage(message *types.RouteMessage) error {
 		return fmt.Errorf("error while storingMessage: message cannot be nil")
 	}
 
-	key := GetKey([]string{ms.prefix, message.Src, message.Sn.String()})
+	key := ms.getKey(message.MessageKey())
 
-	msgByte, err := ms.Encode(message)
+	// encode a copy, the message may change while it is being encoded
+	msgByte, err := ms.Encode(message.Clone())
 	if err != nil {
 		return err
 	}
@@ -105,7 +160,11 @@ func (ms *MessageStore) StoreMessage(message *types.RouteMessage) error {
 }
 
 func (ms *MessageStore) GetMessage(messageKey *types.MessageKey) (*types.RouteMessage, error) {
-	v, err := ms.db.GetByKey(GetKey([]string{ms.prefix, messageKey.Src, messageKey.Sn.String()}))
+	key, err := ms.findKey(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	v, err := ms.db.GetByKey(key)
 	if err != nil {
 		return nil, err
 	}
@@ -117,30 +176,106 @@ func (ms *MessageStore) GetMessage(messageKey *types.MessageKey) (*types.RouteMe
 	return msg, nil
 }
 
+// GetMessages returns a page of the messages of the source nId, all of them when the limit is 0
 func (ms *MessageStore) GetMessages(nId string, p *Pagination) ([]*types.RouteMessage, error) {
-	var messages []*types.RouteMessage
+	messages, _, err := ms.ListMessages(&MessageFilter{Src: nId}, p)
+	return messages, err
+}
 
-	iter := ms.db.NewIterator(GetKey([]string{ms.prefix, nId}))
+// ListMessages returns a page of the messages selected by the filter and the cursor of the
+// next page, nil after the last one. The messages are walked with the most selective index
+// of the filter, in order of retry count for the failed messages, of detection time when
+// since is set, and of event type and sn otherwise.
+func (ms *MessageStore) ListMessages(f *MessageFilter, p *Pagination) ([]*types.RouteMessage, []byte, error) {
+	start, end, indexed := ms.queryRange(f)
+	skip := p.Offset
+	if p.Cursor != nil {
+		if bytes.Compare(p.Cursor, start) < 0 || bytes.Compare(p.Cursor, end) >= 0 {
+			return nil, nil, fmt.Errorf("the cursor does not belong to the query")
+		}
+		start, skip = append(bytes.Clone(p.Cursor), 0), 0
+	}
+
+	iter := ms.db.NewRangeIterator(start, end)
 	defer iter.Release()
 
-	// return all the messages
+	var messages []*types.RouteMessage
 	for iter.Next() {
+		value := iter.Value()
+		if indexed {
+			key, err := ms.index.messageKey(iter.Key())
+			if err != nil {
+				return nil, nil, err
+			}
+			value, err = ms.db.GetByKey(key)
+			// the message was removed since the entry was read
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 		msg := new(types.RouteMessage)
-		if err := ms.Decode(iter.Value(), msg); err != nil {
-			return nil, err
+		if err := ms.Decode(value, msg); err != nil {
+			return nil, nil, err
+		}
+		if !f.match(msg) {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
 		}
-
 		messages = append(messages, msg)
-		if uint(len(messages)) == p.Limit {
-			break
+		if !p.All && p.Limit > 0 && uint(len(messages)) == p.Limit {
+			return messages, bytes.Clone(iter.Key()), iter.Error()
 		}
 	}
+	return messages, nil, iter.Error()
+}
 
-	return messages, iter.Error()
+// queryRange returns the keys walked by the query of the filter and whether they are index entries
+func (ms *MessageStore) queryRange(f *MessageFilter) (start, end []byte, indexed bool) {
+	switch {
+	case f.Failed:
+		index := ms.index.indexKey(f.Src, indexRetry)
+		return index.AppendUint64(1), PrefixEnd(index), true
+	case !f.Since.IsZero():
+		index := ms.index.indexKey(f.Src, indexDetected)
+		return index.AppendUint64(timeKey(f.Since)), PrefixEnd(index), true
+	case f.Dst != "":
+		index := ms.index.indexKey(f.Src, indexDst).AppendString(f.Dst)
+		return index, PrefixEnd(index), true
+	}
+	prefix := NewKey(ms.prefix).AppendString(f.Src)
+	if f.EventType != "" {
+		prefix = prefix.AppendString(f.EventType)
+	}
+	return prefix, PrefixEnd(prefix), false
 }
 
 func (ms *MessageStore) DeleteMessage(messageKey *types.MessageKey) error {
-	return ms.db.DeleteByKey(GetKey([]string{ms.prefix, messageKey.Src, messageKey.Sn.String()}))
+	key, err := ms.findKey(messageKey)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ms.db.DeleteByKey(key)
+}
+
+func (ms *MessageStore) getKey(messageKey *types.MessageKey) []byte {
+	return NewKey(ms.prefix).AppendString(messageKey.Src).AppendString(messageKey.EventType).AppendBigInt(messageKey.Sn)
+}
+
+// findKey returns the key of the message, it is looked up by sn when the event type is not known
+func (ms *MessageStore) findKey(messageKey *types.MessageKey) ([]byte, error) {
+	if messageKey.EventType != "" {
+		return ms.getKey(messageKey), nil
+	}
+	return findBySn(ms.db, NewKey(ms.prefix).AppendString(messageKey.Src), messageKey.Sn)
 }
 
 func (ms *MessageStore) Encode(d interface{}) ([]byte, error) {
@@ -5,14 +5,13 @@ import (
 	"os"
 	"testing"
 
-	"github.com/icon-project/centralized-relay/relayer/lvldb"
 	"github.com/icon-project/centralized-relay/relayer/types"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestMessageStoreSet(t *testing.T) {
 	tmpDir := os.TempDir()
-	testdb, err := lvldb.NewLvlDB(tmpDir + "/tt")
+	testdb, err := newTestDB(tmpDir + "/tt")
 	if err != nil {
 		assert.Fail(t, "error while creating test db ", err)
 	}
@@ -27,10 +26,11 @@ func TestMessageStoreSet(t *testing.T) {
 	messageStore := NewMessageStore(testdb, prefix)
 
 	storeMessage := &types.Message{
-		Src:  nId,
-		Dst:  "archway",
-		Sn:   Sn,
-		Data: []byte("test message"),
+		Src:       nId,
+		Dst:       "archway",
+		Sn:        Sn,
+		Data:      []byte("test message"),
+		EventType: "emitMessage",
 	}
 
 	t.Run("store message", func(t *testing.T) {
@@ -130,17 +130,17 @@ func TestMessageStoreSet(t *testing.T) {
 			msgs, err := messageStore.GetMessages(nId, p)
 			assert.NoError(t, err, "error occured when fetching messages")
 			assert.Equal(t, 2, len(msgs))
-			assert.Equal(t, storeMessage1.Sn, msgs[0].Sn)
-			assert.Equal(t, storeMessage2.Sn, msgs[1].Sn)
+			assert.Equal(t, storeMessage2.Sn, msgs[0].Sn)
+			assert.Equal(t, storeMessage3.Sn, msgs[1].Sn)
 		})
 
 		t.Run("GetMessages when offset is greater than total element", func(t *testing.T) {
 			p := NewPagination().
 				WithLimit(1).
 				WithOffset(14)
-			_, err := messageStore.GetMessages(nId, p)
-			// TODO  fix the issue with offset
+			msgs, err := messageStore.GetMessages(nId, p)
 			assert.NoError(t, err, "error occured when fetching messages")
+			assert.Empty(t, msgs)
 		})
 	})
 
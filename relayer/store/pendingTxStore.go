@@ -0,0 +1,106 @@
+package store
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// PendingTxStore keeps the broadcast destination transactions
+// until their receipt is observed
+type PendingTxStore struct {
+	db     Store
+	prefix string
+}
+
+func NewPendingTxStore(db Store, prefix string) *PendingTxStore {
+	return &PendingTxStore{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+func (ps *PendingTxStore) TotalCount() (uint64, error) {
+	return ps.getCountByKey(NewKey(ps.prefix))
+}
+
+func (ps *PendingTxStore) TotalCountByChain(nId string) (uint64, error) {
+	return ps.getCountByKey(NewKey(ps.prefix).AppendString(nId))
+}
+
+func (ps *PendingTxStore) getCountByKey(key []byte) (uint64, error) {
+	iter := ps.db.NewIterator(key)
+	count := 0
+	for iter.Next() {
+		count++
+	}
+	iter.Release()
+	err := iter.Error()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(count), nil
+}
+
+// pending transactions are stored based on destination nId
+func (ps *PendingTxStore) StorePendingTx(tx *types.PendingTransaction) error {
+	if tx == nil {
+		return fmt.Errorf("error while storing pending tx: tx cannot be nil")
+	}
+
+	key := ps.getKey(tx.MessageKey)
+
+	txByte, err := ps.Encode(tx)
+	if err != nil {
+		return err
+	}
+	return ps.db.SetByKey(key, txByte)
+}
+
+func (ps *PendingTxStore) GetPendingTx(messageKey *types.MessageKey) (*types.PendingTransaction, error) {
+	v, err := ps.db.GetByKey(ps.getKey(messageKey))
+	if err != nil {
+		return nil, err
+	}
+
+	var tx types.PendingTransaction
+	if err := ps.Decode(v, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// GetPendingTxs returns all the pending transactions sent to the destination nId
+func (ps *PendingTxStore) GetPendingTxs(nId string) ([]*types.PendingTransaction, error) {
+	var txs []*types.PendingTransaction
+
+	iter := ps.db.NewIterator(NewKey(ps.prefix).AppendString(nId))
+	defer iter.Release()
+
+	for iter.Next() {
+		var tx types.PendingTransaction
+		if err := ps.Decode(iter.Value(), &tx); err != nil {
+			return nil, err
+		}
+		txs = append(txs, &tx)
+	}
+	return txs, iter.Error()
+}
+
+func (ps *PendingTxStore) DeletePendingTx(messageKey *types.MessageKey) error {
+	return ps.db.DeleteByKey(ps.getKey(messageKey))
+}
+
+func (ps *PendingTxStore) getKey(messageKey *types.MessageKey) []byte {
+	return NewKey(ps.prefix).AppendString(messageKey.Dst).AppendString(messageKey.Src).AppendString(messageKey.EventType).AppendBigInt(messageKey.Sn)
+}
+
+func (ps *PendingTxStore) Encode(d interface{}) ([]byte, error) {
+	return jsoniter.Marshal(d)
+}
+
+func (ps *PendingTxStore) Decode(data []byte, output interface{}) error {
+	return jsoniter.Unmarshal(data, output)
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingTxStore(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/pendingtx")
+	if err != nil {
+		assert.Fail(t, "error while creating test db ", err)
+	}
+	defer testdb.Close()
+
+	if err := testdb.ClearStore(); err != nil {
+		assert.Fail(t, "failed to clear db ", err)
+	}
+
+	pendingTxStore := NewPendingTxStore(testdb, "pending")
+	key := types.NewMessageKey(big.NewInt(1), "icon", "archway", "emitMessage")
+	tx := types.NewPendingTransaction(types.NewMessagekeyWithMessageHeight(key, 100), "0xabc", 7)
+
+	t.Run("store pending tx", func(t *testing.T) {
+		assert.NoError(t, pendingTxStore.StorePendingTx(tx))
+
+		count, err := pendingTxStore.TotalCountByChain("archway")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), count)
+
+		count, err = pendingTxStore.TotalCountByChain("icon")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), count)
+	})
+
+	t.Run("get pending tx", func(t *testing.T) {
+		got, err := pendingTxStore.GetPendingTx(key)
+		assert.NoError(t, err)
+		assert.Equal(t, tx.TxHash, got.TxHash)
+		assert.Equal(t, tx.Nonce, got.Nonce)
+		assert.Equal(t, tx.Height, got.Height)
+
+		txs, err := pendingTxStore.GetPendingTxs("archway")
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+	})
+
+	t.Run("delete pending tx", func(t *testing.T) {
+		assert.NoError(t, pendingTxStore.DeletePendingTx(key))
+		_, err := pendingTxStore.GetPendingTx(key)
+		assert.Error(t, err)
+	})
+}
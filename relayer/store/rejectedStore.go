@@ -0,0 +1,113 @@
+package store
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+)
+
+// RejectedStore keeps the messages refused by the filter rules with the reason
+type RejectedStore struct {
+	db     Store
+	prefix string
+}
+
+func NewRejectedStore(db Store, prefix string) *RejectedStore {
+	return &RejectedStore{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+func (rs *RejectedStore) TotalCount() (uint, error) {
+	return rs.getCountByKey(NewKey(rs.prefix))
+}
+
+func (rs *RejectedStore) TotalCountByChain(nId string) (uint, error) {
+	return rs.getCountByKey(NewKey(rs.prefix).AppendString(nId))
+}
+
+func (rs *RejectedStore) getCountByKey(key []byte) (uint, error) {
+	iter := rs.db.NewIterator(key)
+	var count uint
+	for iter.Next() {
+		count++
+	}
+	iter.Release()
+	err := iter.Error()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// rejected messages are stored based on source nId
+func (rs *RejectedStore) StoreRejected(message *types.RejectedMessage) error {
+	if message == nil {
+		return fmt.Errorf("error while storing rejected message: message cannot be nil")
+	}
+
+	msgByte, err := rs.Encode(message)
+	if err != nil {
+		return err
+	}
+	return rs.db.SetByKey(rs.getKey(message.MessageKey()), msgByte)
+}
+
+func (rs *RejectedStore) GetRejected(messageKey *types.MessageKey) (*types.RejectedMessage, error) {
+	v, err := rs.db.GetByKey(rs.getKey(messageKey))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := new(types.RejectedMessage)
+	if err := rs.Decode(v, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// GetRejectedMessages returns the rejected messages of the source nId, all of them when nId is empty
+func (rs *RejectedStore) GetRejectedMessages(nId string, p *Pagination) ([]*types.RejectedMessage, error) {
+	var messages []*types.RejectedMessage
+
+	key := NewKey(rs.prefix)
+	if nId != "" {
+		key = key.AppendString(nId)
+	}
+	iter := rs.db.NewIterator(key)
+	defer iter.Release()
+
+	for i := uint(0); iter.Next(); i++ {
+		if !p.All && i < p.Offset {
+			continue
+		}
+		msg := new(types.RejectedMessage)
+		if err := rs.Decode(iter.Value(), msg); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+		if !p.All && uint(len(messages)) == p.Limit {
+			break
+		}
+	}
+	return messages, iter.Error()
+}
+
+func (rs *RejectedStore) DeleteRejected(messageKey *types.MessageKey) error {
+	return rs.db.DeleteByKey(rs.getKey(messageKey))
+}
+
+func (rs *RejectedStore) getKey(messageKey *types.MessageKey) []byte {
+	return NewKey(rs.prefix).AppendString(messageKey.Src).AppendString(messageKey.EventType).AppendBigInt(messageKey.Sn)
+}
+
+func (rs *RejectedStore) Encode(d interface{}) ([]byte, error) {
+	return jsoniter.Marshal(d)
+}
+
+func (rs *RejectedStore) Decode(data []byte, output interface{}) error {
+	return jsoniter.Unmarshal(data, output)
+}
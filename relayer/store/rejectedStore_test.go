@@ -0,0 +1,63 @@
+package store
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectedStore(t *testing.T) {
+	testdb, err := newTestDB(os.TempDir() + "/rejected")
+	if err != nil {
+		assert.Fail(t, "error while creating test db ", err)
+	}
+	defer testdb.Close()
+
+	if err := testdb.ClearStore(); err != nil {
+		assert.Fail(t, "failed to clear db ", err)
+	}
+
+	rejectedStore := NewRejectedStore(testdb, "rejected")
+	message := &types.Message{Src: "icon", Dst: "archway", Sn: big.NewInt(1), EventType: "emitMessage"}
+
+	t.Run("store rejected message", func(t *testing.T) {
+		assert.NoError(t, rejectedStore.StoreRejected(types.NewRejectedMessage(message, "dst archway is denied")))
+
+		count, err := rejectedStore.TotalCountByChain("icon")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), count)
+	})
+
+	t.Run("get rejected message", func(t *testing.T) {
+		rejected, err := rejectedStore.GetRejected(message.MessageKey())
+		assert.NoError(t, err)
+		assert.Equal(t, "dst archway is denied", rejected.Reason)
+		assert.Equal(t, message.Dst, rejected.Dst)
+
+		_, err = rejectedStore.GetRejected(types.NewMessageKey(big.NewInt(1), "icon", "archway", "callMessage"))
+		assert.Error(t, err)
+	})
+
+	t.Run("list rejected messages", func(t *testing.T) {
+		other := &types.Message{Src: "avalanche", Dst: "icon", Sn: big.NewInt(2), EventType: "emitMessage"}
+		assert.NoError(t, rejectedStore.StoreRejected(types.NewRejectedMessage(other, "payload too large")))
+
+		messages, err := rejectedStore.GetRejectedMessages("", NewPagination().GetAll())
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+
+		messages, err = rejectedStore.GetRejectedMessages("icon", NewPagination().GetAll())
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+	})
+
+	t.Run("delete rejected message", func(t *testing.T) {
+		assert.NoError(t, rejectedStore.DeleteRejected(message.MessageKey()))
+		count, err := rejectedStore.TotalCount()
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), count)
+	})
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// LegacySchemaVersion is the schema of the stores written before the version was recorded,
+	// their keys are the components joined with "-"
+	LegacySchemaVersion uint64 = 1
+	// KeySchemaVersion is the schema the length prefixed keys came with
+	KeySchemaVersion uint64 = 2
+	// SchemaVersion is the schema written by this relayer, it adds the counters and the
+	// secondary indexes of the messages
+	SchemaVersion uint64 = 3
+)
+
+// schemaKey holds the schema version of the store
+var schemaKey = NewKey("schema")
+
+// GetSchemaVersion returns the schema version of the store, the legacy version when
+// it holds data without a version record and the current one when it is empty
+func GetSchemaVersion(db Store) (uint64, error) {
+	v, err := db.GetByKey(schemaKey)
+	if err == nil {
+		return strconv.ParseUint(string(v), 10, 64)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return 0, err
+	}
+
+	iter := db.NewIterator(nil)
+	defer iter.Release()
+	if iter.Next() {
+		return LegacySchemaVersion, nil
+	}
+	return SchemaVersion, iter.Error()
+}
+
+// SetSchemaVersion records the schema version of the store
+func SetSchemaVersion(db KeyValueWriter, version uint64) error {
+	return db.SetByKey(schemaKey, []byte(strconv.FormatUint(version, 10)))
+}
+
+// CheckSchemaVersion fails unless the store is of the current schema,
+// an empty store is marked with the current version
+func CheckSchemaVersion(db Store) error {
+	version, err := GetSchemaVersion(db)
+	if err != nil {
+		return fmt.Errorf("failed to read the store schema version: %w", err)
+	}
+	switch {
+	case version < SchemaVersion:
+		return fmt.Errorf("store schema version %d is outdated, run the db migrate command to upgrade it to version %d", version, SchemaVersion)
+	case version > SchemaVersion:
+		return fmt.Errorf("store schema version %d is newer than the version %d of this relayer", version, SchemaVersion)
+	}
+	return SetSchemaVersion(db, SchemaVersion)
+}
@@ -1,21 +1,34 @@
 package store
 
 import (
+	"context"
 	"errors"
-
-	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
 
 var (
 	ErrNotFound = errors.New("key not found")
+	// ErrAmbiguousKey is returned by the lookups of a message key without its event type
+	// when the sn is stored under several event types
+	ErrAmbiguousKey = errors.New("several event types have the sn, the event type is required")
+	// ErrLocked is returned by TryLock when another relayer holds the lock of a shared database
+	ErrLocked = errors.New("the database is in use by another relayer")
+	// ErrLockLost is returned by the writes once the lock of a shared database is lost
+	ErrLockLost = errors.New("the database lock is lost")
 )
 
+// Store is the key value database of the relayer, every backend returns ErrNotFound
+// for a missing key and iterates the keys in bytewise order
 type Store interface {
 	KeyValueReader
 	KeyValueWriter
-	NewIterator(prefix []byte) iterator.Iterator
+	NewIterator(prefix []byte) Iterator
+	// NewRangeIterator iterates the keys from start, included, to limit, excluded,
+	// there is no upper bound when limit is nil
+	NewRangeIterator(start, limit []byte) Iterator
+	NewBatch() Batch
 	ClearStore() error
 	DeleteByKey(key []byte) error
+	Close() error
 }
 
 type KeyValueReader interface {
@@ -25,3 +38,42 @@ type KeyValueReader interface {
 type KeyValueWriter interface {
 	SetByKey(key []byte, value []byte) error
 }
+
+// Iterator walks the keys of a prefix in ascending order, it must be released once done
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Release()
+	Error() error
+}
+
+// Batch holds writes until Write applies them to the store at once, either all of them
+// are applied or none
+type Batch interface {
+	KeyValueWriter
+	DeleteByKey(key []byte) error
+	// Len is the number of writes held by the batch
+	Len() int
+	Write() error
+	Reset()
+}
+
+// TxWriter is implemented by the backends writing a batch in a database transaction,
+// the indexes of such a backend are read and written in the transaction of the batch
+type TxWriter interface {
+	// WriteTx runs fn in a transaction, committed when fn returns nil and rolled back
+	// otherwise, the reads of the writer see its writes
+	WriteTx(fn func(w IndexWriter) error) error
+}
+
+// Locker is implemented by the backends shared by several relayers, only the relayer
+// holding the lock of the database writes to it
+type Locker interface {
+	// Lock waits for the lock until ctx is done
+	Lock(ctx context.Context) error
+	// TryLock takes the lock or returns ErrLocked without waiting
+	TryLock() error
+	// Lost is closed when the lock is lost, the writes fail with ErrLockLost from then on
+	Lost() <-chan struct{}
+}
@@ -0,0 +1,173 @@
+// Package storetest checks that a store backend behaves as the relayer expects
+package storetest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run checks the reads, writes, iterators, batches and transactions of an empty store
+func Run(t *testing.T, db store.Store) {
+	t.Run("get set delete", func(t *testing.T) {
+		_, err := db.GetByKey([]byte("missing"))
+		assert.ErrorIs(t, err, store.ErrNotFound)
+
+		require.NoError(t, db.SetByKey([]byte("key"), []byte("value")))
+		value, err := db.GetByKey([]byte("key"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+
+		require.NoError(t, db.SetByKey([]byte("key"), []byte("updated")))
+		value, err = db.GetByKey([]byte("key"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("updated"), value)
+
+		require.NoError(t, db.DeleteByKey([]byte("key")))
+		_, err = db.GetByKey([]byte("key"))
+		assert.ErrorIs(t, err, store.ErrNotFound)
+
+		// index entries have an empty value
+		require.NoError(t, db.SetByKey([]byte("empty"), []byte{}))
+		value, err = db.GetByKey([]byte("empty"))
+		require.NoError(t, err)
+		assert.Empty(t, value)
+	})
+
+	t.Run("iterator", func(t *testing.T) {
+		require.NoError(t, db.ClearStore())
+		// more keys than a page of the page iterators
+		var want []string
+		for i := 0; i < store.DefaultPageSize+10; i++ {
+			key := fmt.Sprintf("message-icon-%04d", i)
+			want = append(want, key)
+			require.NoError(t, db.SetByKey([]byte(key), []byte(key)))
+		}
+		require.NoError(t, db.SetByKey([]byte("message-archway-1"), []byte("other")))
+		require.NoError(t, db.SetByKey([]byte("message-icon\xff"), []byte("other")))
+
+		iter := db.NewIterator([]byte("message-icon-"))
+		var got []string
+		for iter.Next() {
+			assert.Equal(t, iter.Key(), iter.Value())
+			got = append(got, string(iter.Key()))
+		}
+		iter.Release()
+		require.NoError(t, iter.Error())
+		assert.Equal(t, want, got)
+
+		// writing while iterating does not block
+		iter = db.NewIterator([]byte("message-icon-"))
+		for iter.Next() {
+			require.NoError(t, db.DeleteByKey(iter.Key()))
+		}
+		iter.Release()
+		require.NoError(t, iter.Error())
+
+		iter = db.NewIterator(nil)
+		var count int
+		for iter.Next() {
+			count++
+		}
+		iter.Release()
+		require.NoError(t, iter.Error())
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("range iterator", func(t *testing.T) {
+		require.NoError(t, db.ClearStore())
+		for i := 0; i < store.DefaultPageSize+10; i++ {
+			key := []byte(fmt.Sprintf("%04d", i))
+			require.NoError(t, db.SetByKey(key, key))
+		}
+
+		iter := db.NewRangeIterator([]byte("0010"), []byte("0020"))
+		var got []string
+		for iter.Next() {
+			got = append(got, string(iter.Key()))
+		}
+		iter.Release()
+		require.NoError(t, iter.Error())
+		assert.Len(t, got, 10)
+		assert.Equal(t, "0010", got[0])
+		assert.Equal(t, "0019", got[9])
+
+		// no upper bound
+		iter = db.NewRangeIterator([]byte("0010"), nil)
+		var count int
+		for iter.Next() {
+			count++
+		}
+		iter.Release()
+		require.NoError(t, iter.Error())
+		assert.Equal(t, store.DefaultPageSize, count)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		require.NoError(t, db.ClearStore())
+		require.NoError(t, db.SetByKey([]byte("deleted"), []byte("value")))
+
+		batch := db.NewBatch()
+		require.NoError(t, batch.SetByKey([]byte("first"), []byte("1")))
+		require.NoError(t, batch.SetByKey([]byte("second"), []byte("2")))
+		require.NoError(t, batch.DeleteByKey([]byte("deleted")))
+		assert.Equal(t, 3, batch.Len())
+
+		// nothing is written before Write
+		_, err := db.GetByKey([]byte("first"))
+		assert.ErrorIs(t, err, store.ErrNotFound)
+
+		require.NoError(t, batch.Write())
+		value, err := db.GetByKey([]byte("second"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("2"), value)
+		_, err = db.GetByKey([]byte("deleted"))
+		assert.ErrorIs(t, err, store.ErrNotFound)
+
+		batch.Reset()
+		assert.Equal(t, 0, batch.Len())
+	})
+
+	if tx, ok := db.(store.TxWriter); ok {
+		t.Run("transaction", func(t *testing.T) {
+			require.NoError(t, db.ClearStore())
+			require.NoError(t, db.SetByKey([]byte("counter"), []byte("1")))
+
+			require.NoError(t, tx.WriteTx(func(w store.IndexWriter) error {
+				value, err := w.GetByKey([]byte("counter"))
+				require.NoError(t, err)
+				require.NoError(t, w.SetByKey([]byte("counter"), append(value, '0')))
+				// the reads see the writes of the transaction
+				value, err = w.GetByKey([]byte("counter"))
+				require.NoError(t, err)
+				assert.Equal(t, []byte("10"), value)
+				return w.DeleteByKey([]byte("missing"))
+			}))
+			value, err := db.GetByKey([]byte("counter"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte("10"), value)
+
+			// nothing is written when fn fails
+			err = tx.WriteTx(func(w store.IndexWriter) error {
+				require.NoError(t, w.SetByKey([]byte("counter"), []byte("2")))
+				return fmt.Errorf("failed")
+			})
+			assert.Error(t, err)
+			value, err = db.GetByKey([]byte("counter"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte("10"), value)
+		})
+	}
+
+	t.Run("clear", func(t *testing.T) {
+		require.NoError(t, db.SetByKey([]byte("key"), []byte("value")))
+		require.NoError(t, db.ClearStore())
+		iter := db.NewIterator(nil)
+		assert.False(t, iter.Next())
+		iter.Release()
+		require.NoError(t, iter.Error())
+	})
+}
@@ -0,0 +1,64 @@
+package relayer
+
+import (
+	"github.com/icon-project/centralized-relay/relayer/store"
+	"github.com/icon-project/centralized-relay/relayer/types"
+	"go.uber.org/zap"
+)
+
+// storeTx stages the writes of a state change spanning several stores, they are written
+// at once by commit so that a crash never leaves the change half applied. The reads of
+// its stores do not see the staged writes.
+type storeTx struct {
+	batch        store.Batch
+	messages     *store.MessageStore
+	finality     *store.FinalityStore
+	pendingTxs   *store.PendingTxStore
+	deadLetters  *store.DeadLetterStore
+	delivered    *store.DeliveredStore
+	rejected     *store.RejectedStore
+	blockRecords *store.BlockRecordStore
+	history      *store.HistoryStore
+}
+
+// newStoreTx returns the stores of the relayer staging their writes in a new batch
+func (r *Relayer) newStoreTx() *storeTx {
+	batch := r.db.NewBatch()
+	db := store.WithBatch(r.db, batch)
+	return &storeTx{
+		batch:        batch,
+		messages:     store.NewMessageStore(db, prefixMessageStore),
+		finality:     store.NewFinalityStore(db, prefixFinalityStore),
+		pendingTxs:   store.NewPendingTxStore(db, prefixPendingTxStore),
+		deadLetters:  store.NewDeadLetterStore(db, prefixDeadLetterStore),
+		delivered:    store.NewDeliveredStore(db, prefixDeliveredStore),
+		rejected:     store.NewRejectedStore(db, prefixRejectedStore),
+		blockRecords: store.NewBlockRecordStore(db, prefixBlockRecord),
+		history:      store.NewHistoryStore(db, prefixHistoryStore),
+	}
+}
+
+// commit writes the staged writes at once
+func (tx *storeTx) commit() error {
+	return tx.batch.Write()
+}
+
+// stageTransition moves the message to the next lifecycle state and stages its persistence in tx
+func (r *Relayer) stageTransition(tx *storeTx, m *types.RouteMessage, status types.MessageStatus) error {
+	from := m.GetStatus()
+	if err := m.SetStatus(status); err != nil {
+		r.log.Error("message state transition rejected", zap.Any("message-key", m.MessageKey()), zap.Error(err))
+		return err
+	}
+	r.log.Debug("message state transition",
+		zap.String("src", m.Src),
+		zap.Uint64("sn", m.Sn.Uint64()),
+		zap.String("from", string(from)),
+		zap.String("to", string(status)),
+	)
+	if err := tx.messages.StoreMessage(m); err != nil {
+		r.log.Error("failed to persist message state", zap.Any("message-key", m.MessageKey()), zap.String("status", string(status)), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ListenerRestartPolicy is the backoff between the restarts of a failed chain listener
+var ListenerRestartPolicy = &RetryPolicy{
+	BaseDelay:    time.Second,
+	MaxDelay:     5 * time.Minute,
+	Jitter:       0.2,
+	RetryForever: boolPtr(true),
+}
+
+// ChainState is the health of a chain listener
+type ChainState string
+
+const (
+	// ChainStateHealthy is set while the listener delivers blocks
+	ChainStateHealthy ChainState = "healthy"
+	// ChainStateDegraded is set when the listener failed, until it delivers a block again
+	ChainStateDegraded ChainState = "degraded"
+)
+
+// ChainStatus is the listener health of a chain
+type ChainStatus struct {
+	Nid   string
+	State ChainState
+	// Restarts is the number of times the listener was restarted
+	Restarts  int
+	LastError string
+	// Since is when the chain entered the state
+	Since time.Time
+}
+
+// chainHealth guards the status of a chain runtime
+type chainHealth struct {
+	mu     sync.RWMutex
+	status ChainStatus
+	// attempt is the number of failures since the listener was last healthy, it drives the backoff
+	attempt uint8
+}
+
+// Status returns the listener health of the chain
+func (r *ChainRuntime) Status() ChainStatus {
+	r.health.mu.RLock()
+	defer r.health.mu.RUnlock()
+	status := r.health.status
+	status.Nid = r.Provider.NID()
+	return status
+}
+
+// IsDegraded reports whether the listener of the chain is failing
+func (r *ChainRuntime) IsDegraded() bool {
+	r.health.mu.RLock()
+	defer r.health.mu.RUnlock()
+	return r.health.status.State == ChainStateDegraded
+}
+
+// markDegraded records the listener failure and returns the delay before the restart
+func (r *ChainRuntime) markDegraded(err error) time.Duration {
+	r.health.mu.Lock()
+	defer r.health.mu.Unlock()
+	if r.health.status.State != ChainStateDegraded {
+		r.health.status.State = ChainStateDegraded
+		r.health.status.Since = time.Now()
+	}
+	r.health.status.LastError = err.Error()
+	delay := ListenerRestartPolicy.Delay(r.health.attempt)
+	if r.health.attempt < 255 {
+		r.health.attempt++
+	}
+	return delay
+}
+
+// markRestarted counts a listener restart
+func (r *ChainRuntime) markRestarted() {
+	r.health.mu.Lock()
+	defer r.health.mu.Unlock()
+	r.health.status.Restarts++
+}
+
+// markHealthy is called for every block delivered by the listener, it clears the degraded state
+func (r *ChainRuntime) markHealthy() {
+	r.health.mu.RLock()
+	healthy := r.health.status.State == ChainStateHealthy
+	r.health.mu.RUnlock()
+	if healthy {
+		return
+	}
+
+	r.health.mu.Lock()
+	defer r.health.mu.Unlock()
+	if r.health.status.State == ChainStateDegraded {
+		r.log.Info("chain listener recovered", zap.Int("restarts", r.health.status.Restarts))
+	}
+	r.health.status.State = ChainStateHealthy
+	r.health.status.LastError = ""
+	r.health.status.Since = time.Now()
+	r.health.attempt = 0
+}
+
+// superviseListener runs the listener of the chain and restarts it with backoff until ctx is done,
+// a failing chain is marked degraded and the other chains keep relaying
+func (r *Relayer) superviseListener(ctx context.Context, chain *ChainRuntime) {
+	for {
+		err := r.runListener(ctx, chain)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("listener stopped")
+		}
+		delay := chain.markDegraded(err)
+		chain.log.Error("chain listener failed, restarting",
+			zap.Error(err),
+			zap.Duration("delay", delay),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		chain.markRestarted()
+	}
+}
+
+// runListener runs the provider listener, a panic is returned as an error. Every run has its own
+// ctx, cancelled once the listener returns so that the goroutines it started stop before a restart.
+func (r *Relayer) runListener(ctx context.Context, chain *ChainRuntime) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("listener panic: %v", p)
+		}
+	}()
+	return chain.Provider.Listener(ctx, chain.LastSavedHeight(), chain.listenerChan)
+}
+
+// GetChainStatus returns the listener health of the chain or of all the chains when nId is empty
+func (r *Relayer) GetChainStatus(nId string) ([]ChainStatus, error) {
+	if nId != "" {
+		chain, err := r.FindChainRuntime(nId)
+		if err != nil {
+			return nil, err
+		}
+		return []ChainStatus{chain.Status()}, nil
+	}
+	chains := r.chainRuntimes()
+	statuses := make([]ChainStatus, 0, len(chains))
+	for _, chain := range chains {
+		statuses = append(statuses, chain.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Nid < statuses[j].Nid
+	})
+	return statuses, nil
+}
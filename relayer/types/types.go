@@ -17,13 +17,43 @@ var (
 	ConnectionContract       = "connection"
 	SupportedContracts       = []string{XcallContract, ConnectionContract}
 	RetryInterval            = 3*time.Second + RouteDuration
+	MaxAttemptHistory        = 10
 )
 
 type BlockInfo struct {
-	Height   uint64
+	Height uint64
+	// Hash of the block, empty for the chains whose blocks are final once committed
+	Hash     string
 	Messages []*Message
 }
 
+// BlockRecord tracks the messages emitted from a source block until the block is final
+type BlockRecord struct {
+	Height   uint64
+	Hash     string
+	Messages []*MessageKey
+}
+
+// AddMessages records the messages emitted from the block, once each
+func (b *BlockRecord) AddMessages(messages []*Message) {
+	for _, m := range messages {
+		key := m.MessageKey()
+		if b.hasMessage(key) {
+			continue
+		}
+		b.Messages = append(b.Messages, key)
+	}
+}
+
+func (b *BlockRecord) hasMessage(key *MessageKey) bool {
+	for _, k := range b.Messages {
+		if k.Src == key.Src && k.EventType == key.EventType && k.Sn.Cmp(key.Sn) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 type Message struct {
 	Dst           string   `json:"dst"`
 	Src           string   `json:"src"`
@@ -32,6 +62,10 @@ type Message struct {
 	MessageHeight uint64   `json:"messageHeight"`
 	EventType     string   `json:"eventType"`
 	ReqID         *big.Int `json:"reqID,omitempty"`
+	// From is the xcall source network address of a call message, as emitted by the chain
+	From string `json:"from,omitempty"`
+	// TxHash is the source transaction that emitted the message, empty when the chain does not report it
+	TxHash string `json:"txHash,omitempty"`
 }
 
 type ContractConfigMap map[string]string
@@ -67,16 +101,78 @@ func (m *Message) MessageKey() *MessageKey {
 	return NewMessageKey(m.Sn, m.Src, m.Dst, m.EventType)
 }
 
+// MessageStatus is the lifecycle state of a message being relayed
+type MessageStatus string
+
+const (
+	// MessageStatusDetected is set when the message is seen on the source chain
+	MessageStatusDetected MessageStatus = "detected"
+	// MessageStatusQueued is set when the message is picked up for routing
+	MessageStatusQueued MessageStatus = "queued"
+	// MessageStatusSubmitted is set before the destination transaction is broadcast
+	MessageStatusSubmitted MessageStatus = "submitted"
+	// MessageStatusConfirmed is set when the destination transaction succeeded
+	MessageStatusConfirmed MessageStatus = "confirmed"
+	// MessageStatusFinalized is set when the destination transaction is final
+	MessageStatusFinalized MessageStatus = "finalized"
+	// MessageStatusFailed is set when the message exhausted its retries
+	MessageStatusFailed MessageStatus = "failed"
+	// MessageStatusExpired is set when the message outlived the expiration window
+	MessageStatusExpired MessageStatus = "expired"
+)
+
+// messageTransitions lists the states reachable from each state
+var messageTransitions = map[MessageStatus][]MessageStatus{
+	MessageStatusDetected:  {MessageStatusQueued, MessageStatusFinalized, MessageStatusExpired},
+	MessageStatusQueued:    {MessageStatusDetected, MessageStatusSubmitted, MessageStatusFinalized, MessageStatusExpired},
+	MessageStatusSubmitted: {MessageStatusDetected, MessageStatusConfirmed, MessageStatusFailed, MessageStatusFinalized},
+	MessageStatusConfirmed: {MessageStatusDetected, MessageStatusFinalized},
+	MessageStatusFailed:    {MessageStatusDetected, MessageStatusExpired},
+	// an expired message only comes back when it is requeued from the dead letter queue
+	MessageStatusExpired: {MessageStatusDetected},
+}
+
+// CanTransition reports whether the lifecycle allows moving from s to next
+func (s MessageStatus) CanTransition(next MessageStatus) bool {
+	for _, to := range messageTransitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether the message has left the relay pipeline
+func (s MessageStatus) IsTerminal() bool {
+	return s == MessageStatusFinalized || s == MessageStatusExpired
+}
+
+// RouteMessage is a message in the relay pipeline, its state is shared by the
+// router, the callbacks and the processors so it must be changed through its methods
 type RouteMessage struct {
 	*Message
 	Retry      uint8
-	Processing bool
+	Status     MessageStatus
 	LastTry    time.Time
+	DetectedAt time.Time
+	UpdatedAt  time.Time
+	Attempts   []*DeliveryAttempt `json:",omitempty"`
+
+	mu sync.Mutex
+}
+
+// DeliveryAttempt records the outcome of a failed delivery try
+type DeliveryAttempt struct {
+	Retry  uint8
+	TxHash string `json:",omitempty"`
+	Error  string
+	Time   time.Time
 }
 
 func NewRouteMessage(m *Message) *RouteMessage {
 	return &RouteMessage{
 		Message: m,
+		Status:  MessageStatusDetected,
 	}
 }
 
@@ -84,36 +180,173 @@ func (r *RouteMessage) GetMessage() *Message {
 	return r.Message
 }
 
+// Clone returns a consistent copy of the message, safe to encode or log
+// while the original keeps changing
+func (r *RouteMessage) Clone() *RouteMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var attempts []*DeliveryAttempt
+	if len(r.Attempts) > 0 {
+		attempts = append(attempts, r.Attempts...)
+	}
+	return &RouteMessage{
+		Message:    r.Message,
+		Retry:      r.Retry,
+		Status:     r.Status,
+		LastTry:    r.LastTry,
+		DetectedAt: r.DetectedAt,
+		UpdatedAt:  r.UpdatedAt,
+		Attempts:   attempts,
+	}
+}
+
+// IncrementRetry counts a try, the count saturates for messages retried forever
 func (r *RouteMessage) IncrementRetry() {
-	r.Retry++
-	r.AddNextTry()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Retry < math.MaxUint8 {
+		r.Retry++
+	}
+	r.addNextTry()
+}
+
+// ResetRetry gives the message a fresh set of tries
+func (r *RouteMessage) ResetRetry() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Retry = 0
+	r.LastTry = time.Time{}
 }
 
-func (r *RouteMessage) ToggleProcessing() {
-	r.Processing = !r.Processing
+// SetStatus moves the message to the given state if the lifecycle allows it
+func (r *RouteMessage) SetStatus(status MessageStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.status()
+	if current != status && !current.CanTransition(status) {
+		return fmt.Errorf("invalid message transition: %s -> %s", current, status)
+	}
+	r.Status = status
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// GetStatus returns the lifecycle state, messages stored before the lifecycle
+// was introduced are treated as detected
+func (r *RouteMessage) GetStatus() MessageStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status()
+}
+
+func (r *RouteMessage) status() MessageStatus {
+	if r.Status == "" {
+		return MessageStatusDetected
+	}
+	return r.Status
 }
 
 func (r *RouteMessage) GetRetry() uint8 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return r.Retry
 }
 
+// GetLastTry returns the time of the next try, zero when the message was never held
+func (r *RouteMessage) GetLastTry() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.LastTry
+}
+
+// GetDetectedAt returns when the message was detected,
+// messages stored before it was recorded fall back to their last state change
+func (r *RouteMessage) GetDetectedAt() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.DetectedAt.IsZero() {
+		return r.UpdatedAt
+	}
+	return r.DetectedAt
+}
+
+// AddAttempt records a failed try, only the latest MaxAttemptHistory tries are kept
+func (r *RouteMessage) AddAttempt(txHash string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt := &DeliveryAttempt{Retry: r.Retry, TxHash: txHash, Time: time.Now()}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	r.Attempts = append(r.Attempts, attempt)
+	if len(r.Attempts) > MaxAttemptHistory {
+		r.Attempts = r.Attempts[len(r.Attempts)-MaxAttemptHistory:]
+	}
+}
+
+// LastError returns the error of the latest failed try
+func (r *RouteMessage) LastError() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.Attempts) == 0 {
+		return ""
+	}
+	return r.Attempts[len(r.Attempts)-1].Error
+}
+
 // ResetLastTry resets the last try time to the current time plus the retry interval
 func (r *RouteMessage) AddNextTry() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addNextTry()
+}
+
+func (r *RouteMessage) addNextTry() {
 	r.LastTry = time.Now().Add(RetryInterval * time.Duration(math.Pow(2, float64(r.Retry)))) // exponential backoff
 }
 
+// SetNextTry holds the message for the given delay
+func (r *RouteMessage) SetNextTry(delay time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.LastTry = time.Now().Add(delay)
+}
+
+// ClearNextTry makes the message eligible right away
+func (r *RouteMessage) ClearNextTry() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.LastTry = time.Time{}
+}
+
+// IsProcessing reports whether the message is in flight or waiting for its next try
 func (r *RouteMessage) IsProcessing() bool {
-	return r.Processing || !(r.LastTry.IsZero() || r.LastTry.Before(time.Now()))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch r.status() {
+	case MessageStatusQueued, MessageStatusSubmitted, MessageStatusConfirmed:
+		return true
+	}
+	return !(r.LastTry.IsZero() || r.LastTry.Before(time.Now()))
 }
 
 // stale means message which is expired
 func (r *RouteMessage) IsStale() bool {
-	return r.Retry >= StaleMarkCount
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status() == MessageStatusFailed || r.Retry >= StaleMarkCount
 }
 
-// IsElasped checks if the last try is elasped by the duration
+// IsElasped checks if the last try is elasped by the duration,
+// messages never tried are measured from their last state change
 func (r *RouteMessage) IsElasped(duration time.Duration) bool {
-	return r.LastTry.Add(duration).Before(time.Now())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := r.LastTry
+	if last.IsZero() {
+		last = r.UpdatedAt
+	}
+	return last.Add(duration).Before(time.Now())
 }
 
 type TxResponseFunc func(key *MessageKey, response *TxResponse, err error)
@@ -121,9 +354,32 @@ type TxResponseFunc func(key *MessageKey, response *TxResponse, err error)
 type TxResponse struct {
 	Height    int64
 	TxHash    string
+	Nonce     uint64
 	Codespace string
 	Code      ResponseCode
 	Data      string
+	// GasUsed and Fee are what the executed transaction cost, the fee in the smallest denomination
+	// of the chain, they are zero when the chain does not report them
+	GasUsed uint64
+	Fee     uint64
+}
+
+// Share returns the response of the i-th of the n messages delivered by the transaction, the
+// cost of the transaction is split evenly between them and the remainder goes to the first ones
+func (r *TxResponse) Share(i, n int) *TxResponse {
+	res := *r
+	if n > 1 {
+		res.GasUsed, res.Fee = share(r.GasUsed, i, n), share(r.Fee, i, n)
+	}
+	return &res
+}
+
+func share(total uint64, i, n int) uint64 {
+	part := total / uint64(n)
+	if uint64(i) < total%uint64(n) {
+		part++
+	}
+	return part
 }
 
 type ResponseCode uint8
@@ -131,6 +387,8 @@ type ResponseCode uint8
 const (
 	Failed  ResponseCode = 0
 	Success ResponseCode = 1
+	// Pending is reported once the transaction is broadcast, before its receipt is awaited
+	Pending ResponseCode = 2
 )
 
 type MessageKey struct {
@@ -153,57 +411,113 @@ func NewMessagekeyWithMessageHeight(key *MessageKey, height uint64) *MessageKeyW
 	return &MessageKeyWithMessageHeight{key, height}
 }
 
+// MessageCache holds the messages of a source chain being relayed, it is safe for concurrent use.
+// Messages are keyed by the value of their key so that a message loaded again from the store
+// matches the cached one.
 type MessageCache struct {
-	Messages map[MessageKey]*RouteMessage
-	*sync.RWMutex
+	messages map[string]*RouteMessage
+	mu       sync.RWMutex
 }
 
 func NewMessageCache() *MessageCache {
 	return &MessageCache{
-		Messages: make(map[MessageKey]*RouteMessage),
-		RWMutex:  new(sync.RWMutex),
+		messages: make(map[string]*RouteMessage),
 	}
 }
 
+func cacheKey(key *MessageKey) string {
+	return fmt.Sprintf("%s-%s-%s-%s", key.Src, key.Dst, key.Sn, key.EventType)
+}
+
 func (m *MessageCache) Add(r *RouteMessage) {
-	m.Lock()
-	defer m.Unlock()
-	m.Messages[*r.MessageKey()] = r
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages[cacheKey(r.MessageKey())] = r
 }
 
 func (m *MessageCache) Len() int {
-	return len(m.Messages)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.messages)
 }
 
 func (m *MessageCache) Remove(key *MessageKey) {
-	m.Lock()
-	defer m.Unlock()
-	delete(m.Messages, *key)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.messages, cacheKey(key))
 }
 
 // Get returns the message from the cache
 func (m *MessageCache) Get(key *MessageKey) (*RouteMessage, bool) {
-	m.RLock()
-	defer m.RUnlock()
-	msg, ok := m.Messages[*key]
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	msg, ok := m.messages[cacheKey(key)]
 	return msg, ok
 }
 
+// Snapshot returns the cached messages at the time of the call
+func (m *MessageCache) Snapshot() []*RouteMessage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	messages := make([]*RouteMessage, 0, len(m.messages))
+	for _, msg := range m.messages {
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// Range calls fn for every message of a snapshot until fn returns false,
+// fn may change the cache
+func (m *MessageCache) Range(fn func(*RouteMessage) bool) {
+	for _, msg := range m.Snapshot() {
+		if !fn(msg) {
+			return
+		}
+	}
+}
+
+// Update calls fn on the cached message under the cache lock,
+// it reports whether the message was found
+func (m *MessageCache) Update(key *MessageKey, fn func(*RouteMessage)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	msg, ok := m.messages[cacheKey(key)]
+	if ok {
+		fn(msg)
+	}
+	return ok
+}
+
+// Coin is an amount in the smallest denomination of a chain, the balances of the 18 decimal
+// chains are beyond the range of an uint64
 type Coin struct {
 	Denom  string
-	Amount uint64
+	Amount *big.Int
 }
 
-func NewCoin(denom string, amount uint64) *Coin {
+func NewCoin(denom string, amount *big.Int) *Coin {
 	return &Coin{strings.ToLower(denom), amount}
 }
 
 func (c *Coin) String() string {
-	return fmt.Sprintf("%d%s", c.Amount, c.Denom)
+	return c.amount().String() + c.Denom
+}
+
+// Float64 returns the nearest float of the amount, for the metrics
+func (c *Coin) Float64() float64 {
+	value, _ := new(big.Float).SetInt(c.amount()).Float64()
+	return value
+}
+
+func (c *Coin) amount() *big.Int {
+	if c.Amount == nil {
+		return new(big.Int)
+	}
+	return c.Amount
 }
 
 func (c *Coin) Calculate() string {
-	balance := new(big.Float).SetUint64(c.Amount)
+	balance := new(big.Float).SetInt(c.amount())
 	amount := balance.Quo(balance, big.NewFloat(1e18))
 	value, _ := amount.Float64()
 	return fmt.Sprintf("%.18f %s", value, c.Denom)
@@ -213,10 +527,131 @@ type TransactionObject struct {
 	*MessageKeyWithMessageHeight
 	TxHash   string
 	TxHeight uint64
+	// GasUsed and Fee are the cost of the transaction, kept for the history record of the message
+	GasUsed uint64 `json:",omitempty"`
+	Fee     uint64 `json:",omitempty"`
 }
 
 func NewTransactionObject(messageKey *MessageKeyWithMessageHeight, txHash string, height uint64) *TransactionObject {
-	return &TransactionObject{messageKey, txHash, height}
+	return &TransactionObject{MessageKeyWithMessageHeight: messageKey, TxHash: txHash, TxHeight: height}
+}
+
+// DeadLetter is a message that exhausted its retries, kept for investigation
+type DeadLetter struct {
+	*RouteMessage
+	LastError string
+	FailedAt  time.Time
+}
+
+func NewDeadLetter(m *RouteMessage) *DeadLetter {
+	return &DeadLetter{m.Clone(), m.LastError(), time.Now()}
+}
+
+// RejectedMessage is a message refused by the filter rules or the profitability check of the relayer
+type RejectedMessage struct {
+	*Message
+	Reason     string
+	RejectedAt time.Time
+}
+
+func NewRejectedMessage(m *Message, reason string) *RejectedMessage {
+	return &RejectedMessage{m, reason, time.Now()}
+}
+
+// FeeChange is a fee update of a route computed by the fee adjuster
+type FeeChange struct {
+	Src       string
+	Dst       string
+	OldMsgFee uint64
+	NewMsgFee uint64
+	OldResFee uint64
+	NewResFee uint64
+	// Cost is the average delivery cost the message fee is computed from, in the destination denomination
+	Cost uint64
+	// DryRun is set when the change was only computed
+	DryRun bool
+	Error  string `json:",omitempty"`
+	At     time.Time
+}
+
+// FeeClaim is a claim of the fees accrued on a connection made by the fee claimer
+type FeeClaim struct {
+	Nid string
+	// Amount is the fee credited by the claim, or the claimable fee when the claim failed
+	Amount *big.Int
+	// Treasury the claimed fees were swept to, empty when they were kept
+	Treasury   string `json:",omitempty"`
+	Error      string `json:",omitempty"`
+	SweepError string `json:",omitempty"`
+	At         time.Time
+}
+
+// DeliveredMessage records the transaction that delivered a message
+type DeliveredMessage struct {
+	*MessageKey
+	TxHash      string `json:",omitempty"`
+	DeliveredAt time.Time
+}
+
+func NewDeliveredMessage(key *MessageKey, txHash string) *DeliveredMessage {
+	return &DeliveredMessage{key, txHash, time.Now()}
+}
+
+// RelayRecord is the history record of a delivered message, kept once the message is cleared
+type RelayRecord struct {
+	*MessageKey
+	// SrcTxHash and SrcHeight locate the emission of the message on the source chain
+	SrcTxHash string `json:",omitempty"`
+	SrcHeight uint64
+	// DstTxHash and DstHeight locate the delivery on the destination chain,
+	// they are empty when the delivery was observed rather than made by the relayer
+	DstTxHash string `json:",omitempty"`
+	DstHeight uint64 `json:",omitempty"`
+	// GasUsed and Fee are the cost of the destination transaction, the fee in the smallest
+	// denomination of the destination, the messages of a batch transaction each record their share
+	GasUsed uint64 `json:",omitempty"`
+	Fee     uint64 `json:",omitempty"`
+	Retry   uint8
+	// Latency is the time from the detection of the message to its delivery
+	Latency     time.Duration
+	DetectedAt  time.Time
+	DeliveredAt time.Time
+}
+
+// NewRelayRecord returns the history record of the message delivered by the transaction of res,
+// res is nil when the delivery was observed on the destination
+func NewRelayRecord(m *RouteMessage, res *TxResponse) *RelayRecord {
+	m = m.Clone()
+	record := &RelayRecord{
+		MessageKey:  m.MessageKey(),
+		SrcTxHash:   m.TxHash,
+		SrcHeight:   m.MessageHeight,
+		Retry:       m.Retry,
+		DetectedAt:  m.DetectedAt,
+		DeliveredAt: time.Now(),
+	}
+	if !m.DetectedAt.IsZero() {
+		record.Latency = record.DeliveredAt.Sub(m.DetectedAt)
+	}
+	if res != nil {
+		record.DstTxHash = res.TxHash
+		record.DstHeight = uint64(res.Height)
+		record.GasUsed = res.GasUsed
+		record.Fee = res.Fee
+	}
+	return record
+}
+
+// PendingTransaction is the write-ahead record of a broadcast destination transaction
+type PendingTransaction struct {
+	*MessageKeyWithMessageHeight
+	TxHash      string
+	Nonce       uint64
+	SubmittedAt time.Time
+}
+
+func NewPendingTransaction(messageKey *MessageKeyWithMessageHeight, txHash string, nonce uint64) *PendingTransaction {
+	return &PendingTransaction{messageKey, txHash, nonce, time.Now()}
 }
 
 type Receipt struct {
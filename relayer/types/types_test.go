@@ -2,6 +2,7 @@ package types
 
 import (
 	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,10 +18,16 @@ func TestRouteMessage(t *testing.T) {
 	routeMessage := NewRouteMessage(m1)
 
 	t.Run("route message set processing", func(t *testing.T) {
-		routeMessage.ToggleProcessing()
+		assert.Equal(t, MessageStatusDetected, routeMessage.GetStatus())
+		assert.NoError(t, routeMessage.SetStatus(MessageStatusQueued))
 		assert.Equal(t, true, routeMessage.IsProcessing())
 	})
 
+	t.Run("route message invalid transition", func(t *testing.T) {
+		assert.Error(t, routeMessage.SetStatus(MessageStatusConfirmed))
+		assert.Equal(t, MessageStatusQueued, routeMessage.GetStatus())
+	})
+
 	t.Run("route message increment retry", func(t *testing.T) {
 		routeMessage.IncrementRetry()
 		assert.Equal(t, uint8(1), routeMessage.GetRetry())
@@ -45,8 +52,84 @@ func TestMessageCache(t *testing.T) {
 		assert.Equal(t, messageCache.Len(), int(1))
 	})
 
+	t.Run("message cache get by equal key", func(t *testing.T) {
+		_, ok := messageCache.Get(NewMessageKey(big.NewInt(1), "mock-1", "mock-2", ""))
+		assert.True(t, ok)
+	})
+
+	t.Run("message cache update", func(t *testing.T) {
+		ok := messageCache.Update(m1.MessageKey(), func(m *RouteMessage) {
+			m.IncrementRetry()
+		})
+		assert.True(t, ok)
+		msg, _ := messageCache.Get(m1.MessageKey())
+		assert.Equal(t, uint8(1), msg.GetRetry())
+	})
+
+	t.Run("message cache snapshot", func(t *testing.T) {
+		snapshot := messageCache.Snapshot()
+		assert.Len(t, snapshot, 1)
+		messageCache.Range(func(m *RouteMessage) bool {
+			// the cache can change while it is ranged over
+			messageCache.Remove(m.MessageKey())
+			return true
+		})
+		assert.Len(t, snapshot, 1)
+		assert.Equal(t, 0, messageCache.Len())
+	})
+
 	t.Run("message removed from cache", func(t *testing.T) {
+		messageCache.Add(NewRouteMessage(m1))
 		messageCache.Remove(m1.MessageKey())
 		assert.Equal(t, messageCache.Len(), int(0))
 	})
 }
+
+func TestMessageCacheConcurrency(t *testing.T) {
+	messageCache := NewMessageCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for sn := int64(0); sn < 100; sn++ {
+				m := NewRouteMessage(&Message{Src: "mock-1", Dst: "mock-2", Sn: big.NewInt(sn)})
+				switch i % 4 {
+				case 0:
+					messageCache.Add(m)
+				case 1:
+					messageCache.Range(func(m *RouteMessage) bool {
+						m.IsProcessing()
+						return true
+					})
+				case 2:
+					messageCache.Update(m.MessageKey(), func(m *RouteMessage) {
+						m.IncrementRetry()
+						m.SetStatus(MessageStatusQueued)
+					})
+				case 3:
+					messageCache.Remove(m.MessageKey())
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestTxResponseShare(t *testing.T) {
+	res := &TxResponse{TxHash: "0x1", Code: Success, GasUsed: 100, Fee: 1000}
+
+	var gas, fee uint64
+	for i := 0; i < 3; i++ {
+		share := res.Share(i, 3)
+		assert.Equal(t, res.TxHash, share.TxHash)
+		gas += share.GasUsed
+		fee += share.Fee
+	}
+	assert.Equal(t, uint64(34), res.Share(0, 3).GasUsed)
+	assert.Equal(t, uint64(33), res.Share(2, 3).GasUsed)
+	assert.Equal(t, res.GasUsed, gas)
+	assert.Equal(t, res.Fee, fee)
+	assert.Equal(t, res, res.Share(0, 1))
+}